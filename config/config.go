@@ -2,64 +2,248 @@ package config
 
 import (
 	"briefcash-transfer/internal/helper/loghelper"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 )
 
+// Config is loaded from defaults, then optional yaml file, then environment variables.
+// Field tags: yaml key, env variable, default value, validate rules, and secret for redacted value
+// that can also be read from file named by <ENV>_FILE.
 type Config struct {
-	DBHost     string
-	DBPort     string
-	DBUsername string
-	DBPassword string
-	DBName     string
-	AppPort    string
-	RedisHost  string
-	RedisPort  string
-	KafkaHost  string
-	KafkaPort  string
-	KafkaTopic string
+	DBHost            string        `yaml:"db_host" env:"DB_URL" validate:"required"`
+	DBPort            int           `yaml:"db_port" env:"DB_PORT" default:"5432" validate:"port"`
+	DBUsername        string        `yaml:"db_username" env:"DB_USERNAME" validate:"required"`
+	DBPassword        string        `yaml:"db_password" env:"DB_PASSWORD" validate:"required" secret:"true"`
+	DBName            string        `yaml:"db_name" env:"DB_NAME" validate:"required"`
+	DBSslMode         string        `yaml:"db_ssl_mode" env:"DB_SSL_MODE" default:"disable" validate:"oneof=disable allow prefer require verify-ca verify-full"`
+	DBMaxOpenConns    int           `yaml:"db_max_open_conns" env:"DB_MAX_OPEN_CONNS" default:"100" validate:"positive"`
+	DBMaxIdleConns    int           `yaml:"db_max_idle_conns" env:"DB_MAX_IDLE_CONNS" default:"10" validate:"nonnegative"`
+	DBConnMaxLifetime time.Duration `yaml:"db_conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" default:"1h" validate:"positive"`
+
+	AppPort string `yaml:"app_port" env:"APP_PORT" default:":8080" validate:"address"`
+
+	// readiness reports draining for pre stop delay before http listener closes, so load balancer stops routing first
+	ShutdownPreStopDelay time.Duration `yaml:"shutdown_pre_stop_delay" env:"SHUTDOWN_PRE_STOP_DELAY" default:"5s" validate:"nonnegative"`
+
+	// each shutdown phase is bounded separately, phase that overruns is logged and shutdown moves on
+	ShutdownHttpTimeout     time.Duration `yaml:"shutdown_http_timeout" env:"SHUTDOWN_HTTP_TIMEOUT" default:"10s" validate:"positive"`
+	ShutdownWorkerTimeout   time.Duration `yaml:"shutdown_worker_timeout" env:"SHUTDOWN_WORKER_TIMEOUT" default:"30s" validate:"positive"`
+	ShutdownTransferTimeout time.Duration `yaml:"shutdown_transfer_timeout" env:"SHUTDOWN_TRANSFER_TIMEOUT" default:"15s" validate:"positive"`
+	ShutdownKafkaTimeout    time.Duration `yaml:"shutdown_kafka_timeout" env:"SHUTDOWN_KAFKA_TIMEOUT" default:"5s" validate:"positive"`
+	ShutdownTraceTimeout    time.Duration `yaml:"shutdown_trace_timeout" env:"SHUTDOWN_TRACE_TIMEOUT" default:"5s" validate:"positive"`
+	ShutdownStorageTimeout  time.Duration `yaml:"shutdown_storage_timeout" env:"SHUTDOWN_STORAGE_TIMEOUT" default:"5s" validate:"positive"`
+
+	RedisHost         string        `yaml:"redis_host" env:"REDIS_ADDRESS" validate:"required"`
+	RedisPort         int           `yaml:"redis_port" env:"REDIS_PORT" default:"6379" validate:"port"`
+	RedisPassword     string        `yaml:"redis_password" env:"REDIS_PASSWORD" secret:"true"`
+	RedisDB           int           `yaml:"redis_db" env:"REDIS_DB" default:"0" validate:"nonnegative"`
+	RedisPoolSize     int           `yaml:"redis_pool_size" env:"REDIS_POOL_SIZE" default:"50" validate:"positive"`
+	RedisMinIdleConns int           `yaml:"redis_min_idle_conns" env:"REDIS_MIN_IDLE_CONNS" default:"10" validate:"nonnegative"`
+	RedisReadTimeout  time.Duration `yaml:"redis_read_timeout" env:"REDIS_READ_TIMEOUT" default:"3s" validate:"positive"`
+	RedisWriteTimeout time.Duration `yaml:"redis_write_timeout" env:"REDIS_WRITE_TIMEOUT" default:"3s" validate:"positive"`
+	LockExpiry        time.Duration `yaml:"lock_expiry" env:"LOCK_EXPIRY" default:"300ms" validate:"positive"`
+	LockTries         int           `yaml:"lock_tries" env:"LOCK_TRIES" default:"2" validate:"positive"`
+
+	KafkaHost            string        `yaml:"kafka_host" env:"KAFKA_HOST"`
+	KafkaPort            int           `yaml:"kafka_port" env:"KAFKA_PORT" default:"9092" validate:"port"`
+	KafkaBrokerList      string        `yaml:"kafka_brokers" env:"KAFKA_BROKERS"`
+	KafkaClientId        string        `yaml:"kafka_client_id" env:"KAFKA_CLIENT_ID" default:"briefcash-transfer" validate:"required"`
+	KafkaRetryMax        int           `yaml:"kafka_retry_max" env:"KAFKA_RETRY_MAX" default:"3" validate:"nonnegative"`
+	KafkaRetryBackoff    time.Duration `yaml:"kafka_retry_backoff" env:"KAFKA_RETRY_BACKOFF" default:"100ms" validate:"positive"`
+	KafkaProducerTimeout time.Duration `yaml:"kafka_producer_timeout" env:"KAFKA_PRODUCER_TIMEOUT" default:"5s" validate:"positive"`
+	KafkaNetTimeout      time.Duration `yaml:"kafka_net_timeout" env:"KAFKA_NET_TIMEOUT" default:"5s" validate:"positive"`
+	KafkaProducerMode    string        `yaml:"kafka_producer_mode" env:"KAFKA_PRODUCER_MODE" default:"sync" validate:"oneof=sync async"`
+	KafkaAsyncBufferSize int           `yaml:"kafka_async_buffer_size" env:"KAFKA_ASYNC_BUFFER_SIZE" default:"1000" validate:"positive"`
+
+	KafkaDeadLetterTopic string `yaml:"kafka_dead_letter_topic" env:"KAFKA_DEAD_LETTER_TOPIC"`
+
+	// consumed message still failing after max attempts is dead lettered, it is redelivered instead when there is no dead letter topic
+	KafkaConsumerMaxAttempts  int           `yaml:"kafka_consumer_max_attempts" env:"KAFKA_CONSUMER_MAX_ATTEMPTS" default:"5" validate:"positive"`
+	KafkaConsumerRetryBackoff time.Duration `yaml:"kafka_consumer_retry_backoff" env:"KAFKA_CONSUMER_RETRY_BACKOFF" default:"1s" validate:"positive"`
+
+	// security setting is shared by producer, consumers and dead letter reader
+	KafkaSaslMechanism string `yaml:"kafka_sasl_mechanism" env:"KAFKA_SASL_MECHANISM" default:"none" validate:"oneof=none plain scram-sha-256 scram-sha-512"`
+	KafkaSaslUsername  string `yaml:"kafka_sasl_username" env:"KAFKA_SASL_USERNAME"`
+	KafkaSaslPassword  string `yaml:"kafka_sasl_password" env:"KAFKA_SASL_PASSWORD" secret:"true"`
+	KafkaTlsEnabled    bool   `yaml:"kafka_tls_enabled" env:"KAFKA_TLS_ENABLED" default:"false"`
+	KafkaTlsCaFile     string `yaml:"kafka_tls_ca_file" env:"KAFKA_TLS_CA_FILE"`
+	KafkaTlsCertFile   string `yaml:"kafka_tls_cert_file" env:"KAFKA_TLS_CERT_FILE"`
+	KafkaTlsKeyFile    string `yaml:"kafka_tls_key_file" env:"KAFKA_TLS_KEY_FILE"`
+	KafkaTlsServerName string `yaml:"kafka_tls_server_name" env:"KAFKA_TLS_SERVER_NAME"`
+
+	KafkaDepositTopic string `yaml:"kafka_deposit_topic" env:"KAFKA_DEPOSIT_TOPIC"`
+	KafkaDepositGroup string `yaml:"kafka_deposit_group" env:"KAFKA_DEPOSIT_GROUP" default:"briefcash-transfer-deposit" validate:"required"`
+
+	KafkaReversalTopic string `yaml:"kafka_reversal_topic" env:"KAFKA_REVERSAL_TOPIC"`
+	KafkaReversalGroup string `yaml:"kafka_reversal_group" env:"KAFKA_REVERSAL_GROUP" default:"briefcash-transfer-reversal" validate:"required"`
+
+	InternalApiKey string `yaml:"internal_api_key" env:"INTERNAL_API_KEY" secret:"true"`
+	// AdminApiKeys is comma separated admin-id:key pairs, admin action is attributed to admin whose key matched
+	AdminApiKeys string `yaml:"admin_api_keys" env:"ADMIN_API_KEYS" secret:"true"`
+
+	BatchConcurrency int `yaml:"batch_concurrency" env:"BATCH_CONCURRENCY" default:"10" validate:"positive"`
+	BatchMaxRows     int `yaml:"batch_max_rows" env:"BATCH_MAX_ROWS" default:"5000" validate:"positive"`
+
+	SchedulerInterval      time.Duration `yaml:"scheduler_interval" env:"SCHEDULER_INTERVAL" default:"30s" validate:"positive"`
+	ScheduleClaimTimeout   time.Duration `yaml:"schedule_claim_timeout" env:"SCHEDULE_CLAIM_TIMEOUT" default:"5m" validate:"positive"`
+	KafkaNotificationTopic string        `yaml:"kafka_notification_topic" env:"KAFKA_NOTIFICATION_TOPIC"`
+
+	KafkaStatusTopic   string        `yaml:"kafka_status_topic" env:"KAFKA_STATUS_TOPIC"`
+	KafkaStatusGroup   string        `yaml:"kafka_status_group" env:"KAFKA_STATUS_GROUP" default:"briefcash-transfer-status" validate:"required"`
+	WebhookInterval    time.Duration `yaml:"webhook_interval" env:"WEBHOOK_INTERVAL" default:"10s" validate:"positive"`
+	WebhookMaxAttempts int           `yaml:"webhook_max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" default:"8" validate:"positive"`
+	WebhookBackoffBase time.Duration `yaml:"webhook_backoff_base" env:"WEBHOOK_BACKOFF_BASE" default:"30s" validate:"positive"`
+
+	TraceExporter    string  `yaml:"trace_exporter" env:"TRACE_EXPORTER" default:"none" validate:"oneof=none stdout otlp"`
+	TraceEndpoint    string  `yaml:"trace_endpoint" env:"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"`
+	TraceSampleRatio float64 `yaml:"trace_sample_ratio" env:"TRACE_SAMPLE_RATIO" default:"1" validate:"ratio"`
+
+	VAInquiryTimeout     time.Duration `yaml:"va_inquiry_timeout" env:"VA_INQUIRY_TIMEOUT" default:"10s" validate:"positive"`
+	WalletInquiryTimeout time.Duration `yaml:"wallet_inquiry_timeout" env:"WALLET_INQUIRY_TIMEOUT" default:"10s" validate:"positive"`
+
+	ResponseCodeRefreshInterval time.Duration `yaml:"response_code_refresh_interval" env:"RESPONSE_CODE_REFRESH_INTERVAL" default:"5m" validate:"positive"`
+
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout" env:"HEALTH_CHECK_TIMEOUT" default:"2s" validate:"positive"`
+
+	RequestLogBody      bool `yaml:"request_log_body" env:"REQUEST_LOG_BODY" default:"false"`
+	RequestLogBodyLimit int  `yaml:"request_log_body_limit" env:"REQUEST_LOG_BODY_LIMIT" default:"4096" validate:"positive"`
 }
 
-func LoadConfig() (*Config, error) {
-	if err := godotenv.Load(); err != nil {
-		loghelper.Logger.WithError(err).Error("Error while reading .env file config, now using system environment variables")
-	}
-
-	cfg := &Config{
-		DBHost:     os.Getenv("DB_URL"),
-		DBPort:     os.Getenv("DB_PORT"),
-		DBUsername: os.Getenv("DB_USERNAME"),
-		DBPassword: os.Getenv("DB_PASSWORD"),
-		DBName:     os.Getenv("DB_NAME"),
-		RedisHost:  os.Getenv("REDIS_ADDRESS"),
-		RedisPort:  os.Getenv("REDIS_PORT"),
-		KafkaHost:  os.Getenv("KAFKA_HOST"),
-		KafkaPort:  os.Getenv("KAFKA_PORT"),
-		KafkaTopic: os.Getenv("KAFKA_TOPIC"),
-		AppPort: func() string {
-			if value := os.Getenv("APP_PORT"); value != "" {
-				return value
-			}
-			return ":8080"
-		}(),
+// LoadConfig build config from defaults, yaml file when path is not empty, then environment variables.
+// Config is returned along with validation error so caller can still print it.
+func LoadConfig(path string) (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read .env file: %w", err)
 	}
 
-	if cfg.DBHost == "" {
-		loghelper.Logger.Error("DB_HOST not found in environment")
-		return nil, fmt.Errorf("DB_HOST not found in environment")
+	cfg := &Config{}
+	if err := applyDefaults(cfg); err != nil {
+		return nil, err
 	}
 
-	if cfg.RedisHost == "" {
-		loghelper.Logger.Error("REDIS_HOST not found in environment")
-		return nil, fmt.Errorf("REDIS_HOST not found in environment")
+	if path != "" {
+		if err := applyFile(cfg, path); err != nil {
+			return nil, err
+		}
 	}
 
-	if cfg.KafkaHost == "" {
-		loghelper.Logger.Error("KAFKA_HOST not found in environment")
-		return nil, fmt.Errorf("KAFKA_HOST not found in environment")
-	}
+	var problems []string
+	problems = append(problems, applyEnv(cfg)...)
+	problems = append(problems, applySecretFiles(cfg)...)
+	problems = append(problems, validate(cfg)...)
+	problems = append(problems, cfg.crossValidate()...)
 
+	if len(problems) > 0 {
+		return cfg, &ValidationError{Problems: problems}
+	}
 	return cfg, nil
 }
+
+// crossValidate check rules spanning more than one field
+func (c *Config) crossValidate() []string {
+	var problems []string
+	if c.DBMaxIdleConns > c.DBMaxOpenConns {
+		problems = append(problems, fmt.Sprintf("db_max_idle_conns (%d) must not exceed db_max_open_conns (%d)", c.DBMaxIdleConns, c.DBMaxOpenConns))
+	}
+	if c.RedisMinIdleConns > c.RedisPoolSize {
+		problems = append(problems, fmt.Sprintf("redis_min_idle_conns (%d) must not exceed redis_pool_size (%d)", c.RedisMinIdleConns, c.RedisPoolSize))
+	}
+	if _, err := c.AdminKeys(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if c.KafkaHost == "" && c.KafkaBrokerList == "" {
+		problems = append(problems, "kafka_host or kafka_brokers is required")
+	}
+	if c.KafkaSaslMechanism != "none" && (c.KafkaSaslUsername == "" || c.KafkaSaslPassword == "") {
+		problems = append(problems, fmt.Sprintf("kafka_sasl_username and kafka_sasl_password are required for kafka_sasl_mechanism %s", c.KafkaSaslMechanism))
+	}
+	if (c.KafkaTlsCertFile == "") != (c.KafkaTlsKeyFile == "") {
+		problems = append(problems, "kafka_tls_cert_file and kafka_tls_key_file must be set together")
+	}
+	if !c.KafkaTlsEnabled && (c.KafkaTlsCaFile != "" || c.KafkaTlsCertFile != "") {
+		problems = append(problems, "kafka_tls_ca_file and kafka_tls_cert_file require kafka_tls_enabled")
+	}
+	return problems
+}
+
+// Redacted render config as yaml with secret replaced, output can be used as config file
+func (c *Config) Redacted() ([]byte, error) {
+	redacted := *c
+	redactSecrets(&redacted)
+	return yaml.Marshal(&redacted)
+}
+
+// AdminKeys return admin id by api key
+func (c *Config) AdminKeys() (map[string]string, error) {
+	keys := map[string]string{}
+	for _, pair := range strings.Split(c.AdminApiKeys, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		adminId, key, ok := strings.Cut(pair, ":")
+		if !ok || adminId == "" || key == "" {
+			return nil, fmt.Errorf("admin_api_keys entry must be admin-id:key")
+		}
+		keys[key] = adminId
+	}
+	return keys, nil
+}
+
+// KafkaBrokers return bootstrap brokers, comma separated kafka_brokers takes precedence over kafka_host and kafka_port
+func (c *Config) KafkaBrokers() []string {
+	var brokers []string
+	for _, broker := range strings.Split(c.KafkaBrokerList, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	if len(brokers) > 0 {
+		return brokers
+	}
+	return []string{fmt.Sprintf("%s:%d", c.KafkaHost, c.KafkaPort)}
+}
+
+// LoadLogConfig read logger setting before logger exists, so it must not log anything
+func LoadLogConfig() loghelper.LoggerConfig {
+	_ = godotenv.Load()
+
+	intEnv := func(key string, fallback int) int {
+		if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value >= 0 {
+			return value
+		}
+		return fallback
+	}
+
+	return loghelper.LoggerConfig{
+		File: func() string {
+			if value := os.Getenv("LOG_FILE"); value != "" {
+				return value
+			}
+			return "./resource/app.log"
+		}(),
+		Level: func() logrus.Level {
+			if level, err := logrus.ParseLevel(os.Getenv("LOG_LEVEL")); err == nil {
+				return level
+			}
+			return logrus.InfoLevel
+		}(),
+		Format: func() string {
+			if value := os.Getenv("LOG_FORMAT"); value == loghelper.FormatJSON {
+				return value
+			}
+			return loghelper.FormatText
+		}(),
+		MaxSizeMB:  intEnv("LOG_MAX_SIZE_MB", 100),
+		MaxBackups: intEnv("LOG_MAX_BACKUPS", 7),
+		MaxAgeDays: intEnv("LOG_MAX_AGE_DAYS", 30),
+		Compress:   os.Getenv("LOG_COMPRESS") != "false",
+	}
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redactedValue = "******"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ValidationError list every invalid setting at once so operator can fix them in one pass
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// eachField visit every tagged field of config
+func eachField(cfg *Config, visit func(field reflect.StructField, value reflect.Value)) {
+	value := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		visit(value.Type().Field(i), value.Field(i))
+	}
+}
+
+// describe name field by yaml key and env variable, the two ways it can be set
+func describe(field reflect.StructField) string {
+	if env := field.Tag.Get("env"); env != "" {
+		return fmt.Sprintf("%s (%s)", field.Tag.Get("yaml"), env)
+	}
+	return field.Tag.Get("yaml")
+}
+
+func setValue(value reflect.Value, raw string) error {
+	switch {
+	case value.Type() == durationType:
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		value.SetInt(int64(duration))
+	case value.Kind() == reflect.String:
+		value.SetString(raw)
+	case value.Kind() == reflect.Int:
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		value.SetInt(int64(number))
+	case value.Kind() == reflect.Float64:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		value.SetFloat(number)
+	case value.Kind() == reflect.Bool:
+		flag, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		value.SetBool(flag)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+func applyDefaults(cfg *Config) error {
+	var err error
+	eachField(cfg, func(field reflect.StructField, value reflect.Value) {
+		if raw, ok := field.Tag.Lookup("default"); ok && err == nil {
+			if setErr := setValue(value, raw); setErr != nil {
+				err = fmt.Errorf("invalid default of %s: %w", field.Name, setErr)
+			}
+		}
+	})
+	return err
+}
+
+// applyFile overlay yaml file on defaults, unknown key is rejected to catch typo
+func applyFile(cfg *Config, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	// empty file decodes to io.EOF, defaults are kept
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func applyEnv(cfg *Config) []string {
+	var problems []string
+	eachField(cfg, func(field reflect.StructField, value reflect.Value) {
+		raw := os.Getenv(field.Tag.Get("env"))
+		if field.Tag.Get("env") == "" || raw == "" {
+			return
+		}
+		if err := setValue(value, raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", describe(field), err))
+		}
+	})
+	return problems
+}
+
+// applySecretFiles read secret from file named by <ENV>_FILE, e.g. docker or kubernetes mounted secret
+func applySecretFiles(cfg *Config) []string {
+	var problems []string
+	eachField(cfg, func(field reflect.StructField, value reflect.Value) {
+		if field.Tag.Get("secret") != "true" {
+			return
+		}
+
+		fileEnv := field.Tag.Get("env") + "_FILE"
+		path := os.Getenv(fileEnv)
+		if path == "" {
+			return
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: failed to read secret file: %s", fileEnv, err))
+			return
+		}
+		value.SetString(strings.TrimSpace(string(content)))
+	})
+	return problems
+}
+
+func validate(cfg *Config) []string {
+	var problems []string
+	eachField(cfg, func(field reflect.StructField, value reflect.Value) {
+		rule := field.Tag.Get("validate")
+		if rule == "" {
+			return
+		}
+
+		name, argument, _ := strings.Cut(rule, "=")
+		if problem := check(name, argument, value); problem != "" {
+			problems = append(problems, fmt.Sprintf("%s %s", describe(field), problem))
+		}
+	})
+	return problems
+}
+
+func check(rule, argument string, value reflect.Value) string {
+	switch rule {
+	case "required":
+		if value.IsZero() {
+			return "is required"
+		}
+	case "port":
+		if port := value.Int(); port < 1 || port > 65535 {
+			return fmt.Sprintf("must be a port between 1 and 65535, got %d", port)
+		}
+	case "positive":
+		if value.Int() <= 0 {
+			return "must be greater than zero"
+		}
+	case "nonnegative":
+		if value.Int() < 0 {
+			return "must not be negative"
+		}
+	case "ratio":
+		if ratio := value.Float(); ratio < 0 || ratio > 1 {
+			return fmt.Sprintf("must be between 0 and 1, got %g", ratio)
+		}
+	case "oneof":
+		if options := strings.Fields(argument); !slices.Contains(options, value.String()) {
+			return fmt.Sprintf("must be one of %s, got %q", strings.Join(options, ", "), value.String())
+		}
+	case "address":
+		if _, port, err := net.SplitHostPort(value.String()); err != nil || port == "" {
+			return fmt.Sprintf("must be host:port or :port, got %q", value.String())
+		}
+	}
+	return ""
+}
+
+func redactSecrets(cfg *Config) {
+	eachField(cfg, func(field reflect.StructField, value reflect.Value) {
+		if field.Tag.Get("secret") == "true" && value.String() != "" {
+			value.SetString(redactedValue)
+		}
+	})
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const minimalConfig = `
+db_host: db.internal
+db_username: transfer
+db_password: db-password
+db_name: transfer
+redis_host: redis.internal:6379
+kafka_host: kafka.internal
+`
+
+// clearConfigEnv blank every config variable so environment of test runner does not leak into loaded config
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	eachField(&Config{}, func(field reflect.StructField, value reflect.Value) {
+		if env := field.Tag.Get("env"); env != "" {
+			t.Setenv(env, "")
+			t.Setenv(env+"_FILE", "")
+		}
+	})
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	clearConfigEnv(t)
+	cfg, err := LoadConfig(writeFile(t, "config.yaml", minimalConfig))
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      any
+		expected any
+	}{
+		{"db port", cfg.DBPort, 5432},
+		{"db ssl mode", cfg.DBSslMode, "disable"},
+		{"db connection lifetime", cfg.DBConnMaxLifetime, time.Hour},
+		{"kafka producer mode", cfg.KafkaProducerMode, "sync"},
+		{"kafka client id", cfg.KafkaClientId, "briefcash-transfer"},
+		{"pre stop delay", cfg.ShutdownPreStopDelay, 5 * time.Second},
+		{"trace sample ratio", cfg.TraceSampleRatio, 1.0},
+		{"request log body", cfg.RequestLogBody, false},
+		{"value from file", cfg.DBHost, "db.internal"},
+	}
+
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.expected) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.expected)
+		}
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	clearConfigEnv(t)
+	path := writeFile(t, "config.yaml", minimalConfig+`
+db_port: 6432
+db_max_open_conns: 50
+kafka_producer_mode: async
+`)
+	t.Setenv("DB_PORT", "7432")
+	t.Setenv("KAFKA_PRODUCER_MODE", "")
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      any
+		expected any
+	}{
+		{"env overrides file", cfg.DBPort, 7432},
+		{"file overrides default", cfg.DBMaxOpenConns, 50},
+		{"empty env keeps file value", cfg.KafkaProducerMode, "async"},
+		{"default without file or env", cfg.DBMaxIdleConns, 10},
+	}
+
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.expected) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.expected)
+		}
+	}
+}
+
+func TestLoadConfigWithoutFile(t *testing.T) {
+	clearConfigEnv(t)
+	for env, value := range map[string]string{
+		"DB_URL": "db.internal", "DB_USERNAME": "transfer", "DB_PASSWORD": "db-password", "DB_NAME": "transfer",
+		"REDIS_ADDRESS": "redis.internal:6379", "KAFKA_BROKERS": "kafka-1:9092, kafka-2:9092",
+	} {
+		t.Setenv(env, value)
+	}
+
+	cfg, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if brokers := cfg.KafkaBrokers(); !reflect.DeepEqual(brokers, []string{"kafka-1:9092", "kafka-2:9092"}) {
+		t.Errorf("KafkaBrokers() = %v, want both brokers", brokers)
+	}
+}
+
+func TestLoadConfigRejectsUnknownKey(t *testing.T) {
+	clearConfigEnv(t)
+	_, err := LoadConfig(writeFile(t, "config.yaml", minimalConfig+"db_prot: 6432\n"))
+	if err == nil || !strings.Contains(err.Error(), "db_prot") {
+		t.Fatalf("LoadConfig() error = %v, want unknown key db_prot", err)
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		t.Error("typo in file must fail parsing, not validation")
+	}
+}
+
+func TestLoadConfigListsEveryProblem(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("DB_PORT", "abc")
+	t.Setenv("DB_MAX_OPEN_CONNS", "0")
+	t.Setenv("TRACE_SAMPLE_RATIO", "2")
+	t.Setenv("KAFKA_PRODUCER_MODE", "batch")
+
+	cfg, err := LoadConfig(writeFile(t, "config.yaml", ""))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("LoadConfig() error = %v, want ValidationError", err)
+	}
+	if cfg == nil {
+		t.Fatal("config must be returned along with validation error")
+	}
+
+	expected := []string{
+		"db_port (DB_PORT): invalid integer \"abc\"",
+		"db_host (DB_URL) is required",
+		"db_username (DB_USERNAME) is required",
+		"db_password (DB_PASSWORD) is required",
+		"db_name (DB_NAME) is required",
+		"db_max_open_conns (DB_MAX_OPEN_CONNS) must be greater than zero",
+		"redis_host (REDIS_ADDRESS) is required",
+		"kafka_producer_mode (KAFKA_PRODUCER_MODE) must be one of sync, async, got \"batch\"",
+		"trace_sample_ratio (TRACE_SAMPLE_RATIO) must be between 0 and 1, got 2",
+		"kafka_host or kafka_brokers is required",
+	}
+	for _, problem := range expected {
+		if !slices.Contains(validationErr.Problems, problem) {
+			t.Errorf("problem %q is not listed in %q", problem, validationErr.Problems)
+		}
+	}
+	if !strings.Contains(err.Error(), "\n  - db_host (DB_URL) is required") {
+		t.Errorf("error message does not list problems one per line: %s", err)
+	}
+}
+
+func TestLoadConfigSecretFile(t *testing.T) {
+	clearConfigEnv(t)
+	path := writeFile(t, "config.yaml", minimalConfig)
+
+	t.Setenv("DB_PASSWORD", "from-env")
+	t.Setenv("DB_PASSWORD_FILE", writeFile(t, "db_password", "from-file\n"))
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if cfg.DBPassword != "from-file" {
+		t.Errorf("db password = %q, want trimmed value of secret file", cfg.DBPassword)
+	}
+
+	// only secret field is read from file
+	t.Setenv("DB_NAME_FILE", writeFile(t, "db_name", "other"))
+	if cfg, _ = LoadConfig(path); cfg.DBName != "transfer" {
+		t.Errorf("db name = %q, non secret field must not be read from file", cfg.DBName)
+	}
+
+	t.Setenv("DB_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+	var validationErr *ValidationError
+	if _, err = LoadConfig(path); !errors.As(err, &validationErr) || !strings.HasPrefix(validationErr.Problems[0], "DB_PASSWORD_FILE: failed to read secret file") {
+		t.Errorf("LoadConfig() error = %v, want unreadable secret file problem", err)
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		problem string
+	}{
+		{"valid", func(c *Config) {}, ""},
+		{"idle over open db connections", func(c *Config) { c.DBMaxIdleConns = 200 }, "db_max_idle_conns (200) must not exceed db_max_open_conns (100)"},
+		{"idle over redis pool", func(c *Config) { c.RedisMinIdleConns = c.RedisPoolSize + 1 }, "must not exceed redis_pool_size"},
+		{"malformed admin key", func(c *Config) { c.AdminApiKeys = "admin-1" }, "admin_api_keys entry must be admin-id:key"},
+		{"no kafka broker", func(c *Config) { c.KafkaHost = "" }, "kafka_host or kafka_brokers is required"},
+		{"sasl without credential", func(c *Config) { c.KafkaSaslMechanism = "plain" }, "kafka_sasl_username and kafka_sasl_password are required for kafka_sasl_mechanism plain"},
+		{"certificate without key", func(c *Config) { c.KafkaTlsEnabled = true; c.KafkaTlsCertFile = "client.pem" }, "kafka_tls_cert_file and kafka_tls_key_file must be set together"},
+		{"ca without tls", func(c *Config) { c.KafkaTlsCaFile = "ca.pem" }, "kafka_tls_ca_file and kafka_tls_cert_file require kafka_tls_enabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			if err := applyDefaults(cfg); err != nil {
+				t.Fatalf("applyDefaults() error: %v", err)
+			}
+			cfg.KafkaHost = "kafka.internal"
+			cfg.AdminApiKeys = "admin-1:key-1"
+			tt.modify(cfg)
+
+			problems := cfg.crossValidate()
+			if tt.problem == "" {
+				if len(problems) != 0 {
+					t.Errorf("crossValidate() = %q, want none", problems)
+				}
+				return
+			}
+			if len(problems) != 1 || !strings.Contains(problems[0], tt.problem) {
+				t.Errorf("crossValidate() = %q, want %q", problems, tt.problem)
+			}
+		})
+	}
+}
+
+func TestRedactedMasksEverySecret(t *testing.T) {
+	cfg := &Config{}
+	if err := applyDefaults(cfg); err != nil {
+		t.Fatalf("applyDefaults() error: %v", err)
+	}
+
+	var secrets []string
+	eachField(cfg, func(field reflect.StructField, value reflect.Value) {
+		if field.Tag.Get("secret") == "true" {
+			secret := "plain-" + field.Tag.Get("yaml")
+			value.SetString(secret)
+			secrets = append(secrets, secret)
+		}
+	})
+	if len(secrets) == 0 {
+		t.Fatal("config has no secret field")
+	}
+	cfg.RedisPassword = ""
+
+	out, err := cfg.Redacted()
+	if err != nil {
+		t.Fatalf("Redacted() error: %v", err)
+	}
+	for _, secret := range secrets {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("redacted config leaks %s", secret)
+		}
+	}
+	if !strings.Contains(string(out), "db_password: '"+redactedValue+"'") && !strings.Contains(string(out), "db_password: "+redactedValue) {
+		t.Errorf("db_password is not redacted:\n%s", out)
+	}
+	if !strings.Contains(string(out), `redis_password: ""`) {
+		t.Errorf("empty secret must stay empty:\n%s", out)
+	}
+	if cfg.DBPassword != "plain-db_password" {
+		t.Error("Redacted() must not modify config")
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"briefcash-transfer/config"
+	"briefcash-transfer/internal/helper/dbhelper"
+	"briefcash-transfer/internal/helper/kafkahelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/protobuf"
+	"briefcash-transfer/internal/repository"
+	"briefcash-transfer/internal/service"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const deadLetterUsage = `usage: briefcash-transfer [-config file] dlq <command> [flags]
+
+commands:
+  list     list oldest dead letters, -limit caps the number shown
+  inspect  show metadata, headers and decoded payload of dead letter at -partition and -offset
+  replay   publish dead letter at -partition and -offset back to its original topic, -dry-run only checks eligibility.
+           instruction is replayed only while its transfer is CREATED, FAILED_PUBLISH transfer is already refunded
+           and merchant has to submit a new transfer
+`
+
+// runDeadLetterCommand is ops entry point to investigate and replay dead letter topic, it returns process exit code
+func runDeadLetterCommand(configPath string, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, deadLetterUsage)
+		return 2
+	}
+
+	loghelper.InitLogger(config.LoadLogConfig())
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if cfg.KafkaDeadLetterTopic == "" {
+		fmt.Fprintln(os.Stderr, "kafka_dead_letter_topic is not configured")
+		return 1
+	}
+
+	// replay waits for broker acknowledgement before transfer is moved forward
+	kafkaConfig := newKafkaConfig(cfg)
+	kafkaConfig.ProducerMode = kafkahelper.ProducerModeSync
+
+	command, flags := args[0], flag.NewFlagSet("dlq "+args[0], flag.ContinueOnError)
+	limit := flags.Int("limit", 50, "maximum dead letters listed")
+	partition := flags.Int("partition", -1, "partition of dead letter")
+	offset := flags.Int64("offset", -1, "offset of dead letter")
+	dryRun := flags.Bool("dry-run", false, "check replay eligibility without publishing")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	switch command {
+	case "list":
+		err = listDeadLetters(os.Stdout, kafkaConfig, cfg.KafkaDeadLetterTopic, *limit)
+	case "inspect", "replay":
+		if *partition < 0 || *offset < 0 {
+			fmt.Fprintf(os.Stderr, "dlq %s requires -partition and -offset\n", command)
+			return 2
+		}
+		var letter kafkahelper.DeadLetter
+		letter, err = kafkahelper.ReadDeadLetter(kafkaConfig, cfg.KafkaDeadLetterTopic, int32(*partition), *offset)
+		if err != nil {
+			break
+		}
+		if command == "inspect" {
+			err = inspectDeadLetter(os.Stdout, letter)
+		} else {
+			err = replayDeadLetter(os.Stdout, cfg, kafkaConfig, letter, *dryRun)
+		}
+	default:
+		fmt.Fprint(os.Stderr, deadLetterUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func listDeadLetters(out io.Writer, kafkaConfig kafkahelper.KafkaConfig, topic string, limit int) error {
+	letters, err := kafkahelper.ReadDeadLetters(kafkaConfig, topic, limit)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "PARTITION\tOFFSET\tFAILED AT\tSOURCE\tTOPIC\tKEY\tREASON")
+	for _, letter := range letters {
+		fmt.Fprintf(writer, "%d\t%d\t%s\t%s\t%s\t%s\t%s\n", letter.DeadLetterPartition, letter.DeadLetterOffset,
+			letter.FailedAt.Format(time.RFC3339), letter.Source, letter.Topic, letter.Key, letter.Reason)
+	}
+	return writer.Flush()
+}
+
+func inspectDeadLetter(out io.Writer, letter kafkahelper.DeadLetter) error {
+	fmt.Fprintf(out, "dead letter:  %d/%d\n", letter.DeadLetterPartition, letter.DeadLetterOffset)
+	fmt.Fprintf(out, "source:       %s\n", letter.Source)
+	fmt.Fprintf(out, "topic:        %s\n", letter.Topic)
+	if letter.Source == kafkahelper.DeadLetterSourceConsume {
+		fmt.Fprintf(out, "position:     %d/%d\n", letter.Partition, letter.Offset)
+	}
+	fmt.Fprintf(out, "key:          %s\n", letter.Key)
+	fmt.Fprintf(out, "failed at:    %s\n", letter.FailedAt.Format(time.RFC3339Nano))
+	fmt.Fprintf(out, "reason:       %s\n", letter.Reason)
+
+	keys := make([]string, 0, len(letter.Headers))
+	for key := range letter.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Fprintln(out, "headers:")
+	for _, key := range keys {
+		fmt.Fprintf(out, "  %s: %s\n", key, letter.Headers[key])
+	}
+
+	fmt.Fprintln(out, "payload:")
+	_, err := out.Write(decodePayload(letter))
+	return err
+}
+
+// decodePayload render json payload indented and protobuf payload as json, anything else as hex dump
+func decodePayload(letter kafkahelper.DeadLetter) []byte {
+	if letter.Headers[kafkahelper.HeaderContentType] != kafkahelper.ContentTypeProtobuf && json.Valid(letter.Value) {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, letter.Value, "", "  "); err == nil {
+			return append(indented.Bytes(), '\n')
+		}
+	}
+
+	if message, err := protobuf.DecodeMessage(letter.Value); err == nil {
+		if decoded, err := (protojson.MarshalOptions{Multiline: true, Indent: "  "}).Marshal(message); err == nil {
+			return append(decoded, '\n')
+		}
+	}
+	return []byte(hex.Dump(letter.Value))
+}
+
+func replayDeadLetter(out io.Writer, cfg *config.Config, kafkaConfig kafkahelper.KafkaConfig, letter kafkahelper.DeadLetter, dryRun bool) error {
+	ctx := context.Background()
+	dbCon, err := dbhelper.NewDBConfig(newDBConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to connect database, with error: %w", err)
+	}
+	if sqlDb, err := dbCon.DB.DB(); err == nil {
+		defer sqlDb.Close()
+	}
+
+	kafkaProducer, err := kafkahelper.NewKafkaProducer(kafkaConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect kafka, with error: %w", err)
+	}
+	defer kafkaProducer.Close()
+
+	deadLetterService := service.NewDeadLetterService(repository.NewTransferRepository(dbCon.DB), dbCon.DB, kafkaProducer)
+	if err := deadLetterService.CheckReplay(ctx, letter); err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Fprintf(out, "dead letter %d/%d is eligible for replay to %s\n", letter.DeadLetterPartition, letter.DeadLetterOffset, letter.Topic)
+		return nil
+	}
+
+	if err := deadLetterService.Replay(ctx, letter); err != nil {
+		if errors.Is(err, service.ErrReplayNotEligible) {
+			return fmt.Errorf("dead letter became ineligible before replay: %w", err)
+		}
+		return err
+	}
+	fmt.Fprintf(out, "dead letter %d/%d replayed to %s\n", letter.DeadLetterPartition, letter.DeadLetterOffset, letter.Topic)
+	return nil
+}
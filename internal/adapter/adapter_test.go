@@ -0,0 +1,351 @@
+package adapter
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/entity"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with current output")
+
+// staticMapper resolve codes from fixed table, standing in for response code mapping cached from database
+type staticMapper map[string]map[string]entity.PartnerResponseCode
+
+func (m staticMapper) Resolve(ctx context.Context, bankCode string, codes ...string) entity.PartnerResponseCode {
+	for _, code := range codes {
+		if mapping, ok := m[bankCode][code]; ok {
+			return mapping
+		}
+	}
+	return entity.PartnerResponseCode{BankCode: bankCode, PartnerCode: codes[0], Outcome: constants.OutcomeSuspect}
+}
+
+func snapMappings(bankCode string) map[string]entity.PartnerResponseCode {
+	return map[string]entity.PartnerResponseCode{
+		"200":     {BankCode: bankCode, PartnerCode: "200", Outcome: constants.OutcomeSuccess},
+		"202":     {BankCode: bankCode, PartnerCode: "202", Outcome: constants.OutcomePending},
+		"400":     {BankCode: bankCode, PartnerCode: "400", Outcome: constants.OutcomeFailedFinal, ResponseCode: constants.ErrBadRequest},
+		"403xx14": {BankCode: bankCode, PartnerCode: "403xx14", Outcome: constants.OutcomeFailedFinal},
+		"404xx11": {BankCode: bankCode, PartnerCode: "404xx11", Outcome: constants.OutcomeFailedFinal, ResponseCode: constants.ErrDataNotFound},
+		"409":     {BankCode: bankCode, PartnerCode: "409", Outcome: constants.OutcomeSuspect},
+		"500xx01": {BankCode: bankCode, PartnerCode: "500xx01", Outcome: constants.OutcomeSuspect},
+		"500xx02": {BankCode: bankCode, PartnerCode: "500xx02", Outcome: constants.OutcomeFailedRetryable},
+		"504":     {BankCode: bankCode, PartnerCode: "504", Outcome: constants.OutcomeSuspect},
+	}
+}
+
+var testMapper = staticMapper{
+	constants.BankCodeBCA:  snapMappings(constants.BankCodeBCA),
+	constants.BankCodeBRI:  snapMappings(constants.BankCodeBRI),
+	constants.BankCodeCIMB: snapMappings(constants.BankCodeCIMB),
+	constants.BankCodePermata: {
+		"00": {BankCode: constants.BankCodePermata, PartnerCode: "00", Outcome: constants.OutcomeSuccess},
+		"14": {BankCode: constants.BankCodePermata, PartnerCode: "14", Outcome: constants.OutcomeFailedFinal, ResponseCode: constants.ErrDataNotFound},
+		"68": {BankCode: constants.BankCodePermata, PartnerCode: "68", Outcome: constants.OutcomeSuspect},
+		"96": {BankCode: constants.BankCodePermata, PartnerCode: "96", Outcome: constants.OutcomeFailedRetryable},
+		"99": {BankCode: constants.BankCodePermata, PartnerCode: "99", Outcome: "UNDEFINED"},
+	},
+}
+
+// sampleTransfer is the canonical transfer every adapter golden file is rendered from
+func sampleTransfer(transferType, channel, beneficiaryBankCode, beneficiaryAccount string) dto.PartnerTransfer {
+	var clearingInfo *dto.TransferClearingInfo
+	if transferType == constants.TransferTypeSKN || transferType == constants.TransferTypeRTGS {
+		clearingInfo = &dto.TransferClearingInfo{
+			ResidentStatus:            "01",
+			BeneficiaryType:           "2",
+			BeneficiaryBankBranchName: "KCP Sudirman",
+			BeneficiaryBankCity:       "Jakarta Selatan",
+			BeneficiaryBankAddress:    "Plaza Mandiri, Jl. Jend. Gatot Subroto Kav. 36-38",
+			BeneficiaryAddress:        []string{"Jl. Jend. Sudirman Kav. 52-53", "Senayan, Kebayoran Baru"},
+			DatiII:                    "0394",
+		}
+	}
+
+	return dto.PartnerTransfer{
+		Request: dto.TransferRequest{
+			PartnerReferenceNo:       "MRC-20250101-0001",
+			CustomerNumber:           "6281234567890",
+			AccountType:              "saving",
+			BeneficiaryAccountNumber: beneficiaryAccount,
+			BeneficiaryBankCode:      beneficiaryBankCode,
+			Amount:                   dto.TransferAmountData{Value: "150000.00", Currency: "IDR"},
+			AdditionalInfo: dto.TransferRequestInfo{
+				TransactionDate:   "2025-01-01T10:15:30+07:00",
+				CustomerReference: "INV-0001",
+				Channel:           channel,
+				Remarks:           "invoice payment",
+				Email:             "budi@example.com",
+				Address:           "Jl. Jend. Sudirman Kav. 52-53, Senayan, Kebayoran Baru, Jakarta Selatan",
+				Citizenship:       "wni",
+				TransferPurpose:   "02",
+				TransferActivity:  "01",
+				CustomerType:      "01",
+			},
+			ClearingInfo: clearingInfo,
+		},
+		TransferType:           transferType,
+		ReferenceNumber:        "TRF2501010000001",
+		ExternalId:             "ext-0001",
+		SourceAccountNo:        "1234567890",
+		SourceAccountName:      "PT Briefcash Indonesia",
+		BeneficiaryAccountName: "Budi Santoso",
+		BeneficiaryBankName:    "Bank Mandiri",
+	}
+}
+
+// assertGolden compare value rendered as indented json with testdata file, run with -update to rewrite
+func assertGolden(t *testing.T, name string, value any) {
+	t.Helper()
+	got, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal %s: %v", name, err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s: %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func TestResolveTransferType(t *testing.T) {
+	tests := []struct {
+		name        string
+		channel     string
+		beneficiary string
+		partner     string
+		want        string
+		wantErr     error
+	}{
+		{"online same bank", constants.ChannelOnline, constants.BankCodeBCA, constants.BankCodeBCA, constants.TransferTypeIntrabank, nil},
+		{"online other bank", constants.ChannelOnline, "008", constants.BankCodeBCA, constants.TransferTypeInterbank, nil},
+		{"bifast other bank", constants.ChannelBifast, "008", constants.BankCodeBRI, constants.TransferTypeInterbank, nil},
+		{"va", constants.ChannelVA, constants.BankCodeCIMB, constants.BankCodeCIMB, constants.TransferTypeVA, nil},
+		{"skn", constants.ChannelSknbi, "008", constants.BankCodePermata, constants.TransferTypeSKN, nil},
+		{"rtgs", constants.ChannelRtgs, "008", constants.BankCodePermata, constants.TransferTypeRTGS, nil},
+		{"wallet", constants.ChannelWallet, "", constants.BankCodeBCA, "", ErrUnsupportedTransfer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveTransferType(tt.channel, tt.beneficiary, tt.partner)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("transfer type = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name         string
+		bankCode     string
+		code         string
+		codes        []string
+		outcome      string
+		status       string
+		responseCode string
+	}{
+		{"exact snap code", constants.BankCodeBCA, "4041811", snapCodes("4041811"), constants.OutcomeFailedFinal, constants.StatusRejected, constants.ErrDataNotFound},
+		{"snap code of any service", constants.BankCodeBRI, "4041711", snapCodes("4041711"), constants.OutcomeFailedFinal, constants.StatusRejected, constants.ErrDataNotFound},
+		{"snap http category", constants.BankCodeCIMB, "2001700", snapCodes("2001700"), constants.OutcomeSuccess, constants.StatusDone, constants.TransferSuccess},
+		{"retryable", constants.BankCodeBCA, "5001802", snapCodes("5001802"), constants.OutcomeFailedRetryable, constants.StatusPending, constants.ErrExternalServerError},
+		{"unknown snap code", constants.BankCodeBCA, "4291800", snapCodes("4291800"), constants.OutcomeSuspect, constants.StatusTimeout, constants.ErrTransferTimeout},
+		{"malformed snap code", constants.BankCodeBRI, "00", snapCodes("00"), constants.OutcomeSuspect, constants.StatusTimeout, constants.ErrTransferTimeout},
+		{"code of other bank", constants.BankCodePermata, "2001800", []string{"2001800"}, constants.OutcomeSuspect, constants.StatusTimeout, constants.ErrTransferTimeout},
+		{"invalid outcome in mapping", constants.BankCodePermata, "99", []string{"99"}, constants.OutcomeSuspect, constants.StatusTimeout, constants.ErrTransferTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := normalize(context.Background(), testMapper, tt.bankCode, tt.code, "message", tt.codes)
+			if result.Outcome != tt.outcome || result.Status != tt.status || result.ResponseCode != tt.responseCode {
+				t.Errorf("normalize(%s) = %s/%s/%s, want %s/%s/%s", tt.code, result.Outcome, result.Status, result.ResponseCode, tt.outcome, tt.status, tt.responseCode)
+			}
+			if result.PartnerResponseCode != tt.code {
+				t.Errorf("partner response code = %q, want %q", result.PartnerResponseCode, tt.code)
+			}
+		})
+	}
+}
+
+func TestNewPartnerAdapters(t *testing.T) {
+	adapters := NewPartnerAdapters(testMapper)
+	for _, bankCode := range []string{constants.BankCodeBCA, constants.BankCodeBRI, constants.BankCodeCIMB, constants.BankCodePermata} {
+		partnerAdapter, err := ForBank(adapters, bankCode)
+		if err != nil {
+			t.Fatalf("ForBank(%s): %v", bankCode, err)
+		}
+		if partnerAdapter.BankCode() != bankCode {
+			t.Errorf("adapter for %s reports bank code %s", bankCode, partnerAdapter.BankCode())
+		}
+	}
+
+	if _, err := ForBank(adapters, "999"); !errors.Is(err, ErrUnknownPartner) {
+		t.Errorf("ForBank(999) error = %v, want %v", err, ErrUnknownPartner)
+	}
+}
+
+// permataStatus render permata transfer response carrying status code
+func permataStatus(statusCode string) []byte {
+	return []byte(`{"MsgRsHdr":{"CustRefID":"TRF2501010000001","StatusCode":"` + statusCode + `","StatusDesc":"status ` + statusCode + `"},"TrxReffNo":"PMT2501010000077"}`)
+}
+
+// scriptedSender answer every send with next status code and record payload sent
+type scriptedSender struct {
+	statusCodes []string
+	payloads    []any
+	err         error
+}
+
+func (s *scriptedSender) send(ctx context.Context, payload any) ([]byte, error) {
+	s.payloads = append(s.payloads, payload)
+	if s.err != nil {
+		return nil, s.err
+	}
+	statusCode := s.statusCodes[min(len(s.payloads), len(s.statusCodes))-1]
+	return permataStatus(statusCode), nil
+}
+
+func TestExecute(t *testing.T) {
+	errConnection := errors.New("connection reset by peer")
+	tests := []struct {
+		name        string
+		statusCodes []string
+		sendErr     error
+		maxAttempts int
+		attempts    int
+		outcome     string
+		status      string
+		wantErr     error
+	}{
+		{"success on first attempt", []string{"00"}, nil, 3, 1, constants.OutcomeSuccess, constants.StatusDone, nil},
+		{"retryable then success", []string{"96", "96", "00"}, nil, 3, 3, constants.OutcomeSuccess, constants.StatusDone, nil},
+		{"retryable exhausted is rejected", []string{"96"}, nil, 3, 3, constants.OutcomeFailedRetryable, constants.StatusRejected, nil},
+		{"single attempt policy", []string{"96", "00"}, nil, 1, 1, constants.OutcomeFailedRetryable, constants.StatusRejected, nil},
+		{"final failure is not retried", []string{"14", "00"}, nil, 3, 1, constants.OutcomeFailedFinal, constants.StatusRejected, nil},
+		{"suspect is not retried", []string{"68", "00"}, nil, 3, 1, constants.OutcomeSuspect, constants.StatusTimeout, nil},
+		{"send error is not retried", nil, errConnection, 3, 1, "", "", errConnection},
+	}
+
+	partnerAdapter := newTestPermataAdapter()
+	transfer := sampleTransfer(constants.TransferTypeInterbank, constants.ChannelOnline, "008", "1300012345678")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &scriptedSender{statusCodes: tt.statusCodes, err: tt.sendErr}
+			result, err := Execute(context.Background(), partnerAdapter, transfer, sender.send, RetryPolicy{MaxAttempts: tt.maxAttempts, Backoff: time.Millisecond})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if len(sender.payloads) != tt.attempts {
+				t.Errorf("sent %d times, want %d", len(sender.payloads), tt.attempts)
+			}
+			if result.Outcome != tt.outcome || result.Status != tt.status {
+				t.Errorf("result = %s/%s, want %s/%s", result.Outcome, result.Status, tt.outcome, tt.status)
+			}
+			for i := 1; i < len(sender.payloads); i++ {
+				if !reflect.DeepEqual(sender.payloads[i], sender.payloads[0]) {
+					t.Errorf("attempt %d sent different payload", i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteStopsOnCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sender := &scriptedSender{statusCodes: []string{"96"}}
+	send := func(ctx context.Context, payload any) ([]byte, error) {
+		defer cancel()
+		return sender.send(ctx, payload)
+	}
+
+	transfer := sampleTransfer(constants.TransferTypeInterbank, constants.ChannelOnline, "008", "1300012345678")
+	_, err := Execute(ctx, newTestPermataAdapter(), transfer, send, RetryPolicy{MaxAttempts: 3, Backoff: time.Hour})
+	if !errors.Is(err, context.Canceled) || len(sender.payloads) != 1 {
+		t.Errorf("error = %v after %d attempts, want cancelled after first attempt", err, len(sender.payloads))
+	}
+}
+
+type buildCase struct {
+	name     string
+	transfer dto.PartnerTransfer
+	golden   string
+	wantErr  error
+}
+
+func runBuildCases(t *testing.T, partnerAdapter PartnerAdapter, tests []buildCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := partnerAdapter.BuildRequest(tt.transfer)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.golden != "" {
+				assertGolden(t, tt.golden, payload)
+			}
+		})
+	}
+}
+
+type parseCase struct {
+	name         string
+	transferType string
+	fixture      string
+	golden       string
+	wantErr      bool
+}
+
+func runParseCases(t *testing.T, partnerAdapter PartnerAdapter, tests []parseCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := partnerAdapter.ParseResponse(context.Background(), tt.transferType, readFixture(t, tt.fixture))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.golden != "" {
+				assertGolden(t, tt.golden, result)
+			}
+		})
+	}
+}
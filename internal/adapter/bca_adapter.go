@@ -0,0 +1,104 @@
+package adapter
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// bcaTransferType is additionalInfo.transferType of BCA interbank transfer by our channel
+var bcaTransferType = map[string]string{
+	constants.ChannelOnline: "1",
+	constants.ChannelSknbi:  "2",
+	constants.ChannelRtgs:   "3",
+	constants.ChannelBifast: "4",
+}
+
+type bcaAdapter struct {
+	mapper ResponseCodeMapper
+}
+
+func NewBCAAdapter(mapper ResponseCodeMapper) PartnerAdapter {
+	return &bcaAdapter{mapper}
+}
+
+func (a *bcaAdapter) BankCode() string {
+	return constants.BankCodeBCA
+}
+
+func (a *bcaAdapter) BuildRequest(transfer dto.PartnerTransfer) (any, error) {
+	request := transfer.Request
+	switch transfer.TransferType {
+	case constants.TransferTypeIntrabank:
+		return dto.BCATransferInternalRequest{
+			PartnerReferenceNo:   transfer.ReferenceNumber,
+			BeneficiaryEmail:     request.AdditionalInfo.Email,
+			Amount:               amountData(request.Amount),
+			BeneficiaryAccountNo: request.BeneficiaryAccountNumber,
+			Remark:               request.AdditionalInfo.Remarks,
+			SourceAccountNo:      transfer.SourceAccountNo,
+			TransactionDate:      request.AdditionalInfo.TransactionDate,
+			AdditionalInfo: dto.BCATransferInternalInfo{
+				EconomicActivity:   request.AdditionalInfo.TransferActivity,
+				TransactionPurpose: request.AdditionalInfo.TransferPurpose,
+			},
+		}, nil
+	case constants.TransferTypeInterbank, constants.TransferTypeSKN, constants.TransferTypeRTGS:
+		return dto.BCATransferExternalRequest{
+			PartnerReferenceNo:     transfer.ReferenceNumber,
+			Amount:                 amountData(request.Amount),
+			BeneficiaryAccountName: transfer.BeneficiaryAccountName,
+			BeneficiaryAccountNo:   request.BeneficiaryAccountNumber,
+			BeneficiaryBankCode:    request.BeneficiaryBankCode,
+			BeneficiaryEmail:       request.AdditionalInfo.Email,
+			SourceAccountNo:        transfer.SourceAccountNo,
+			TransactionDate:        request.AdditionalInfo.TransactionDate,
+			AdditionalInfo: dto.BCATransferExternalInfo{
+				TransferType: bcaTransferType[request.AdditionalInfo.Channel],
+				PurposeCode:  request.AdditionalInfo.TransferPurpose,
+			},
+		}, nil
+	case constants.TransferTypeVA:
+		return dto.BCATransferToVARequest{
+			VirtualAccountNo:    request.BeneficiaryAccountNumber,
+			VirtualAccountEmail: request.AdditionalInfo.Email,
+			SourceAccountNo:     transfer.SourceAccountNo,
+			PartnerReferenceNo:  transfer.ReferenceNumber,
+			PaidAmount:          request.Amount.Value,
+			TrxDateTime:         request.AdditionalInfo.TransactionDate,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s with bca", ErrUnsupportedTransfer, transfer.TransferType)
+}
+
+func (a *bcaAdapter) ParseResponse(ctx context.Context, transferType string, body []byte) (dto.PartnerTransferResult, error) {
+	var result dto.PartnerTransferResult
+	switch transferType {
+	case constants.TransferTypeIntrabank:
+		var response dto.BCATransferInternalResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return result, fmt.Errorf("failed to parse bca internal transfer response, with error: %w", err)
+		}
+		result = normalize(ctx, a.mapper, a.BankCode(), response.ResponseCode, response.ResponseMessage, snapCodes(response.ResponseCode))
+		result.ReferenceNumber, result.BankReferenceNo = response.PartnerReferenceNo, response.ReferenceNo
+	case constants.TransferTypeInterbank, constants.TransferTypeSKN, constants.TransferTypeRTGS:
+		var response dto.BCATransferExternalResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return result, fmt.Errorf("failed to parse bca external transfer response, with error: %w", err)
+		}
+		result = normalize(ctx, a.mapper, a.BankCode(), response.ResponseCode, response.ResponseMessage, snapCodes(response.ResponseCode))
+		result.ReferenceNumber, result.BankReferenceNo = response.PartnerReferenceNo, response.ReferenceNo
+	case constants.TransferTypeVA:
+		var response dto.BCATransferToVAResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return result, fmt.Errorf("failed to parse bca virtual account response, with error: %w", err)
+		}
+		result = normalize(ctx, a.mapper, a.BankCode(), response.ResponseCode, response.ResponseMessage, snapCodes(response.ResponseCode))
+		result.ReferenceNumber, result.BankReferenceNo = response.VirtualAccountData.PartnerReferenceNo, response.VirtualAccountData.ReferenceNo
+	default:
+		return result, fmt.Errorf("%w: %s with bca", ErrUnsupportedTransfer, transferType)
+	}
+	return result, nil
+}
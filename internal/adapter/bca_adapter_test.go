@@ -0,0 +1,28 @@
+package adapter
+
+import (
+	"briefcash-transfer/internal/constants"
+	"testing"
+)
+
+func TestBCABuildRequest(t *testing.T) {
+	runBuildCases(t, NewBCAAdapter(testMapper), []buildCase{
+		{"intrabank", sampleTransfer(constants.TransferTypeIntrabank, constants.ChannelOnline, constants.BankCodeBCA, "0987654321"), "bca/intrabank_request.json", nil},
+		{"interbank online", sampleTransfer(constants.TransferTypeInterbank, constants.ChannelOnline, "008", "1300012345678"), "bca/interbank_request.json", nil},
+		{"interbank bifast", sampleTransfer(constants.TransferTypeInterbank, constants.ChannelBifast, "008", "1300012345678"), "bca/bifast_request.json", nil},
+		{"skn", sampleTransfer(constants.TransferTypeSKN, constants.ChannelSknbi, "008", "1300012345678"), "bca/skn_request.json", nil},
+		{"rtgs", sampleTransfer(constants.TransferTypeRTGS, constants.ChannelRtgs, "008", "1300012345678"), "bca/rtgs_request.json", nil},
+		{"va", sampleTransfer(constants.TransferTypeVA, constants.ChannelVA, constants.BankCodeBCA, "3935812345678901"), "bca/va_request.json", nil},
+		{"unsupported", sampleTransfer("WALLET", constants.ChannelWallet, constants.BankCodeBCA, "081234567890"), "", ErrUnsupportedTransfer},
+	})
+}
+
+func TestBCAParseResponse(t *testing.T) {
+	runParseCases(t, NewBCAAdapter(testMapper), []parseCase{
+		{"intrabank success", constants.TransferTypeIntrabank, "bca/intrabank_response.json", "bca/intrabank_result.json", false},
+		{"interbank invalid account", constants.TransferTypeInterbank, "bca/interbank_response.json", "bca/interbank_result.json", false},
+		{"va in progress", constants.TransferTypeVA, "bca/va_response.json", "bca/va_result.json", false},
+		{"malformed", constants.TransferTypeSKN, "malformed_response.json", "", true},
+		{"unsupported", "WALLET", "bca/intrabank_response.json", "", true},
+	})
+}
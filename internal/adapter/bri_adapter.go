@@ -0,0 +1,128 @@
+package adapter
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	briFeeTypeOur         = "OUR"
+	briVirtualAccountCode = 5
+)
+
+// briServiceCode is SNAP service code of BRI interbank transfer by transfer type
+var briServiceCode = map[string]string{
+	constants.TransferTypeInterbank: "18",
+	constants.TransferTypeRTGS:      "22",
+	constants.TransferTypeSKN:       "23",
+}
+
+// briSenderType map our customer type to BRI sender type, 01 individual, 02 corporate, 99 others
+var briSenderType = map[string]string{
+	"01": "01",
+	"02": "02",
+}
+
+type briAdapter struct {
+	mapper ResponseCodeMapper
+}
+
+func NewBRIAdapter(mapper ResponseCodeMapper) PartnerAdapter {
+	return &briAdapter{mapper}
+}
+
+func (a *briAdapter) BankCode() string {
+	return constants.BankCodeBRI
+}
+
+func (a *briAdapter) BuildRequest(transfer dto.PartnerTransfer) (any, error) {
+	request := transfer.Request
+	switch transfer.TransferType {
+	case constants.TransferTypeIntrabank:
+		return dto.BRITransferInternalRequest{
+			PartnerReferenceNo:   transfer.ReferenceNumber,
+			Amount:               amountData(request.Amount),
+			BeneficiaryAccountNo: request.BeneficiaryAccountNumber,
+			FeeType:              briFeeTypeOur,
+			Remark:               request.AdditionalInfo.Remarks,
+			SourceAccountNo:      transfer.SourceAccountNo,
+			TransactionDate:      request.AdditionalInfo.TransactionDate,
+			AdditionalInfo:       map[string]string{},
+		}, nil
+	case constants.TransferTypeInterbank, constants.TransferTypeSKN, constants.TransferTypeRTGS:
+		senderType, ok := briSenderType[request.AdditionalInfo.CustomerType]
+		if !ok {
+			senderType = "99"
+		}
+		return dto.BRITransferExternalRequest{
+			PartnerReferenceNo:     transfer.ReferenceNumber,
+			Amount:                 amountData(request.Amount),
+			BeneficiaryAccountName: transfer.BeneficiaryAccountName,
+			BeneficiaryAccountNo:   request.BeneficiaryAccountNumber,
+			BeneficiaryAddress:     request.AdditionalInfo.Address,
+			BeneficiaryBankCode:    request.BeneficiaryBankCode,
+			BeneficiaryBankName:    transfer.BeneficiaryBankName,
+			BeneficiaryEmail:       request.AdditionalInfo.Email,
+			SourceAccountNo:        transfer.SourceAccountNo,
+			TransactionDate:        request.AdditionalInfo.TransactionDate,
+			AdditionalInfo: dto.BRITransferExternalAdditionalInfoRequest{
+				ServiceCode:          briServiceCode[transfer.TransferType],
+				ReferenceNo:          request.AdditionalInfo.CustomerReference,
+				ExternalId:           transfer.ExternalId,
+				SenderIdentityNumber: request.CustomerNumber,
+				SenderType:           senderType,
+				SenderResidentStatus: residentStatus(request.AdditionalInfo.Citizenship),
+			},
+		}, nil
+	case constants.TransferTypeVA:
+		partnerServiceId, customerNo, err := splitVirtualAccount(request.BeneficiaryAccountNumber, briVirtualAccountCode)
+		if err != nil {
+			return nil, err
+		}
+		return dto.BRITransferVARequest{
+			PartnerServiceId:   partnerServiceId,
+			CustomerNo:         customerNo,
+			VirtualAccountNo:   partnerServiceId + customerNo,
+			VirtualAccountName: transfer.BeneficiaryAccountName,
+			SourceAccountNo:    transfer.SourceAccountNo,
+			PartnerReferenceNo: transfer.ReferenceNumber,
+			PaidAmount:         amountData(request.Amount),
+			TrxDateTime:        request.AdditionalInfo.TransactionDate,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s with bri", ErrUnsupportedTransfer, transfer.TransferType)
+}
+
+func (a *briAdapter) ParseResponse(ctx context.Context, transferType string, body []byte) (dto.PartnerTransferResult, error) {
+	var result dto.PartnerTransferResult
+	switch transferType {
+	case constants.TransferTypeIntrabank:
+		var response dto.BRITransferInternalResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return result, fmt.Errorf("failed to parse bri internal transfer response, with error: %w", err)
+		}
+		result = normalize(ctx, a.mapper, a.BankCode(), response.ResponseCode, response.ResponseMessage, snapCodes(response.ResponseCode))
+		result.ReferenceNumber, result.BankReferenceNo = response.PartnerReferenceNo, response.ReferenceNo
+	case constants.TransferTypeInterbank, constants.TransferTypeSKN, constants.TransferTypeRTGS:
+		var response dto.BRITransferExternalResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return result, fmt.Errorf("failed to parse bri external transfer response, with error: %w", err)
+		}
+		result = normalize(ctx, a.mapper, a.BankCode(), response.ResponseCode, response.ResponseMessage, snapCodes(response.ResponseCode))
+		result.ReferenceNumber, result.BankReferenceNo = response.PartnerReferenceNo, response.ReferenceNo
+	case constants.TransferTypeVA:
+		var response dto.BRITransferVAResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return result, fmt.Errorf("failed to parse bri virtual account response, with error: %w", err)
+		}
+		// BRIVA identifies payment by payment request id instead of reference number
+		result = normalize(ctx, a.mapper, a.BankCode(), response.ResponseCode, response.ResponseMessage, snapCodes(response.ResponseCode))
+		result.ReferenceNumber, result.BankReferenceNo = response.VirtualAccountData.PartnerReferenceNo, response.VirtualAccountData.PaymentRequestId
+	default:
+		return result, fmt.Errorf("%w: %s with bri", ErrUnsupportedTransfer, transferType)
+	}
+	return result, nil
+}
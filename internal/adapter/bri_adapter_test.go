@@ -0,0 +1,31 @@
+package adapter
+
+import (
+	"briefcash-transfer/internal/constants"
+	"testing"
+)
+
+func TestBRIBuildRequest(t *testing.T) {
+	corporate := sampleTransfer(constants.TransferTypeSKN, constants.ChannelSknbi, "008", "1300012345678")
+	corporate.Request.AdditionalInfo.CustomerType = "02"
+	corporate.Request.AdditionalInfo.Citizenship = "wna"
+
+	runBuildCases(t, NewBRIAdapter(testMapper), []buildCase{
+		{"intrabank", sampleTransfer(constants.TransferTypeIntrabank, constants.ChannelOnline, constants.BankCodeBRI, "888801000157508"), "bri/intrabank_request.json", nil},
+		{"interbank", sampleTransfer(constants.TransferTypeInterbank, constants.ChannelOnline, "008", "1300012345678"), "bri/interbank_request.json", nil},
+		{"skn corporate non resident", corporate, "bri/skn_request.json", nil},
+		{"rtgs", sampleTransfer(constants.TransferTypeRTGS, constants.ChannelRtgs, "008", "1300012345678"), "bri/rtgs_request.json", nil},
+		{"va", sampleTransfer(constants.TransferTypeVA, constants.ChannelVA, constants.BankCodeBRI, "7777708123456789"), "bri/va_request.json", nil},
+		{"va too short", sampleTransfer(constants.TransferTypeVA, constants.ChannelVA, constants.BankCodeBRI, "77777"), "", ErrInvalidVirtualAccount},
+		{"unsupported", sampleTransfer("WALLET", constants.ChannelWallet, constants.BankCodeBRI, "081234567890"), "", ErrUnsupportedTransfer},
+	})
+}
+
+func TestBRIParseResponse(t *testing.T) {
+	runParseCases(t, NewBRIAdapter(testMapper), []parseCase{
+		{"intrabank success", constants.TransferTypeIntrabank, "bri/intrabank_response.json", "bri/intrabank_result.json", false},
+		{"rtgs timeout", constants.TransferTypeRTGS, "bri/rtgs_response.json", "bri/rtgs_result.json", false},
+		{"va success", constants.TransferTypeVA, "bri/va_response.json", "bri/va_result.json", false},
+		{"malformed", constants.TransferTypeVA, "malformed_response.json", "", true},
+	})
+}
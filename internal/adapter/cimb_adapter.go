@@ -0,0 +1,112 @@
+package adapter
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const cimbVirtualAccountCode = 4
+
+// cimbTrxType is additionalInfo.trxType of CIMB interbank transfer by our channel
+var cimbTrxType = map[string]string{
+	constants.ChannelOnline: "ONLINE",
+	constants.ChannelBifast: "BIFAST",
+	constants.ChannelSknbi:  "SKN",
+	constants.ChannelRtgs:   "RTGS",
+}
+
+type cimbAdapter struct {
+	mapper ResponseCodeMapper
+}
+
+func NewCIMBAdapter(mapper ResponseCodeMapper) PartnerAdapter {
+	return &cimbAdapter{mapper}
+}
+
+func (a *cimbAdapter) BankCode() string {
+	return constants.BankCodeCIMB
+}
+
+func (a *cimbAdapter) BuildRequest(transfer dto.PartnerTransfer) (any, error) {
+	request := transfer.Request
+	switch transfer.TransferType {
+	case constants.TransferTypeIntrabank:
+		return dto.CIMBTransferInternalRequest{
+			PartnerReferenceNo:   transfer.ReferenceNumber,
+			Amount:               amountData(request.Amount),
+			BeneficiaryAccountNo: request.BeneficiaryAccountNumber,
+			Remark:               request.AdditionalInfo.Remarks,
+			SourceAccountNo:      transfer.SourceAccountNo,
+			TransactionDate:      request.AdditionalInfo.TransactionDate,
+			AdditionalData: dto.CIMBTransferInternalInfoRequest{
+				BeneficiaryAccountName: transfer.BeneficiaryAccountName,
+			},
+		}, nil
+	case constants.TransferTypeInterbank, constants.TransferTypeSKN, constants.TransferTypeRTGS:
+		return dto.CIMBTransferExternalRequest{
+			PartnerReferenceNo:     transfer.ReferenceNumber,
+			Amount:                 amountData(request.Amount),
+			BeneficiaryAccountName: transfer.BeneficiaryAccountName,
+			BeneficiaryAccountNo:   request.BeneficiaryAccountNumber,
+			BeneficiaryBankCode:    request.BeneficiaryBankCode,
+			SourceAccountNo:        transfer.SourceAccountNo,
+			TransactionDate:        request.AdditionalInfo.TransactionDate,
+			AdditionalInfo: dto.CIMBTransferExternalInfoRequest{
+				Remark:         request.AdditionalInfo.Remarks,
+				TrxType:        cimbTrxType[request.AdditionalInfo.Channel],
+				TrxPurposeCode: request.AdditionalInfo.TransferPurpose,
+			},
+		}, nil
+	case constants.TransferTypeVA:
+		partnerServiceId, customerNo, err := splitVirtualAccount(request.BeneficiaryAccountNumber, cimbVirtualAccountCode)
+		if err != nil {
+			return nil, err
+		}
+		return dto.CIMBTransferVARequest{
+			PartnerServiceId:   partnerServiceId,
+			CustomerNo:         customerNo,
+			VirtualAccountNo:   partnerServiceId + customerNo,
+			VirtualAccountName: transfer.BeneficiaryAccountName,
+			PartnerReferenceNo: transfer.ReferenceNumber,
+			PaidAmount:         amountData(request.Amount),
+			TotalAmount:        amountData(request.Amount),
+			TrxDateTime:        request.AdditionalInfo.TransactionDate,
+			AdditionalInfo:     map[string]string{"sourceAccountNo": transfer.SourceAccountNo},
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s with cimb", ErrUnsupportedTransfer, transfer.TransferType)
+}
+
+func (a *cimbAdapter) ParseResponse(ctx context.Context, transferType string, body []byte) (dto.PartnerTransferResult, error) {
+	var result dto.PartnerTransferResult
+	switch transferType {
+	case constants.TransferTypeIntrabank:
+		// CIMB internal transfer response carries no bank reference
+		var response dto.CIMBTransferInternaResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return result, fmt.Errorf("failed to parse cimb internal transfer response, with error: %w", err)
+		}
+		result = normalize(ctx, a.mapper, a.BankCode(), response.ResponseCode, response.ResponseMessage, snapCodes(response.ResponseCode))
+		result.ReferenceNumber = response.PartnerReferenceNo
+	case constants.TransferTypeInterbank, constants.TransferTypeSKN, constants.TransferTypeRTGS:
+		var response dto.CIMBTransferExternalResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return result, fmt.Errorf("failed to parse cimb external transfer response, with error: %w", err)
+		}
+		result = normalize(ctx, a.mapper, a.BankCode(), response.ResponseCode, response.ResponseMessage, snapCodes(response.ResponseCode))
+		result.ReferenceNumber, result.BankReferenceNo = response.PartnerReferenceNo, response.ReferenceNo
+	case constants.TransferTypeVA:
+		var response dto.CIMBTransferVAResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return result, fmt.Errorf("failed to parse cimb virtual account response, with error: %w", err)
+		}
+		result = normalize(ctx, a.mapper, a.BankCode(), response.ResponseCode, response.ResponseMessage, snapCodes(response.ResponseCode))
+		result.ReferenceNumber, result.BankReferenceNo = response.VirtualAccountData.PartnerReferenceNo, response.VirtualAccountData.ReferenceNo
+	default:
+		return result, fmt.Errorf("%w: %s with cimb", ErrUnsupportedTransfer, transferType)
+	}
+	return result, nil
+}
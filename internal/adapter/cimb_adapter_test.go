@@ -0,0 +1,26 @@
+package adapter
+
+import (
+	"briefcash-transfer/internal/constants"
+	"testing"
+)
+
+func TestCIMBBuildRequest(t *testing.T) {
+	runBuildCases(t, NewCIMBAdapter(testMapper), []buildCase{
+		{"intrabank", sampleTransfer(constants.TransferTypeIntrabank, constants.ChannelOnline, constants.BankCodeCIMB, "800123456700"), "cimb/intrabank_request.json", nil},
+		{"interbank bifast", sampleTransfer(constants.TransferTypeInterbank, constants.ChannelBifast, "008", "1300012345678"), "cimb/bifast_request.json", nil},
+		{"skn", sampleTransfer(constants.TransferTypeSKN, constants.ChannelSknbi, "008", "1300012345678"), "cimb/skn_request.json", nil},
+		{"rtgs", sampleTransfer(constants.TransferTypeRTGS, constants.ChannelRtgs, "008", "1300012345678"), "cimb/rtgs_request.json", nil},
+		{"va", sampleTransfer(constants.TransferTypeVA, constants.ChannelVA, constants.BankCodeCIMB, "2046081234567890"), "cimb/va_request.json", nil},
+		{"unsupported", sampleTransfer("WALLET", constants.ChannelWallet, constants.BankCodeCIMB, "081234567890"), "", ErrUnsupportedTransfer},
+	})
+}
+
+func TestCIMBParseResponse(t *testing.T) {
+	runParseCases(t, NewCIMBAdapter(testMapper), []parseCase{
+		{"intrabank success", constants.TransferTypeIntrabank, "cimb/intrabank_response.json", "cimb/intrabank_result.json", false},
+		{"interbank insufficient fund", constants.TransferTypeInterbank, "cimb/interbank_response.json", "cimb/interbank_result.json", false},
+		{"va duplicate reference", constants.TransferTypeVA, "cimb/va_response.json", "cimb/va_result.json", false},
+		{"malformed", constants.TransferTypeIntrabank, "malformed_response.json", "", true},
+	})
+}
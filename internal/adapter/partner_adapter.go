@@ -0,0 +1,227 @@
+package adapter
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedTransfer   = errors.New("transfer type is not supported by partner")
+	ErrUnknownPartner        = errors.New("no adapter registered for partner bank")
+	ErrInvalidAmount         = errors.New("invalid transfer amount for partner")
+	ErrInvalidVirtualAccount = errors.New("invalid virtual account number")
+)
+
+const defaultCurrency = "IDR"
+
+// PartnerAdapter convert canonical transfer into bank payload and normalize bank response back.
+// Adapters are pure mapping, sending the payload and signing the request belong to partner client.
+type PartnerAdapter interface {
+	BankCode() string
+	BuildRequest(transfer dto.PartnerTransfer) (any, error)
+	ParseResponse(ctx context.Context, transferType string, body []byte) (dto.PartnerTransferResult, error)
+}
+
+// NewPartnerAdapters register adapter of every supported bank keyed by bank code
+func NewPartnerAdapters(mapper ResponseCodeMapper) map[string]PartnerAdapter {
+	adapters := map[string]PartnerAdapter{}
+	for _, partnerAdapter := range []PartnerAdapter{NewBCAAdapter(mapper), NewBRIAdapter(mapper), NewCIMBAdapter(mapper), NewPermataAdapter(mapper, time.Now)} {
+		adapters[partnerAdapter.BankCode()] = partnerAdapter
+	}
+	return adapters
+}
+
+// ForBank pick adapter of partner bank
+func ForBank(adapters map[string]PartnerAdapter, bankCode string) (PartnerAdapter, error) {
+	partnerAdapter, ok := adapters[bankCode]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPartner, bankCode)
+	}
+	return partnerAdapter, nil
+}
+
+// PartnerSender post payload built by adapter to partner and return raw response body, signing belongs to sender
+type PartnerSender func(ctx context.Context, payload any) ([]byte, error)
+
+// RetryPolicy bound resend of transfer partner reported as failed retryable, backoff doubles on every attempt
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// Execute build, send and normalize transfer, resending the same payload while partner reports failed retryable.
+// Transfer still failing on last attempt is rejected since partner never executed it. Send error is returned as is,
+// caller can not tell whether partner received the request
+func Execute(ctx context.Context, partnerAdapter PartnerAdapter, transfer dto.PartnerTransfer, send PartnerSender, policy RetryPolicy) (dto.PartnerTransferResult, error) {
+	payload, err := partnerAdapter.BuildRequest(transfer)
+	if err != nil {
+		return dto.PartnerTransferResult{}, err
+	}
+
+	backoff := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		body, err := send(ctx, payload)
+		if err != nil {
+			return dto.PartnerTransferResult{}, err
+		}
+
+		result, err := partnerAdapter.ParseResponse(ctx, transfer.TransferType, body)
+		if err != nil || result.Outcome != constants.OutcomeFailedRetryable {
+			return result, err
+		}
+
+		if attempt >= policy.MaxAttempts {
+			result.Status = constants.StatusRejected
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// ResolveTransferType derive transfer type from channel, online and bifast are intrabank when beneficiary banks with partner
+func ResolveTransferType(channel, beneficiaryBankCode, partnerBankCode string) (string, error) {
+	switch channel {
+	case constants.ChannelOnline, constants.ChannelBifast:
+		if beneficiaryBankCode == partnerBankCode {
+			return constants.TransferTypeIntrabank, nil
+		}
+		return constants.TransferTypeInterbank, nil
+	case constants.ChannelVA:
+		return constants.TransferTypeVA, nil
+	case constants.ChannelSknbi:
+		return constants.TransferTypeSKN, nil
+	case constants.ChannelRtgs:
+		return constants.TransferTypeRTGS, nil
+	}
+	return "", fmt.Errorf("%w: channel %s", ErrUnsupportedTransfer, channel)
+}
+
+// ResponseCodeMapper resolve partner response code to canonical outcome, codes are tried from most specific
+// and code without mapping resolves to suspect
+type ResponseCodeMapper interface {
+	Resolve(ctx context.Context, bankCode string, codes ...string) entity.PartnerResponseCode
+}
+
+// partnerOutcome is our transfer status and default response code of canonical outcome
+type partnerOutcome struct {
+	status       string
+	responseCode string
+}
+
+// outcomeResult keep transfer open unless partner outcome is final, retryable failure is sent again by Execute
+// and suspect waits for status inquiry since bank may have executed it
+var outcomeResult = map[string]partnerOutcome{
+	constants.OutcomeSuccess:         {constants.StatusDone, constants.TransferSuccess},
+	constants.OutcomePending:         {constants.StatusPending, constants.PendingTransfer},
+	constants.OutcomeFailedFinal:     {constants.StatusRejected, constants.ErrExternalServerError},
+	constants.OutcomeFailedRetryable: {constants.StatusPending, constants.ErrExternalServerError},
+	constants.OutcomeSuspect:         {constants.StatusTimeout, constants.ErrTransferTimeout},
+}
+
+// snapCodes list lookup keys of 7 digit SNAP response code, http status + service code + case code.
+// Mapping may hold exact code, code with service code written as xx, or bare http status.
+func snapCodes(responseCode string) []string {
+	if len(responseCode) != 7 {
+		return []string{responseCode}
+	}
+	return []string{responseCode, responseCode[:3] + "xx" + responseCode[5:], responseCode[:3]}
+}
+
+// normalize resolve partner code through mapper into our status and response code
+func normalize(ctx context.Context, mapper ResponseCodeMapper, bankCode, partnerCode, partnerMessage string, codes []string) dto.PartnerTransferResult {
+	mapping := mapper.Resolve(ctx, bankCode, codes...)
+	outcome, ok := outcomeResult[mapping.Outcome]
+	if !ok {
+		mapping.Outcome, outcome = constants.OutcomeSuspect, outcomeResult[constants.OutcomeSuspect]
+	}
+
+	responseCode := outcome.responseCode
+	if mapping.ResponseCode != "" {
+		responseCode = mapping.ResponseCode
+	}
+
+	return dto.PartnerTransferResult{
+		Outcome:                mapping.Outcome,
+		Status:                 outcome.status,
+		ResponseCode:           responseCode,
+		ResponseMessage:        constants.ResponseMap[responseCode],
+		PartnerResponseCode:    partnerCode,
+		PartnerResponseMessage: partnerMessage,
+	}
+}
+
+func amountData(amount dto.TransferAmountData) dto.TransferAmountData {
+	if amount.Currency == "" {
+		amount.Currency = defaultCurrency
+	}
+	return amount
+}
+
+// wholeAmount convert decimal amount to whole rupiah, partner without decimal field rejects fraction
+func wholeAmount(value string) (int64, error) {
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	if amount != math.Trunc(amount) {
+		return 0, fmt.Errorf("%w: %q has fraction", ErrInvalidAmount, value)
+	}
+	return int64(amount), nil
+}
+
+// splitVirtualAccount split SNAP virtual account into partner service id, left padded to 8 characters, and customer number
+func splitVirtualAccount(virtualAccountNo string, prefixLength int) (string, string, error) {
+	if len(virtualAccountNo) <= prefixLength {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidVirtualAccount, virtualAccountNo)
+	}
+	return fmt.Sprintf("%8s", virtualAccountNo[:prefixLength]), virtualAccountNo[prefixLength:], nil
+}
+
+// splitText wrap text on word boundary into fixed number of fields, partner address fields are limited in length
+// and the last field keeps whatever does not fit truncated to length
+func splitText(text string, fields, length int) []string {
+	parts := make([]string, fields)
+	index := 0
+	for _, word := range strings.Fields(text) {
+		switch {
+		case parts[index] == "":
+			parts[index] = word
+		case len(parts[index])+1+len(word) <= length:
+			parts[index] += " " + word
+		case index < fields-1:
+			index++
+			parts[index] = word
+		default:
+			parts[index] += " " + word
+		}
+	}
+
+	for i := range parts {
+		if len(parts[i]) > length {
+			parts[i] = parts[i][:length]
+		}
+	}
+	return parts
+}
+
+// residentStatus map citizenship to 01 resident, 02 non resident
+func residentStatus(citizenship string) string {
+	if strings.EqualFold(citizenship, "wna") {
+		return "02"
+	}
+	return "01"
+}
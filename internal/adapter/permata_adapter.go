@@ -0,0 +1,234 @@
+package adapter
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/timehelper"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	permataTimestampLayout    = "2006-01-02T15:04:05.000-07:00"
+	permataChargeToSender     = "0"
+	permataBillTypeVA         = "VIRTUALACCOUNT"
+	permataVirtualAccountCode = 4
+	permataAddressFields      = 3
+	permataAddressLength      = 35
+)
+
+// permataBeneficiaryType map our customer type to Permata 1 individual, 2 company, 3 others
+var permataBeneficiaryType = map[string]string{
+	"01": "1",
+	"02": "2",
+}
+
+type permataAdapter struct {
+	mapper ResponseCodeMapper
+	now    func() time.Time
+}
+
+// NewPermataAdapter create adapter stamping request header by now, tests pass fixed clock
+func NewPermataAdapter(mapper ResponseCodeMapper, now func() time.Time) PartnerAdapter {
+	return &permataAdapter{mapper, now}
+}
+
+func (a *permataAdapter) BankCode() string {
+	return constants.BankCodePermata
+}
+
+func (a *permataAdapter) BuildRequest(transfer dto.PartnerTransfer) (any, error) {
+	request := transfer.Request
+	header := a.header(transfer)
+
+	if transfer.TransferType == constants.TransferTypeVA {
+		virtualAccountNo := request.BeneficiaryAccountNumber
+		if len(virtualAccountNo) <= permataVirtualAccountCode {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidVirtualAccount, virtualAccountNo)
+		}
+		return dto.PermataTransferVARequest{
+			MessageHeader: header,
+			BillPaymentInfo: dto.PermataVAMessageBodyRequest{
+				BillType:             permataBillTypeVA,
+				InstitutionCode:      virtualAccountNo[:permataVirtualAccountCode],
+				BillNumber:           virtualAccountNo,
+				TransactionAmount:    request.Amount.Value,
+				Currency:             amountData(request.Amount).Currency,
+				UserId:               transfer.ExternalId,
+				DebitAccountNumber:   transfer.SourceAccountNo,
+				DebitAccountName:     transfer.SourceAccountName,
+				DebitAccountCurrency: defaultCurrency,
+			},
+		}, nil
+	}
+
+	// Permata transfer amount has no decimal part
+	amount, err := wholeAmount(request.Amount.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch transfer.TransferType {
+	case constants.TransferTypeIntrabank:
+		return dto.PermataTransferInternalRequest{
+			MessageHeader: header,
+			MessageBody: dto.PermataInternalMessageBodyRequest{
+				FromAccount:            transfer.SourceAccountNo,
+				ToAccount:              request.BeneficiaryAccountNumber,
+				Amount:                 amount,
+				CurrencyCode:           amountData(request.Amount).Currency,
+				ChargeTo:               permataChargeToSender,
+				TrxDesc:                request.AdditionalInfo.Remarks,
+				TrxDesc2:               request.AdditionalInfo.CustomerReference,
+				BeneficiaryEmail:       request.AdditionalInfo.Email,
+				BeneficiaryAccountName: transfer.BeneficiaryAccountName,
+				BeneficiaryPhoneNo:     request.CustomerNumber,
+				FromAccountName:        transfer.SourceAccountName,
+			},
+		}, nil
+	case constants.TransferTypeInterbank:
+		return dto.PermataTransferExternalRequest{
+			MessageHeader: header,
+			MessageBody: dto.PermataExternalMessageBodyRequest{
+				FromAccount:            transfer.SourceAccountNo,
+				ToAccount:              request.BeneficiaryAccountNumber,
+				ToBankId:               request.BeneficiaryBankCode,
+				ToBankName:             transfer.BeneficiaryBankName,
+				Amount:                 amount,
+				ChargeTo:               permataChargeToSender,
+				TrxDesc:                request.AdditionalInfo.Remarks,
+				TrxDesc2:               request.AdditionalInfo.CustomerReference,
+				BeneficiaryEmail:       request.AdditionalInfo.Email,
+				BeneficiaryAccountName: transfer.BeneficiaryAccountName,
+				BeneficiaryPhoneNo:     request.CustomerNumber,
+				FromAccountName:        transfer.SourceAccountName,
+			},
+		}, nil
+	case constants.TransferTypeSKN:
+		clearing := permataClearing(request)
+		return dto.PermataTransferSKNRequest{
+			MessageHeader: header,
+			MessageBody: dto.PermataSKNMessageBodyRequest{
+				FromAccount:               transfer.SourceAccountNo,
+				ToAccount:                 request.BeneficiaryAccountNumber,
+				ToBankId:                  request.BeneficiaryBankCode,
+				ToBankName:                transfer.BeneficiaryBankName,
+				Amount:                    amount,
+				CurrencyCode:              amountData(request.Amount).Currency,
+				ChargeTo:                  permataChargeToSender,
+				TrxDesc:                   request.AdditionalInfo.Remarks,
+				TrxDesc2:                  request.AdditionalInfo.CustomerReference,
+				ResidentStatus:            clearing.ResidentStatus,
+				BeneficiaryType:           clearing.BeneficiaryType,
+				BeneficiaryEmail:          request.AdditionalInfo.Email,
+				BeneficiaryAccountName:    transfer.BeneficiaryAccountName,
+				BeneficiaryPhoneNo:        request.CustomerNumber,
+				BeneficiaryBankAddress:    clearing.BeneficiaryBankAddress,
+				BeneficiaryBankBranchName: clearing.BeneficiaryBankBranchName,
+				BeneficiaryBankCity:       clearing.BeneficiaryBankCity,
+				FromAccountName:           transfer.SourceAccountName,
+				FromCurrencyCode:          defaultCurrency,
+				BeneficiaryAddress1:       clearing.BeneficiaryAddress[0],
+				BeneficiaryAddress2:       clearing.BeneficiaryAddress[1],
+				BeneficiaryAddress3:       clearing.BeneficiaryAddress[2],
+				DatiII:                    clearing.DatiII,
+			},
+		}, nil
+	case constants.TransferTypeRTGS:
+		clearing := permataClearing(request)
+		return dto.PermataTransferRTGSRequest{
+			MessageHeader: header,
+			MessageBody: dto.PermataRTGSMessageBodyRequest{
+				FromAccount:               transfer.SourceAccountNo,
+				ToAccount:                 request.BeneficiaryAccountNumber,
+				ToBankId:                  request.BeneficiaryBankCode,
+				ToBankName:                transfer.BeneficiaryBankName,
+				Amount:                    amount,
+				CurrencyCode:              amountData(request.Amount).Currency,
+				ChargeTo:                  permataChargeToSender,
+				TrxDesc:                   request.AdditionalInfo.Remarks,
+				TrxDesc2:                  request.AdditionalInfo.CustomerReference,
+				CitizenStatus:             strings.ToUpper(request.AdditionalInfo.Citizenship),
+				ResidentStatus:            clearing.ResidentStatus,
+				BeneficiaryEmail:          request.AdditionalInfo.Email,
+				BeneficiaryAccountName:    transfer.BeneficiaryAccountName,
+				BeneficiaryPhoneNo:        request.CustomerNumber,
+				BeneficiaryBankAddress:    clearing.BeneficiaryBankAddress,
+				BeneficiaryBankBranchName: clearing.BeneficiaryBankBranchName,
+				BeneficiaryBankCity:       clearing.BeneficiaryBankCity,
+				FromAccountName:           transfer.SourceAccountName,
+				FromCurrencyCode:          defaultCurrency,
+				BeneficiaryAddress1:       clearing.BeneficiaryAddress[0],
+				BeneficiaryAddress2:       clearing.BeneficiaryAddress[1],
+				BeneficiaryAddress3:       clearing.BeneficiaryAddress[2],
+				DatiII:                    clearing.DatiII,
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s with permata", ErrUnsupportedTransfer, transfer.TransferType)
+}
+
+func (a *permataAdapter) ParseResponse(ctx context.Context, transferType string, body []byte) (dto.PartnerTransferResult, error) {
+	var result dto.PartnerTransferResult
+	switch transferType {
+	case constants.TransferTypeIntrabank, constants.TransferTypeInterbank, constants.TransferTypeSKN, constants.TransferTypeRTGS:
+		var response dto.PermataTransferResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return result, fmt.Errorf("failed to parse permata transfer response, with error: %w", err)
+		}
+		result = a.normalize(ctx, response.MessageHeader)
+		result.BankReferenceNo = response.TransactionRefNo
+	case constants.TransferTypeVA:
+		var response dto.PermataTransferVAResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return result, fmt.Errorf("failed to parse permata virtual account response, with error: %w", err)
+		}
+		result = a.normalize(ctx, response.MessageHeader)
+		result.BankReferenceNo = response.BillReferenceNo
+	default:
+		return result, fmt.Errorf("%w: %s with permata", ErrUnsupportedTransfer, transferType)
+	}
+	return result, nil
+}
+
+// permataClearing return clearing info padded to three address lines, instruction published before clearing
+// info existed is derived from citizenship, customer type and free text address as before
+func permataClearing(request dto.TransferRequest) dto.TransferClearingInfo {
+	var clearing dto.TransferClearingInfo
+	if request.ClearingInfo != nil {
+		clearing = *request.ClearingInfo
+	} else {
+		beneficiaryType, ok := permataBeneficiaryType[request.AdditionalInfo.CustomerType]
+		if !ok {
+			beneficiaryType = "3"
+		}
+		clearing = dto.TransferClearingInfo{
+			ResidentStatus:     residentStatus(request.AdditionalInfo.Citizenship),
+			BeneficiaryType:    beneficiaryType,
+			BeneficiaryAddress: splitText(request.AdditionalInfo.Address, permataAddressFields, permataAddressLength),
+		}
+	}
+
+	address := make([]string, permataAddressFields)
+	copy(address, clearing.BeneficiaryAddress)
+	clearing.BeneficiaryAddress = address
+	return clearing
+}
+
+// header stamp request with time it is built, Permata rejects request whose timestamp is too far from its clock
+func (a *permataAdapter) header(transfer dto.PartnerTransfer) dto.PermataMessageHeaderRequest {
+	return dto.PermataMessageHeaderRequest{
+		RequestTimestamp:    a.now().In(timehelper.WIB).Format(permataTimestampLayout),
+		CustomerReferenceId: transfer.ReferenceNumber,
+	}
+}
+
+// normalize resolve Permata StatusCode, it follows ISO 8583 response code so no wildcard applies
+func (a *permataAdapter) normalize(ctx context.Context, header dto.PermataMessageHeaderResponse) dto.PartnerTransferResult {
+	result := normalize(ctx, a.mapper, a.BankCode(), header.StatusCode, header.StatusDesc, []string{header.StatusCode})
+	result.ReferenceNumber = header.CustomerReferenceId
+	return result
+}
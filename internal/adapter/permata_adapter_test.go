@@ -0,0 +1,50 @@
+package adapter
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/helper/timehelper"
+	"testing"
+	"time"
+)
+
+// permataClock is the instant every permata golden file is stamped with
+var permataClock = time.Date(2025, time.January, 1, 10, 15, 30, 0, timehelper.WIB)
+
+func newTestPermataAdapter() PartnerAdapter {
+	return NewPermataAdapter(testMapper, func() time.Time { return permataClock })
+}
+
+func TestPermataBuildRequest(t *testing.T) {
+	fraction := sampleTransfer(constants.TransferTypeInterbank, constants.ChannelOnline, "008", "1300012345678")
+	fraction.Request.Amount.Value = "150000.50"
+
+	// request timestamp comes from clock, not from transaction date given by merchant
+	staleDate := sampleTransfer(constants.TransferTypeIntrabank, constants.ChannelOnline, constants.BankCodePermata, "701234567")
+	staleDate.Request.AdditionalInfo.TransactionDate = "2024-12-31T08:00:00+07:00"
+
+	// instruction published before clearing info was part of the contract
+	legacy := sampleTransfer(constants.TransferTypeSKN, constants.ChannelSknbi, "008", "1300012345678")
+	legacy.Request.ClearingInfo = nil
+
+	runBuildCases(t, newTestPermataAdapter(), []buildCase{
+		{"intrabank", sampleTransfer(constants.TransferTypeIntrabank, constants.ChannelOnline, constants.BankCodePermata, "701234567"), "permata/intrabank_request.json", nil},
+		{"interbank", sampleTransfer(constants.TransferTypeInterbank, constants.ChannelOnline, "008", "1300012345678"), "permata/interbank_request.json", nil},
+		{"skn", sampleTransfer(constants.TransferTypeSKN, constants.ChannelSknbi, "008", "1300012345678"), "permata/skn_request.json", nil},
+		{"skn without clearing info", legacy, "permata/skn_legacy_request.json", nil},
+		{"rtgs", sampleTransfer(constants.TransferTypeRTGS, constants.ChannelRtgs, "008", "1300012345678"), "permata/rtgs_request.json", nil},
+		{"va", sampleTransfer(constants.TransferTypeVA, constants.ChannelVA, constants.BankCodePermata, "8625081234567890"), "permata/va_request.json", nil},
+		{"fraction amount", fraction, "", ErrInvalidAmount},
+		{"transaction date is not request timestamp", staleDate, "permata/intrabank_request.json", nil},
+		{"unsupported", sampleTransfer("WALLET", constants.ChannelWallet, constants.BankCodePermata, "081234567890"), "", ErrUnsupportedTransfer},
+	})
+}
+
+func TestPermataParseResponse(t *testing.T) {
+	runParseCases(t, newTestPermataAdapter(), []parseCase{
+		{"transfer success", constants.TransferTypeInterbank, "permata/transfer_response.json", "permata/transfer_result.json", false},
+		{"transfer invalid account", constants.TransferTypeSKN, "permata/rejected_response.json", "permata/rejected_result.json", false},
+		{"unknown status code", constants.TransferTypeRTGS, "permata/unknown_response.json", "permata/unknown_result.json", false},
+		{"va timeout", constants.TransferTypeVA, "permata/va_response.json", "permata/va_result.json", false},
+		{"malformed", constants.TransferTypeIntrabank, "malformed_response.json", "", true},
+	})
+}
@@ -9,11 +9,21 @@ const (
 	TransferSuccess        = "2004300"
 	PendingTransfer        = "2024300"
 	ErrBadRequest          = "4004300"
+	ErrUnauthorized        = "4014300"
+	ErrAmountLimit         = "4034302"
 	ErrInsufficientFunds   = "4034314"
+	ErrOutsideWindow       = "4034315"
 	ErrDataNotFound        = "4044301"
+	ErrInvalidStatus       = "4094301"
+	ErrAlreadyReversed     = "4094302"
+	ErrDuplicateReference  = "4094303"
 	ErrBalanceNotAvailable = "4044316"
+	ErrInvalidBill         = "4044312"
+	ErrInvalidAmount       = "4044313"
 	ErrInternalServerError = "5004301"
 	ErrExternalServerError = "5004302"
+	ErrServiceUnavailable  = "5034300"
+	ErrServiceBusy         = "5034301"
 	ErrTransferTimeout     = "5044300"
 )
 
@@ -21,11 +31,21 @@ var ResponseMap = map[string]string{
 	TransferSuccess:        "Successful",
 	PendingTransfer:        "Transaction is being processed",
 	ErrBadRequest:          "Invalid request",
+	ErrUnauthorized:        "Unauthorized",
+	ErrAmountLimit:         "Amount outside transaction limit",
 	ErrInsufficientFunds:   "Insufficient funds",
+	ErrOutsideWindow:       "Transaction not permitted outside channel operating window",
 	ErrDataNotFound:        "Data not found",
+	ErrInvalidStatus:       "Invalid transaction status",
+	ErrAlreadyReversed:     "Transaction already reversed",
+	ErrDuplicateReference:  "Duplicate reference number",
 	ErrBalanceNotAvailable: "Merchant balance not found",
+	ErrInvalidBill:         "Invalid bill or virtual account",
+	ErrInvalidAmount:       "Invalid amount",
 	ErrInternalServerError: "Internal server error",
 	ErrExternalServerError: "External server error",
+	ErrServiceUnavailable:  "Service is shutting down, retry later",
+	ErrServiceBusy:         "Service is busy, retry later",
 	ErrTransferTimeout:     "Timeout",
 }
 
@@ -35,9 +55,173 @@ const (
 )
 
 const (
+	StatusCreated       = "CREATED"
 	StatusFailedPublish = "FAILED_PUBLISH"
 	StatusDone          = "DONE"
 	StatusPending       = "PENDING"
 	StatusRejected      = "REJECTED"
 	StatusInProgress    = "PROGRESSING"
+	StatusReversed      = "REVERSED"
+	StatusTimeout       = "TIMEOUT"
+	StatusRefunded      = "REFUNDED"
 )
+
+const (
+	ActorSystem           = "system"
+	ActorPartnerStatus    = "partner-status-event"
+	ActorDeadLetterReplay = "dead-letter-replay"
+)
+
+const (
+	ChannelOnline  = "online"
+	ChannelBifast  = "bifast"
+	ChannelSknbi   = "sknbi"
+	ChannelRtgs    = "rtgs"
+	ChannelVA      = "va"
+	ChannelWallet  = "wallet"
+	ChannelDeposit = "deposit"
+)
+
+var TransferChannels = map[string]bool{
+	ChannelOnline: true,
+	ChannelBifast: true,
+	ChannelSknbi:  true,
+	ChannelRtgs:   true,
+	ChannelVA:     true,
+	ChannelWallet: true,
+}
+
+// DedicatedChannels only accepted through their own endpoint, which runs the channel checks before transfer pipeline
+var DedicatedChannels = map[string]bool{
+	ChannelVA:     true,
+	ChannelWallet: true,
+}
+
+const (
+	OutOfWindowReject = "reject"
+	OutOfWindowQueue  = "queue"
+)
+
+const (
+	DepositSourceVA     = "va"
+	DepositSourceManual = "manual"
+)
+
+const (
+	StatusCredited = "CREDITED"
+)
+
+const (
+	AccountMerchantBalance = "MERCHANT_BALANCE"
+	AccountPartnerPayable  = "PARTNER_PAYABLE"
+	AccountCompanyRevenue  = "COMPANY_REVENUE"
+	AccountTaxPayable      = "TAX_PAYABLE"
+	AccountSettlement      = "SETTLEMENT"
+	AccountFeeRounding     = "FEE_ROUNDING"
+)
+
+const (
+	JournalTransfer = "TRANSFER"
+	JournalRefund   = "REFUND"
+	JournalDeposit  = "DEPOSIT"
+	JournalReversal = "REVERSAL"
+)
+
+const (
+	BatchProcessing = "PROCESSING"
+	BatchCompleted  = "COMPLETED"
+)
+
+const (
+	BatchItemQueued   = "QUEUED"
+	BatchItemAccepted = "ACCEPTED"
+	BatchItemFailed   = "FAILED"
+
+	// BatchItemSubmitting is row handed to transfer pipeline, only such row may have executed before restart
+	BatchItemSubmitting = "SUBMITTING"
+
+	// BatchItemScheduled is row outside channel window queued as single scheduled transfer, its reservation is released
+	BatchItemScheduled = "SCHEDULED"
+)
+
+const (
+	RecurrenceOnce    = "once"
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+const (
+	ScheduleActive    = "ACTIVE"
+	ScheduleCompleted = "COMPLETED"
+	ScheduleFailed    = "FAILED"
+	ScheduleCancelled = "CANCELLED"
+)
+
+const (
+	ScheduleRunClaimed  = "CLAIMED"
+	ScheduleRunAccepted = "ACCEPTED"
+	ScheduleRunFailed   = "FAILED"
+)
+
+const (
+	EventScheduledTransferFailed = "SCHEDULED_TRANSFER_FAILED"
+	EventTransferStatusChanged   = "TRANSFER_STATUS_CHANGED"
+)
+
+const (
+	WebhookPending    = "PENDING"
+	WebhookDelivered  = "DELIVERED"
+	WebhookDeadLetter = "DEAD_LETTER"
+)
+
+const (
+	HealthUp       = "UP"
+	HealthDown     = "DOWN"
+	HealthDraining = "DRAINING"
+)
+
+const (
+	DependencyPostgres     = "postgres"
+	DependencyRedis        = "redis"
+	DependencyKafka        = "kafka"
+	DependencyPartnerCache = "partner_cache"
+	DependencyFeeCache     = "fee_cache"
+)
+
+const (
+	VirtualAccountClosed = "CLOSED"
+	VirtualAccountOpen   = "OPEN"
+)
+
+const (
+	BankCodeBCA     = "014"
+	BankCodeBRI     = "002"
+	BankCodeCIMB    = "022"
+	BankCodePermata = "013"
+)
+
+const (
+	TransferTypeIntrabank = "INTRABANK"
+	TransferTypeInterbank = "INTERBANK"
+	TransferTypeVA        = "VA"
+	TransferTypeSKN       = "SKN"
+	TransferTypeRTGS      = "RTGS"
+)
+
+// canonical outcome of partner response code
+const (
+	OutcomeSuccess         = "SUCCESS"
+	OutcomePending         = "PENDING"
+	OutcomeFailedFinal     = "FAILED_FINAL"
+	OutcomeFailedRetryable = "FAILED_RETRYABLE"
+	OutcomeSuspect         = "SUSPECT"
+)
+
+var PartnerOutcomes = map[string]bool{
+	OutcomeSuccess:         true,
+	OutcomePending:         true,
+	OutcomeFailedFinal:     true,
+	OutcomeFailedRetryable: true,
+	OutcomeSuspect:         true,
+}
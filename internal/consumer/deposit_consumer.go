@@ -0,0 +1,56 @@
+package consumer
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/kafkahelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/sirupsen/logrus"
+)
+
+type depositConsumer struct {
+	svc service.DepositService
+}
+
+func NewDepositConsumer(svc service.DepositService) *depositConsumer {
+	return &depositConsumer{svc}
+}
+
+func (d *depositConsumer) Handle(ctx context.Context, message *sarama.ConsumerMessage) error {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "deposit_consumer",
+		"topic":     message.Topic,
+		"partition": message.Partition,
+		"offset":    message.Offset,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	log.Info("Parsing deposit message")
+	var request dto.DepositRequest
+	if err := json.Unmarshal(message.Value, &request); err != nil {
+		return fmt.Errorf("%w: invalid deposit message, with error: %v", kafkahelper.ErrPoisonMessage, err)
+	}
+
+	// confirmed deposit from payment channel is always virtual account payment
+	if request.Source == "" {
+		request.Source = constants.DepositSourceVA
+	}
+
+	response := d.svc.Deposit(ctx, request)
+	switch response.ResponseCode {
+	case constants.ErrInternalServerError:
+		return fmt.Errorf("failed to credit deposit %s", request.DepositReference)
+	case constants.TransferSuccess:
+		log.Info("Deposit message processed")
+	default:
+		log.Errorf("Deposit message rejected with code %s: %s", response.ResponseCode, response.ResponseMessage)
+	}
+
+	return nil
+}
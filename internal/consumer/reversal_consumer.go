@@ -0,0 +1,53 @@
+package consumer
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/kafkahelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/sirupsen/logrus"
+)
+
+const reversalEventActor = "bank-reversal-event"
+
+type reversalConsumer struct {
+	svc service.ReversalService
+}
+
+func NewReversalConsumer(svc service.ReversalService) *reversalConsumer {
+	return &reversalConsumer{svc}
+}
+
+func (r *reversalConsumer) Handle(ctx context.Context, message *sarama.ConsumerMessage) error {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "reversal_consumer",
+		"topic":     message.Topic,
+		"partition": message.Partition,
+		"offset":    message.Offset,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	log.Info("Parsing reversal message")
+	var request dto.ReversalRequest
+	if err := json.Unmarshal(message.Value, &request); err != nil {
+		return fmt.Errorf("%w: invalid reversal message, with error: %v", kafkahelper.ErrPoisonMessage, err)
+	}
+
+	response := r.svc.Reverse(ctx, request, reversalEventActor)
+	switch response.ResponseCode {
+	case constants.ErrInternalServerError:
+		return fmt.Errorf("failed to reverse transfer %s", request.OriginalPartnerReferenceNo)
+	case constants.TransferSuccess, constants.ErrAlreadyReversed:
+		log.Info("Reversal message processed")
+	default:
+		log.Errorf("Reversal message rejected with code %s: %s", response.ResponseCode, response.ResponseMessage)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package consumer
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/kafkahelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/protobuf"
+	"briefcash-transfer/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+)
+
+type transferStatusConsumer struct {
+	svc service.TransferStatusService
+}
+
+func NewTransferStatusConsumer(svc service.TransferStatusService) *transferStatusConsumer {
+	return &transferStatusConsumer{svc}
+}
+
+func (t *transferStatusConsumer) Handle(ctx context.Context, message *sarama.ConsumerMessage) error {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "transfer_status_consumer",
+		"topic":     message.Topic,
+		"partition": message.Partition,
+		"offset":    message.Offset,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	log.Info("Parsing transfer status message")
+	event, err := decodeStatusEvent(message.Value)
+	if err != nil {
+		return fmt.Errorf("%w: invalid transfer status message, with error: %v", kafkahelper.ErrPoisonMessage, err)
+	}
+
+	responseCode := t.svc.ApplyStatus(ctx, event)
+	switch responseCode {
+	case constants.ErrInternalServerError:
+		return fmt.Errorf("failed to apply status %s to transfer %s", event.Status, event.PartnerReferenceNo)
+	case constants.TransferSuccess, constants.ErrInvalidStatus:
+		log.Info("Transfer status message processed")
+	default:
+		log.Errorf("Transfer status message rejected with code %s: %s", responseCode, constants.ResponseMap[responseCode])
+	}
+
+	return nil
+}
+
+// decodeStatusEvent accept legacy json event and protobuf TransferResult, partner service migrate to protobuf one by one
+func decodeStatusEvent(value []byte) (dto.TransferStatusEvent, error) {
+	var event dto.TransferStatusEvent
+	if len(value) > 0 && value[0] == '{' {
+		err := json.Unmarshal(value, &event)
+		return event, err
+	}
+
+	var result protobuf.TransferResult
+	if err := proto.Unmarshal(value, &result); err != nil {
+		return event, err
+	}
+	if result.GetPartnerRefNo() == "" || result.GetReferenceNumber() == "" {
+		return event, fmt.Errorf("transfer result has no partner reference number or reference number")
+	}
+	return dto.TransferStatusEvent{
+		PartnerReferenceNo: result.GetPartnerRefNo(),
+		ReferenceNumber:    result.GetReferenceNumber(),
+		Status:             result.GetStatus(),
+		BankReferenceNo:    result.GetBankReferenceNo(),
+		Reason:             result.GetResponseMessage(),
+	}, nil
+}
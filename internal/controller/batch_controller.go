@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/service"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type batchController struct {
+	svc service.BatchService
+}
+
+func NewBatchController(svc service.BatchService) *batchController {
+	return &batchController{svc}
+}
+
+var batchHttpStatus = map[string]int{
+	constants.TransferSuccess:        http.StatusOK,
+	constants.PendingTransfer:        http.StatusAccepted,
+	constants.ErrBadRequest:          http.StatusBadRequest,
+	constants.ErrDataNotFound:        http.StatusNotFound,
+	constants.ErrBalanceNotAvailable: http.StatusNotFound,
+	constants.ErrInsufficientFunds:   http.StatusForbidden,
+	constants.ErrDuplicateReference:  http.StatusConflict,
+	constants.ErrInternalServerError: http.StatusInternalServerError,
+}
+
+// Submit accept json body or multipart csv upload in field file with batchReference form value
+func (b *batchController) Submit(ctx *gin.Context) {
+	var request dto.BatchTransferRequest
+	externalId := ctx.GetHeader("X-EXTERNAL-ID")
+	merchantCode := ctx.GetHeader("X-PARTNER-ID")
+
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":     "batch_controller",
+		"trace_id":    externalId,
+		"merchant_id": merchantCode,
+	})
+	reqCtx := requestContext(ctx, log)
+
+	log.Info("Parsing payload request")
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		request.BatchReference = ctx.PostForm("batchReference")
+		transfers, err := b.parseUpload(ctx)
+		if err != nil {
+			log.WithError(err).Warn("Invalid batch file")
+			b.badRequest(ctx, request.BatchReference)
+			return
+		}
+		request.Transfers = transfers
+	} else if err := ctx.ShouldBindJSON(&request); err != nil {
+		b.badRequest(ctx, "")
+		return
+	}
+
+	response := b.svc.Submit(reqCtx, request, merchantCode, externalId)
+
+	log.Info("Populate response")
+	ctx.JSON(batchHttpStatus[response.ResponseCode], response)
+}
+
+func (b *batchController) Status(ctx *gin.Context) {
+	merchantCode := ctx.GetHeader("X-PARTNER-ID")
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":     "batch_controller",
+		"trace_id":    ctx.GetHeader("X-EXTERNAL-ID"),
+		"merchant_id": merchantCode,
+	})
+	reqCtx := requestContext(ctx, log)
+
+	response := b.svc.GetBatch(reqCtx, merchantCode, ctx.Param("batchReference"))
+	ctx.JSON(batchHttpStatus[response.ResponseCode], response)
+}
+
+func (b *batchController) Result(ctx *gin.Context) {
+	merchantCode := ctx.GetHeader("X-PARTNER-ID")
+	batchReference := ctx.Param("batchReference")
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":     "batch_controller",
+		"trace_id":    ctx.GetHeader("X-EXTERNAL-ID"),
+		"merchant_id": merchantCode,
+	})
+	reqCtx := requestContext(ctx, log)
+
+	file, err := b.svc.ResultFile(reqCtx, merchantCode, batchReference)
+	if err != nil {
+		log.WithError(err).Warn("Failed to build batch result file")
+		ctx.JSON(http.StatusNotFound, dto.BatchTransferResponse{
+			ResponseCode:    constants.ErrDataNotFound,
+			ResponseMessage: constants.ResponseMap[constants.ErrDataNotFound],
+			BatchReference:  batchReference,
+			TransactionDate: timehelper.FormatTimeToISO7(time.Now()),
+		})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", batchReference+"-result.csv"))
+	ctx.Data(http.StatusOK, "text/csv", file)
+}
+
+func (b *batchController) parseUpload(ctx *gin.Context) ([]dto.TransferRequest, error) {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return service.ParseBatchCSV(file)
+}
+
+func (b *batchController) badRequest(ctx *gin.Context, batchReference string) {
+	ctx.JSON(http.StatusBadRequest, dto.BatchTransferResponse{
+		ResponseCode:    constants.ErrBadRequest,
+		ResponseMessage: constants.ResponseMap[constants.ErrBadRequest],
+		BatchReference:  batchReference,
+		TransactionDate: timehelper.FormatTimeToISO7(time.Now()),
+	})
+}
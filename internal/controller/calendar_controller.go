@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/middleware"
+	"briefcash-transfer/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type calendarController struct {
+	svc service.CalendarService
+}
+
+func NewCalendarController(svc service.CalendarService) *calendarController {
+	return &calendarController{svc}
+}
+
+var calendarHttpStatus = map[string]int{
+	constants.TransferSuccess:        http.StatusOK,
+	constants.ErrBadRequest:          http.StatusBadRequest,
+	constants.ErrInternalServerError: http.StatusInternalServerError,
+}
+
+func (c *calendarController) AddHoliday(ctx *gin.Context) {
+	var request dto.HolidayRequest
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":  "calendar_controller",
+		"trace_id": ctx.GetHeader("X-EXTERNAL-ID"),
+		"admin_id": middleware.AdminId(ctx),
+	})
+	reqCtx := requestContext(ctx, log)
+
+	log.Info("Parsing payload request")
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.CalendarResponse{
+			ResponseCode:    constants.ErrBadRequest,
+			ResponseMessage: constants.ResponseMap[constants.ErrBadRequest],
+		})
+		return
+	}
+
+	response := c.svc.AddHoliday(reqCtx, request)
+	ctx.JSON(calendarHttpStatus[response.ResponseCode], response)
+}
+
+// ListHolidays show holidays of year, current year in WIB by default, with active channel cutoff
+func (c *calendarController) ListHolidays(ctx *gin.Context) {
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":  "calendar_controller",
+		"trace_id": ctx.GetHeader("X-EXTERNAL-ID"),
+	})
+	reqCtx := requestContext(ctx, log)
+
+	year, err := strconv.Atoi(ctx.DefaultQuery("year", strconv.Itoa(time.Now().In(timehelper.WIB).Year())))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.CalendarResponse{
+			ResponseCode:    constants.ErrBadRequest,
+			ResponseMessage: constants.ResponseMap[constants.ErrBadRequest],
+		})
+		return
+	}
+
+	response := c.svc.ListHolidays(reqCtx, year)
+	ctx.JSON(calendarHttpStatus[response.ResponseCode], response)
+}
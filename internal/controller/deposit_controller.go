@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type depositController struct {
+	svc service.DepositService
+}
+
+func NewDepositController(svc service.DepositService) *depositController {
+	return &depositController{svc}
+}
+
+func (d *depositController) Deposit(ctx *gin.Context) {
+	var request dto.DepositRequest
+	externalId := ctx.GetHeader("X-EXTERNAL-ID")
+
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":  "deposit_controller",
+		"trace_id": externalId,
+	})
+	reqCtx := requestContext(ctx, log)
+
+	log.Info("Parsing payload request")
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.DepositResponse{
+			ResponseCode:    constants.ErrBadRequest,
+			ResponseMessage: constants.ResponseMap[constants.ErrBadRequest],
+			TransactionDate: timehelper.FormatTimeToISO7(time.Now()),
+		})
+		return
+	}
+
+	// finance team credit is always manual, virtual account payment comes from kafka
+	if request.Source == "" {
+		request.Source = constants.DepositSourceManual
+	}
+
+	response := d.svc.Deposit(reqCtx, request)
+
+	httpStatus := map[string]int{
+		constants.TransferSuccess:        http.StatusOK,
+		constants.ErrBadRequest:          http.StatusBadRequest,
+		constants.ErrBalanceNotAvailable: http.StatusNotFound,
+		constants.ErrDuplicateReference:  http.StatusConflict,
+		constants.ErrInternalServerError: http.StatusInternalServerError,
+	}
+
+	log.Info("Populate response")
+	ctx.JSON(httpStatus[response.ResponseCode], response)
+}
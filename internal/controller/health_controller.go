@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type healthController struct {
+	svc service.HealthService
+}
+
+func NewHealthController(svc service.HealthService) *healthController {
+	return &healthController{svc}
+}
+
+func (c *healthController) Live(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.svc.Liveness())
+}
+
+// Ready respond 503 when any dependency is down or service is draining
+func (c *healthController) Ready(ctx *gin.Context) {
+	response := c.svc.Readiness(ctx.Request.Context())
+	if response.Status != constants.HealthUp {
+		ctx.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
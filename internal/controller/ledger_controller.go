@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ledgerController struct {
+	svc service.LedgerService
+}
+
+func NewLedgerController(svc service.LedgerService) *ledgerController {
+	return &ledgerController{svc}
+}
+
+// TrialBalance report journal totals per account, from and to are inclusive dates in yyyy-mm-dd
+func (l *ledgerController) TrialBalance(ctx *gin.Context) {
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":  "ledger_controller",
+		"trace_id": ctx.GetHeader("X-EXTERNAL-ID"),
+	})
+	reqCtx := requestContext(ctx, log)
+
+	location := time.FixedZone("WIB", 7*60*60)
+	today := time.Now().In(location).Format(time.DateOnly)
+
+	from, errFrom := time.ParseInLocation(time.DateOnly, ctx.DefaultQuery("from", today), location)
+	to, errTo := time.ParseInLocation(time.DateOnly, ctx.DefaultQuery("to", today), location)
+	if errFrom != nil || errTo != nil || to.Before(from) {
+		ctx.JSON(http.StatusBadRequest, dto.TrialBalanceResponse{
+			ResponseCode:    constants.ErrBadRequest,
+			ResponseMessage: constants.ResponseMap[constants.ErrBadRequest],
+		})
+		return
+	}
+
+	response := l.svc.TrialBalance(reqCtx, from, to.AddDate(0, 0, 1))
+	if response.ResponseCode != constants.TransferSuccess {
+		ctx.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"briefcash-transfer/internal/helper/loghelper"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// requestContext carry request logger and trace span to service, detached from client cancellation
+// so money movement is never aborted halfway when caller disconnects
+func requestContext(ctx *gin.Context, log *logrus.Entry) context.Context {
+	return loghelper.NewContext(context.WithoutCancel(ctx.Request.Context()), log)
+}
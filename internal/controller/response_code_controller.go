@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/middleware"
+	"briefcash-transfer/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type responseCodeController struct {
+	svc service.ResponseCodeService
+}
+
+func NewResponseCodeController(svc service.ResponseCodeService) *responseCodeController {
+	return &responseCodeController{svc}
+}
+
+var responseCodeHttpStatus = map[string]int{
+	constants.TransferSuccess:        http.StatusOK,
+	constants.ErrBadRequest:          http.StatusBadRequest,
+	constants.ErrInternalServerError: http.StatusInternalServerError,
+}
+
+// List show response code mapping of a partner bank given by bankCode query
+func (c *responseCodeController) List(ctx *gin.Context) {
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":  "response_code_controller",
+		"trace_id": ctx.GetHeader("X-EXTERNAL-ID"),
+	})
+	reqCtx := requestContext(ctx, log)
+
+	response := c.svc.List(reqCtx, ctx.Query("bankCode"))
+	ctx.JSON(responseCodeHttpStatus[response.ResponseCode], response)
+}
+
+func (c *responseCodeController) Save(ctx *gin.Context) {
+	var request dto.ResponseCodeRequest
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":  "response_code_controller",
+		"trace_id": ctx.GetHeader("X-EXTERNAL-ID"),
+		"admin_id": middleware.AdminId(ctx),
+	})
+	reqCtx := requestContext(ctx, log)
+
+	log.Info("Parsing payload request")
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseCodeResponse{
+			ResponseCode:    constants.ErrBadRequest,
+			ResponseMessage: constants.ResponseMap[constants.ErrBadRequest],
+		})
+		return
+	}
+
+	response := c.svc.Save(reqCtx, request)
+	ctx.JSON(responseCodeHttpStatus[response.ResponseCode], response)
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/middleware"
+	"briefcash-transfer/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type reversalController struct {
+	svc service.ReversalService
+}
+
+func NewReversalController(svc service.ReversalService) *reversalController {
+	return &reversalController{svc}
+}
+
+func (r *reversalController) Reverse(ctx *gin.Context) {
+	var request dto.ReversalRequest
+	externalId := ctx.GetHeader("X-EXTERNAL-ID")
+	adminId := middleware.AdminId(ctx)
+
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":  "reversal_controller",
+		"trace_id": externalId,
+		"admin_id": adminId,
+	})
+	reqCtx := requestContext(ctx, log)
+
+	log.Info("Parsing payload request")
+	if err := ctx.ShouldBindJSON(&request); err != nil || adminId == "" {
+		ctx.JSON(http.StatusBadRequest, dto.ReversalResponse{
+			ResponseCode:    constants.ErrBadRequest,
+			ResponseMessage: constants.ResponseMap[constants.ErrBadRequest],
+			TransactionDate: timehelper.FormatTimeToISO7(time.Now()),
+		})
+		return
+	}
+
+	response := r.svc.Reverse(reqCtx, request, adminId)
+
+	httpStatus := map[string]int{
+		constants.TransferSuccess:        http.StatusOK,
+		constants.ErrBadRequest:          http.StatusBadRequest,
+		constants.ErrDataNotFound:        http.StatusNotFound,
+		constants.ErrInvalidStatus:       http.StatusConflict,
+		constants.ErrAlreadyReversed:     http.StatusConflict,
+		constants.ErrInternalServerError: http.StatusInternalServerError,
+	}
+
+	log.Info("Populate response")
+	ctx.JSON(httpStatus[response.ResponseCode], response)
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type scheduleController struct {
+	svc service.ScheduleService
+}
+
+func NewScheduleController(svc service.ScheduleService) *scheduleController {
+	return &scheduleController{svc}
+}
+
+var scheduleHttpStatus = map[string]int{
+	constants.TransferSuccess:        http.StatusOK,
+	constants.PendingTransfer:        http.StatusAccepted,
+	constants.ErrBadRequest:          http.StatusBadRequest,
+	constants.ErrDataNotFound:        http.StatusNotFound,
+	constants.ErrInvalidStatus:       http.StatusConflict,
+	constants.ErrDuplicateReference:  http.StatusConflict,
+	constants.ErrInternalServerError: http.StatusInternalServerError,
+}
+
+func (s *scheduleController) Schedule(ctx *gin.Context) {
+	var request dto.ScheduleTransferRequest
+	merchantCode := ctx.GetHeader("X-PARTNER-ID")
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":     "schedule_controller",
+		"trace_id":    ctx.GetHeader("X-EXTERNAL-ID"),
+		"merchant_id": merchantCode,
+	})
+	reqCtx := requestContext(ctx, log)
+
+	log.Info("Parsing payload request")
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ScheduleTransferResponse{
+			ResponseCode:    constants.ErrBadRequest,
+			ResponseMessage: constants.ResponseMap[constants.ErrBadRequest],
+			TransactionDate: timehelper.FormatTimeToISO7(time.Now()),
+		})
+		return
+	}
+
+	response := s.svc.Schedule(reqCtx, request, merchantCode)
+	ctx.JSON(scheduleHttpStatus[response.ResponseCode], response)
+}
+
+func (s *scheduleController) List(ctx *gin.Context) {
+	merchantCode := ctx.GetHeader("X-PARTNER-ID")
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":     "schedule_controller",
+		"trace_id":    ctx.GetHeader("X-EXTERNAL-ID"),
+		"merchant_id": merchantCode,
+	})
+	reqCtx := requestContext(ctx, log)
+
+	response := s.svc.List(reqCtx, merchantCode, ctx.Query("status"))
+	ctx.JSON(scheduleHttpStatus[response.ResponseCode], response)
+}
+
+func (s *scheduleController) Cancel(ctx *gin.Context) {
+	merchantCode := ctx.GetHeader("X-PARTNER-ID")
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":     "schedule_controller",
+		"trace_id":    ctx.GetHeader("X-EXTERNAL-ID"),
+		"merchant_id": merchantCode,
+	})
+	reqCtx := requestContext(ctx, log)
+
+	response := s.svc.Cancel(reqCtx, merchantCode, ctx.Param("scheduleReference"))
+	ctx.JSON(scheduleHttpStatus[response.ResponseCode], response)
+}
@@ -5,20 +5,24 @@ import (
 	"briefcash-transfer/internal/dto"
 	"briefcash-transfer/internal/helper/loghelper"
 	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/helper/tracehelper"
 	"briefcash-transfer/internal/service"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type transferController struct {
-	svc service.TransferService
+	svc         service.TransferService
+	scheduleSvc service.ScheduleService
+	calendarSvc service.CalendarService
 }
 
-func NewTransferController(svc service.TransferService) *transferController {
-	return &transferController{svc}
+func NewTransferController(svc service.TransferService, scheduleSvc service.ScheduleService, calendarSvc service.CalendarService) *transferController {
+	return &transferController{svc, scheduleSvc, calendarSvc}
 }
 
 func (t *transferController) Transfer(ctx *gin.Context) {
@@ -28,12 +32,18 @@ func (t *transferController) Transfer(ctx *gin.Context) {
 	externalId := ctx.GetHeader("X-EXTERNAL-ID")
 	merchantCode := ctx.GetHeader("X-PARTNER-ID")
 
-	log := loghelper.Logger.WithFields(logrus.Fields{
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
 		"service":     "transfer_controller",
 		"trace_id":    externalId,
 		"merchant_id": merchantCode,
 	})
 
+	reqCtx, span := tracehelper.StartSpan(requestContext(ctx, log), "TransferController.Transfer",
+		attribute.String("external.id", externalId),
+		attribute.String("merchant.code", merchantCode),
+	)
+	defer span.End()
+
 	defer func() {
 		log.WithField("processing_time", time.Since(start).Milliseconds())
 	}()
@@ -50,12 +60,69 @@ func (t *transferController) Transfer(ctx *gin.Context) {
 		return
 	}
 
-	response := t.svc.TransferRequest(ctx, request, merchantCode, externalId)
+	if constants.DedicatedChannels[request.AdditionalInfo.Channel] {
+		log.Warnf("Channel %s is only accepted through its own endpoint", request.AdditionalInfo.Channel)
+		ctx.JSON(http.StatusBadRequest, dto.TransferResponse{
+			ResponseCode:       constants.ErrBadRequest,
+			ResponseMessage:    constants.ResponseMap[constants.ErrBadRequest],
+			PartnerReferenceNo: request.PartnerReferenceNo,
+			TransactionDate:    timehelper.FormatTimeToISO7(time.Now()),
+		})
+		return
+	}
+
+	// future dated transfer and transfer queued for next channel window are stored as single scheduled transfer
+	requestedAt := time.Now()
+	if transactionDate, err := timehelper.ParseDateTime(request.AdditionalInfo.TransactionDate); err == nil && transactionDate.After(requestedAt.Add(time.Minute)) {
+		requestedAt = transactionDate
+	}
+
+	executeAt, open := t.calendarSvc.ExecutionTime(request.AdditionalInfo.Channel, requestedAt)
+	if !open && t.calendarSvc.OutOfWindowAction(reqCtx, merchantCode) == constants.OutOfWindowReject {
+		log.Warnf("Channel %s is outside operating window, rejecting transfer", request.AdditionalInfo.Channel)
+		ctx.JSON(http.StatusForbidden, dto.TransferResponse{
+			ResponseCode:       constants.ErrOutsideWindow,
+			ResponseMessage:    constants.ResponseMap[constants.ErrOutsideWindow],
+			PartnerReferenceNo: request.PartnerReferenceNo,
+			TransactionDate:    timehelper.FormatTimeToISO7(time.Now()),
+			AdditionalInfo:     map[string]string{"expected_execution_date": timehelper.FormatTimeToISO7(executeAt)},
+		})
+		return
+	}
+
+	if executeAt.After(time.Now().Add(time.Minute)) {
+		log.Infof("Scheduling transfer for execution at %s", timehelper.FormatTimeToISO7(executeAt))
+		scheduled := t.scheduleSvc.Schedule(reqCtx, dto.ScheduleTransferRequest{
+			ScheduleReference: request.PartnerReferenceNo,
+			ExecuteAt:         timehelper.FormatTimeToISO7(executeAt),
+			Recurrence:        constants.RecurrenceOnce,
+			Transfer:          request,
+		}, merchantCode)
+		ctx.JSON(scheduleHttpStatus[scheduled.ResponseCode], dto.TransferResponse{
+			ResponseCode:       scheduled.ResponseCode,
+			ResponseMessage:    scheduled.ResponseMessage,
+			PartnerReferenceNo: request.PartnerReferenceNo,
+			TransactionDate:    scheduled.TransactionDate,
+			AdditionalInfo: map[string]string{
+				"expected_execution_date": timehelper.FormatTimeToISO7(executeAt),
+				"schedule_reference":      request.PartnerReferenceNo,
+			},
+		})
+		return
+	}
+
+	span.SetAttributes(attribute.String("transfer.reference", request.PartnerReferenceNo), attribute.String("transfer.channel", request.AdditionalInfo.Channel))
+	response := t.svc.TransferRequest(reqCtx, request, merchantCode, externalId)
+	span.SetAttributes(attribute.String("transfer.response_code", response.ResponseCode))
 
 	httpStatus := map[string]int{
+		constants.ErrBadRequest:          http.StatusBadRequest,
 		constants.ErrDataNotFound:        http.StatusNotFound,
 		constants.ErrInsufficientFunds:   http.StatusForbidden,
+		constants.ErrOutsideWindow:       http.StatusForbidden,
 		constants.ErrInternalServerError: http.StatusInternalServerError,
+		constants.ErrServiceUnavailable:  http.StatusServiceUnavailable,
+		constants.ErrServiceBusy:         http.StatusServiceUnavailable,
 		constants.PendingTransfer:        http.StatusAccepted,
 	}
 
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type walletController struct {
+	svc service.WalletService
+}
+
+func NewWalletController(svc service.WalletService) *walletController {
+	return &walletController{svc}
+}
+
+var walletHttpStatus = map[string]int{
+	constants.TransferSuccess:        http.StatusOK,
+	constants.PendingTransfer:        http.StatusAccepted,
+	constants.ErrBadRequest:          http.StatusBadRequest,
+	constants.ErrInsufficientFunds:   http.StatusForbidden,
+	constants.ErrOutsideWindow:       http.StatusForbidden,
+	constants.ErrAmountLimit:         http.StatusForbidden,
+	constants.ErrDataNotFound:        http.StatusNotFound,
+	constants.ErrInvalidAmount:       http.StatusBadRequest,
+	constants.ErrBalanceNotAvailable: http.StatusNotFound,
+	constants.ErrInternalServerError: http.StatusInternalServerError,
+	constants.ErrExternalServerError: http.StatusBadGateway,
+	constants.ErrServiceUnavailable:  http.StatusServiceUnavailable,
+	constants.ErrServiceBusy:         http.StatusServiceUnavailable,
+	constants.ErrTransferTimeout:     http.StatusGatewayTimeout,
+}
+
+func (c *walletController) Inquiry(ctx *gin.Context) {
+	var request dto.WalletInquiryRequest
+	externalId := ctx.GetHeader("X-EXTERNAL-ID")
+	merchantCode := ctx.GetHeader("X-PARTNER-ID")
+
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":     "wallet_controller",
+		"trace_id":    externalId,
+		"merchant_id": merchantCode,
+	})
+	reqCtx := requestContext(ctx, log)
+
+	log.Info("Parsing payload request")
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.WalletInquiryResponse{
+			ResponseCode:    constants.ErrBadRequest,
+			ResponseMessage: constants.ResponseMap[constants.ErrBadRequest],
+		})
+		return
+	}
+
+	response := c.svc.Inquiry(reqCtx, request, merchantCode, externalId)
+	ctx.JSON(walletHttpStatus[response.ResponseCode], response)
+}
+
+func (c *walletController) Topup(ctx *gin.Context) {
+	var request dto.WalletTopupRequest
+	externalId := ctx.GetHeader("X-EXTERNAL-ID")
+	merchantCode := ctx.GetHeader("X-PARTNER-ID")
+
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":     "wallet_controller",
+		"trace_id":    externalId,
+		"merchant_id": merchantCode,
+	})
+	reqCtx := requestContext(ctx, log)
+
+	log.Info("Parsing payload request")
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.TransferResponse{
+			ResponseCode:    constants.ErrBadRequest,
+			ResponseMessage: constants.ResponseMap[constants.ErrBadRequest],
+			TransactionDate: timehelper.FormatTimeToISO7(time.Now()),
+		})
+		return
+	}
+
+	response := c.svc.Topup(reqCtx, request, merchantCode, externalId)
+	ctx.JSON(walletHttpStatus[response.ResponseCode], response)
+}
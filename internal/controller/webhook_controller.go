@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/middleware"
+	"briefcash-transfer/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type webhookController struct {
+	svc service.WebhookService
+}
+
+func NewWebhookController(svc service.WebhookService) *webhookController {
+	return &webhookController{svc}
+}
+
+var webhookHttpStatus = map[string]int{
+	constants.TransferSuccess:        http.StatusOK,
+	constants.ErrBadRequest:          http.StatusBadRequest,
+	constants.ErrDataNotFound:        http.StatusNotFound,
+	constants.ErrInternalServerError: http.StatusInternalServerError,
+}
+
+// RegisterEndpoint set callback url and signing secret of merchant
+func (w *webhookController) RegisterEndpoint(ctx *gin.Context) {
+	var request dto.WebhookEndpointRequest
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":  "webhook_controller",
+		"trace_id": ctx.GetHeader("X-EXTERNAL-ID"),
+		"admin_id": middleware.AdminId(ctx),
+	})
+	reqCtx := requestContext(ctx, log)
+
+	log.Info("Parsing payload request")
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		w.badRequest(ctx)
+		return
+	}
+
+	response := w.svc.RegisterEndpoint(reqCtx, ctx.Param("merchantCode"), request)
+	ctx.JSON(webhookHttpStatus[response.ResponseCode], response)
+}
+
+// Deliveries list latest deliveries, status DEAD_LETTER lists the dead letter store
+func (w *webhookController) Deliveries(ctx *gin.Context) {
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":  "webhook_controller",
+		"trace_id": ctx.GetHeader("X-EXTERNAL-ID"),
+	})
+	reqCtx := requestContext(ctx, log)
+
+	response := w.svc.ListDeliveries(reqCtx, ctx.Query("merchantCode"), ctx.Query("status"))
+	ctx.JSON(webhookHttpStatus[response.ResponseCode], response)
+}
+
+// Delivery show single delivery with every attempt made
+func (w *webhookController) Delivery(ctx *gin.Context) {
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":  "webhook_controller",
+		"trace_id": ctx.GetHeader("X-EXTERNAL-ID"),
+	})
+	reqCtx := requestContext(ctx, log)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		w.badRequest(ctx)
+		return
+	}
+
+	response := w.svc.GetDelivery(reqCtx, id)
+	ctx.JSON(webhookHttpStatus[response.ResponseCode], response)
+}
+
+func (w *webhookController) Resend(ctx *gin.Context) {
+	adminId := middleware.AdminId(ctx)
+	log := loghelper.FromContext(ctx.Request.Context()).WithFields(logrus.Fields{
+		"service":  "webhook_controller",
+		"trace_id": ctx.GetHeader("X-EXTERNAL-ID"),
+		"admin_id": adminId,
+	})
+	reqCtx := requestContext(ctx, log)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil || adminId == "" {
+		w.badRequest(ctx)
+		return
+	}
+
+	response := w.svc.Resend(reqCtx, id, adminId)
+	ctx.JSON(webhookHttpStatus[response.ResponseCode], response)
+}
+
+func (w *webhookController) badRequest(ctx *gin.Context) {
+	ctx.JSON(http.StatusBadRequest, dto.WebhookResponse{
+		ResponseCode:    constants.ErrBadRequest,
+		ResponseMessage: constants.ResponseMap[constants.ErrBadRequest],
+		TransactionDate: timehelper.FormatTimeToISO7(time.Now()),
+	})
+}
@@ -0,0 +1,29 @@
+package dto
+
+type BatchTransferRequest struct {
+	BatchReference string            `json:"batchReference"`
+	Transfers      []TransferRequest `json:"transfers"`
+}
+
+type BatchTransferResponse struct {
+	ResponseCode    string            `json:"responseCode"`
+	ResponseMessage string            `json:"responseMessage"`
+	BatchReference  string            `json:"batchReference"`
+	Status          string            `json:"status"`
+	TotalRows       int               `json:"totalRows"`
+	SuccessCount    int               `json:"successCount"`
+	FailedCount     int               `json:"failedCount"`
+	ReservedAmount  string            `json:"reservedAmount"`
+	TransactionDate string            `json:"transactionDate"`
+	Rows            []BatchRowResult  `json:"rows"`
+	AdditionalInfo  map[string]string `json:"additionalInfo"`
+}
+
+type BatchRowResult struct {
+	RowNumber          int    `json:"rowNumber"`
+	PartnerReferenceNo string `json:"partnerReferenceNo"`
+	Status             string `json:"status"`
+	ResponseCode       string `json:"responseCode"`
+	ResponseMessage    string `json:"responseMessage"`
+	ReferenceNumber    string `json:"referenceNumber"`
+}
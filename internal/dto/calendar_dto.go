@@ -0,0 +1,25 @@
+package dto
+
+type HolidayRequest struct {
+	Date        string `json:"date"` // yyyy-mm-dd
+	Description string `json:"description"`
+}
+
+type CalendarResponse struct {
+	ResponseCode    string          `json:"responseCode"`
+	ResponseMessage string          `json:"responseMessage"`
+	Holidays        []HolidayInfo   `json:"holidays"`
+	Cutoffs         []ChannelWindow `json:"cutoffs"`
+}
+
+type HolidayInfo struct {
+	Date        string `json:"date"`
+	Description string `json:"description"`
+}
+
+type ChannelWindow struct {
+	Channel          string `json:"channel"`
+	OpenTime         string `json:"openTime"`
+	CutoffTime       string `json:"cutoffTime"`
+	BusinessDaysOnly bool   `json:"businessDaysOnly"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+type DepositRequest struct {
+	DepositReference string             `json:"depositReference"`
+	MerchantCode     string             `json:"merchantCode"`
+	Source           string             `json:"source"`          // va, manual
+	SourceReference  string             `json:"sourceReference"` // virtual account number or finance approval number
+	Amount           TransferAmountData `json:"amount"`
+	PaidAt           string             `json:"paidAt"`
+	Description      string             `json:"description"`
+}
+
+type DepositResponse struct {
+	ResponseCode     string            `json:"responseCode"`
+	ResponseMessage  string            `json:"responseMessage"`
+	DepositReference string            `json:"depositReference"`
+	MerchantCode     string            `json:"merchantCode"`
+	TransactionDate  string            `json:"transactionDate"`
+	AdditionalInfo   map[string]string `json:"additionalInfo"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+type HealthResponse struct {
+	Status    string                 `json:"status"`
+	CheckedAt string                 `json:"checkedAt"`
+	Uptime    string                 `json:"uptime"`
+	LatencyMs float64                `json:"latencyMs"`
+	Checks    map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+type HealthCheck struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
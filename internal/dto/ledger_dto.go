@@ -0,0 +1,27 @@
+package dto
+
+type TrialBalanceResponse struct {
+	ResponseCode    string                `json:"responseCode"`
+	ResponseMessage string                `json:"responseMessage"`
+	From            string                `json:"from"`
+	To              string                `json:"to"`
+	Accounts        []TrialBalanceAccount `json:"accounts"`
+	TotalDebit      string                `json:"totalDebit"`
+	TotalCredit     string                `json:"totalCredit"`
+	Balanced        bool                  `json:"balanced"`
+	Unbalanced      []UnbalancedJournal   `json:"unbalancedJournals"`
+}
+
+type TrialBalanceAccount struct {
+	AccountCode string `json:"accountCode"`
+	Debit       string `json:"debit"`
+	Credit      string `json:"credit"`
+	Balance     string `json:"balance"`
+}
+
+type UnbalancedJournal struct {
+	JournalId   int64  `json:"journalId"`
+	Reference   string `json:"reference"`
+	JournalType string `json:"journalType"`
+	Difference  string `json:"difference"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+// PartnerTransfer is canonical transfer handed to partner adapter, source account is our account at partner bank
+type PartnerTransfer struct {
+	Request                TransferRequest
+	TransferType           string
+	ReferenceNumber        string
+	ExternalId             string
+	SourceAccountNo        string
+	SourceAccountName      string
+	BeneficiaryAccountName string
+	BeneficiaryBankName    string
+}
+
+// PartnerTransferResult is partner response normalized to our transfer status and response code
+type PartnerTransferResult struct {
+	Outcome                string `json:"outcome"`
+	Status                 string `json:"status"`
+	ResponseCode           string `json:"responseCode"`
+	ResponseMessage        string `json:"responseMessage"`
+	PartnerResponseCode    string `json:"partnerResponseCode"`
+	PartnerResponseMessage string `json:"partnerResponseMessage"`
+	ReferenceNumber        string `json:"referenceNumber"`
+	BankReferenceNo        string `json:"bankReferenceNo"`
+}
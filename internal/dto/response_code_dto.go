@@ -0,0 +1,24 @@
+package dto
+
+type ResponseCodeRequest struct {
+	BankCode     string `json:"bankCode"`
+	PartnerCode  string `json:"partnerCode"`
+	Outcome      string `json:"outcome"`      // SUCCESS, PENDING, FAILED_FINAL, FAILED_RETRYABLE, SUSPECT
+	ResponseCode string `json:"responseCode"` // optional, default response code of outcome when empty
+	Description  string `json:"description"`
+}
+
+type ResponseCodeResponse struct {
+	ResponseCode    string                `json:"responseCode"`
+	ResponseMessage string                `json:"responseMessage"`
+	Mappings        []ResponseCodeMapping `json:"mappings"`
+}
+
+type ResponseCodeMapping struct {
+	BankCode     string `json:"bankCode"`
+	PartnerCode  string `json:"partnerCode"`
+	Outcome      string `json:"outcome"`
+	ResponseCode string `json:"responseCode"`
+	Description  string `json:"description"`
+	UpdatedAt    string `json:"updatedAt"`
+}
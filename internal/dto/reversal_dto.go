@@ -0,0 +1,18 @@
+package dto
+
+type ReversalRequest struct {
+	MerchantCode               string `json:"merchantCode"`
+	OriginalPartnerReferenceNo string `json:"originalPartnerReferenceNo"`
+	ReversalReferenceNo        string `json:"reversalReferenceNo"`
+	Reason                     string `json:"reason"`
+	IncludeFee                 bool   `json:"includeFee"`
+}
+
+type ReversalResponse struct {
+	ResponseCode               string            `json:"responseCode"`
+	ResponseMessage            string            `json:"responseMessage"`
+	OriginalPartnerReferenceNo string            `json:"originalPartnerReferenceNo"`
+	ReversalReferenceNo        string            `json:"reversalReferenceNo"`
+	TransactionDate            string            `json:"transactionDate"`
+	AdditionalInfo             map[string]string `json:"additionalInfo"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+type ScheduleTransferRequest struct {
+	ScheduleReference string          `json:"scheduleReference"`
+	ExecuteAt         string          `json:"executeAt"`
+	Recurrence        string          `json:"recurrence"` // once, daily, weekly, monthly
+	EndAt             string          `json:"endAt"`
+	Transfer          TransferRequest `json:"transfer"`
+}
+
+type ScheduleTransferResponse struct {
+	ResponseCode    string         `json:"responseCode"`
+	ResponseMessage string         `json:"responseMessage"`
+	TransactionDate string         `json:"transactionDate"`
+	Schedules       []ScheduleInfo `json:"schedules"`
+}
+
+type ScheduleInfo struct {
+	ScheduleReference   string `json:"scheduleReference"`
+	PartnerReferenceNo  string `json:"partnerReferenceNo"`
+	Recurrence          string `json:"recurrence"`
+	Status              string `json:"status"`
+	NextExecution       string `json:"nextExecution"`
+	LastExecution       string `json:"lastExecution"`
+	EndAt               string `json:"endAt"`
+	RunCount            int    `json:"runCount"`
+	LastResponseCode    string `json:"lastResponseCode"`
+	LastResponseMessage string `json:"lastResponseMessage"`
+}
+
+type ScheduleNotification struct {
+	Event              string `json:"event"`
+	ScheduleReference  string `json:"scheduleReference"`
+	MerchantCode       string `json:"merchantCode"`
+	PartnerReferenceNo string `json:"partnerReferenceNo"`
+	ResponseCode       string `json:"responseCode"`
+	ResponseMessage    string `json:"responseMessage"`
+	ExecutedAt         string `json:"executedAt"`
+}
@@ -1,13 +1,14 @@
 package dto
 
 type TransferRequest struct {
-	PartnerReferenceNo       string              `json:"partnerReferenceNo"`
-	CustomerNumber           string              `json:"customerNumber"` // phone number
-	AccountType              string              `json:"accountType"`
-	BeneficiaryAccountNumber string              `json:"beneficiaryAccountNumber"`
-	BeneficiaryBankCode      string              `json:"beneficiaryBankCode"`
-	Amount                   TransferAmountData  `json:"amount"`
-	AdditionalInfo           TransferRequestInfo `json:"additionalInfo"`
+	PartnerReferenceNo       string                `json:"partnerReferenceNo"`
+	CustomerNumber           string                `json:"customerNumber"` // phone number
+	AccountType              string                `json:"accountType"`
+	BeneficiaryAccountNumber string                `json:"beneficiaryAccountNumber"`
+	BeneficiaryBankCode      string                `json:"beneficiaryBankCode"`
+	Amount                   TransferAmountData    `json:"amount"`
+	AdditionalInfo           TransferRequestInfo   `json:"additionalInfo"`
+	ClearingInfo             *TransferClearingInfo `json:"clearingInfo,omitempty"` // mandatory for sknbi and rtgs
 }
 
 type TransferAmountData struct {
@@ -28,6 +29,17 @@ type TransferRequestInfo struct {
 	CustomerType      string `json:"customerType"`     // 01 - individu, 02 - corporate, 03 - others
 }
 
+// TransferClearingInfo is beneficiary data required by SKN and RTGS clearing
+type TransferClearingInfo struct {
+	ResidentStatus            string   `json:"residentStatus"`  // 01 - resident, 02 - non resident
+	BeneficiaryType           string   `json:"beneficiaryType"` // 1 - individual, 2 - corporate, 3 - government
+	BeneficiaryBankBranchName string   `json:"beneficiaryBankBranchName"`
+	BeneficiaryBankCity       string   `json:"beneficiaryBankCity"`
+	BeneficiaryBankAddress    string   `json:"beneficiaryBankAddress"` // only mandatory for rtgs
+	BeneficiaryAddress        []string `json:"beneficiaryAddress"`     // up to 3 lines of 35 characters
+	DatiII                    string   `json:"datiII"`                 // 4 digit regency code of beneficiary bank
+}
+
 type TransferResponse struct {
 	ResponseCode       string            `json:"responseCode"`
 	ResponseMessage    string            `json:"responseMessage"`
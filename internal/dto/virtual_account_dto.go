@@ -0,0 +1,58 @@
+package dto
+
+type VAInquiryRequest struct {
+	PartnerReferenceNo  string `json:"partnerReferenceNo"`
+	BeneficiaryBankCode string `json:"beneficiaryBankCode"`
+	VirtualAccountNo    string `json:"virtualAccountNo"`
+}
+
+type VAInquiryResponse struct {
+	ResponseCode       string             `json:"responseCode"`
+	ResponseMessage    string             `json:"responseMessage"`
+	PartnerReferenceNo string             `json:"partnerReferenceNo"`
+	VirtualAccountNo   string             `json:"virtualAccountNo"`
+	VirtualAccountName string             `json:"virtualAccountName"`
+	VirtualAccountType string             `json:"virtualAccountType"` // CLOSED, OPEN
+	BillAmount         TransferAmountData `json:"billAmount"`
+	BillDetails        []BCAVABillDetails `json:"billDetails"`
+	AdditionalInfo     map[string]string  `json:"additionalInfo"`
+}
+
+type VAPaymentRequest struct {
+	PartnerReferenceNo  string             `json:"partnerReferenceNo"`
+	CustomerNumber      string             `json:"customerNumber"`
+	BeneficiaryBankCode string             `json:"beneficiaryBankCode"`
+	VirtualAccountNo    string             `json:"virtualAccountNo"`
+	Amount              TransferAmountData `json:"amount"`
+	AdditionalInfo      VAPaymentInfo      `json:"additionalInfo"`
+}
+
+type VAPaymentInfo struct {
+	TransactionDate   string `json:"transactionDate"`
+	CustomerReference string `json:"customerReference"`
+	Remarks           string `json:"remarks"`
+	Email             string `json:"email"`
+}
+
+// VAInquiryPartnerRequest is SNAP inquiry sent to partner gateway, gateway resolves partnerServiceId of destination bank
+type VAInquiryPartnerRequest struct {
+	VirtualAccountNo    string `json:"virtualAccountNo"`
+	BeneficiaryBankCode string `json:"beneficiaryBankCode"`
+	InquiryRequestId    string `json:"inquiryRequestId"`
+	TrxDateInit         string `json:"trxDateInit"`
+}
+
+type VAInquiryPartnerResponse struct {
+	ResponseCode       string               `json:"responseCode"`
+	ResponseMessage    string               `json:"responseMessage"`
+	VirtualAccountData VAInquiryPartnerData `json:"virtualAccountData"`
+}
+
+type VAInquiryPartnerData struct {
+	VirtualAccountNo      string             `json:"virtualAccountNo"`
+	VirtualAccountName    string             `json:"virtualAccountName"`
+	VirtualAccountTrxType string             `json:"virtualAccountTrxType"` // C - closed, O - open
+	InquiryRequestId      string             `json:"inquiryRequestId"`
+	TotalAmount           TransferAmountData `json:"totalAmount"`
+	BillDetails           []BCAVABillDetails `json:"billDetails"`
+}
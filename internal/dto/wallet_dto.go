@@ -0,0 +1,46 @@
+package dto
+
+type WalletInquiryRequest struct {
+	PartnerReferenceNo string `json:"partnerReferenceNo"`
+	WalletCode         string `json:"walletCode"`
+	CustomerNumber     string `json:"customerNumber"` // phone number registered to wallet
+}
+
+type WalletInquiryResponse struct {
+	ResponseCode       string            `json:"responseCode"`
+	ResponseMessage    string            `json:"responseMessage"`
+	PartnerReferenceNo string            `json:"partnerReferenceNo"`
+	WalletCode         string            `json:"walletCode"`
+	CustomerNumber     string            `json:"customerNumber"`
+	CustomerName       string            `json:"customerName"`
+	AdditionalInfo     map[string]string `json:"additionalInfo"`
+}
+
+type WalletTopupRequest struct {
+	PartnerReferenceNo string             `json:"partnerReferenceNo"`
+	WalletCode         string             `json:"walletCode"`
+	CustomerNumber     string             `json:"customerNumber"`
+	Amount             TransferAmountData `json:"amount"`
+	AdditionalInfo     WalletTopupInfo    `json:"additionalInfo"`
+}
+
+type WalletTopupInfo struct {
+	TransactionDate   string `json:"transactionDate"`
+	CustomerReference string `json:"customerReference"`
+	Remarks           string `json:"remarks"`
+}
+
+type WalletInquiryPartnerRequest struct {
+	PartnerReferenceNo string             `json:"partnerReferenceNo"`
+	CustomerNumber     string             `json:"customerNumber"`
+	Amount             TransferAmountData `json:"amount"`
+	TransactionDate    string             `json:"transactionDate"`
+	AdditionalInfo     map[string]string  `json:"additionalInfo"`
+}
+
+type WalletInquiryPartnerResponse struct {
+	ResponseCode    string `json:"responseCode"`
+	ResponseMessage string `json:"responseMessage"`
+	CustomerNumber  string `json:"customerNumber"`
+	CustomerName    string `json:"customerName"`
+}
@@ -0,0 +1,64 @@
+package dto
+
+type WebhookEndpointRequest struct {
+	CallbackUrl string `json:"callbackUrl"`
+	Secret      string `json:"secret"`
+	IsActive    *bool  `json:"isActive"`
+}
+
+type WebhookResponse struct {
+	ResponseCode    string                `json:"responseCode"`
+	ResponseMessage string                `json:"responseMessage"`
+	TransactionDate string                `json:"transactionDate"`
+	Deliveries      []WebhookDeliveryInfo `json:"deliveries"`
+}
+
+type WebhookDeliveryInfo struct {
+	DeliveryId         int64                `json:"deliveryId"`
+	MerchantCode       string               `json:"merchantCode"`
+	Event              string               `json:"event"`
+	PartnerReferenceNo string               `json:"partnerReferenceNo"`
+	Status             string               `json:"status"`
+	Attempts           int                  `json:"attempts"`
+	NextAttemptAt      string               `json:"nextAttemptAt"`
+	LastHttpStatus     int                  `json:"lastHttpStatus"`
+	LastError          string               `json:"lastError"`
+	DeliveredAt        string               `json:"deliveredAt"`
+	CreatedAt          string               `json:"createdAt"`
+	Logs               []WebhookAttemptInfo `json:"logs,omitempty"`
+}
+
+type WebhookAttemptInfo struct {
+	Attempt      int    `json:"attempt"`
+	CallbackUrl  string `json:"callbackUrl"`
+	HttpStatus   int    `json:"httpStatus"`
+	ResponseBody string `json:"responseBody"`
+	Error        string `json:"error"`
+	DurationMs   int64  `json:"durationMs"`
+	Actor        string `json:"actor"`
+	AttemptedAt  string `json:"attemptedAt"`
+}
+
+// WebhookPayload is the body posted to merchant callback url
+type WebhookPayload struct {
+	Event              string `json:"event"`
+	MerchantCode       string `json:"merchantCode"`
+	PartnerReferenceNo string `json:"partnerReferenceNo"`
+	ReferenceNumber    string `json:"referenceNumber"`
+	BankReferenceNo    string `json:"bankReferenceNo"`
+	PreviousStatus     string `json:"previousStatus"`
+	Status             string `json:"status"`
+	Amount             string `json:"amount"`
+	Reason             string `json:"reason"`
+	OccurredAt         string `json:"occurredAt"`
+}
+
+// TransferStatusEvent is the final transfer status reported by partner bank services.
+// ReferenceNumber is our reference sent in transfer instruction, partner reference alone is only unique per merchant
+type TransferStatusEvent struct {
+	PartnerReferenceNo string `json:"partnerReferenceNo"`
+	ReferenceNumber    string `json:"referenceNumber"`
+	Status             string `json:"status"` // DONE, REJECTED, TIMEOUT, REFUNDED
+	BankReferenceNo    string `json:"bankReferenceNo"`
+	Reason             string `json:"reason"`
+}
@@ -5,4 +5,5 @@ type BankConfig struct {
 	BankName        string `gorm:"column:bank_name"`
 	KafkaTopic      string `gorm:"column:kafka_topic"`
 	KafkaTopicGroup string `gorm:"column:kafka_topic_group"`
+	InquiryUrl      string `gorm:"column:inquiry_url"`
 }
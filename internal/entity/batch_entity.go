@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+type TransferBatch struct {
+	ID             int64               `gorm:"column:id;primaryKey"`
+	BatchReference string              `gorm:"column:batch_reference"`
+	MerchantCode   string              `gorm:"column:merchant_code"`
+	TotalRows      int                 `gorm:"column:total_rows"`
+	ReservedAmount float64             `gorm:"column:reserved_amount"`
+	SuccessCount   int                 `gorm:"column:success_count"`
+	FailedCount    int                 `gorm:"column:failed_count"`
+	Status         string              `gorm:"column:status"`
+	CreatedAt      time.Time           `gorm:"column:created_at"`
+	CompletedAt    *time.Time          `gorm:"column:completed_at"`
+	Items          []TransferBatchItem `gorm:"foreignKey:BatchId"`
+}
+
+type TransferBatchItem struct {
+	ID                 int64     `gorm:"column:id;primaryKey"`
+	BatchId            int64     `gorm:"column:batch_id"`
+	RowNumber          int       `gorm:"column:row_number"`
+	PartnerReferenceNo string    `gorm:"column:partner_reference_no"`
+	Payload            string    `gorm:"column:payload"`
+	ReservedAmount     float64   `gorm:"column:reserved_amount"`
+	Status             string    `gorm:"column:status"`
+	ResponseCode       string    `gorm:"column:response_code"`
+	ResponseMessage    string    `gorm:"column:response_message"`
+	ReferenceNumber    string    `gorm:"column:reference_number"`
+	LastUpdated        time.Time `gorm:"column:last_updated"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+type Holiday struct {
+	ID          int64     `gorm:"column:id;primaryKey"`
+	HolidayDate time.Time `gorm:"column:holiday_date"`
+	Description string    `gorm:"column:description"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
+
+// ChannelCutoff is the daily operating window of a channel in WIB, open and cutoff time are in HH:MM
+type ChannelCutoff struct {
+	ID               int64  `gorm:"column:id;primaryKey"`
+	Channel          string `gorm:"column:channel"`
+	OpenTime         string `gorm:"column:open_time"`
+	CutoffTime       string `gorm:"column:cutoff_time"`
+	BusinessDaysOnly bool   `gorm:"column:business_days_only"`
+}
+
+type MerchantTransferPreference struct {
+	MerchantCode      string    `gorm:"column:merchant_code;primaryKey"`
+	OutOfWindowAction string    `gorm:"column:out_of_window_action"`
+	LastUpdated       time.Time `gorm:"column:last_updated"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type Deposit struct {
+	ID               int64     `gorm:"column:id;primaryKey"`
+	DepositReference string    `gorm:"column:deposit_reference;uniqueIndex"`
+	MerchantCode     string    `gorm:"column:merchant_code"`
+	Source           string    `gorm:"column:source"`
+	SourceReference  string    `gorm:"column:source_reference"`
+	Amount           float64   `gorm:"column:amount"`
+	Currency         string    `gorm:"column:currency"`
+	Description      string    `gorm:"column:description"`
+	Status           string    `gorm:"column:status"`
+	PaidAt           time.Time `gorm:"column:paid_at"`
+	CreatedAt        time.Time `gorm:"column:created_at"`
+}
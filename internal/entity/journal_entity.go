@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+type Journal struct {
+	ID            int64         `gorm:"column:id;primaryKey"`
+	TransactionId int64         `gorm:"column:transaction_id"`
+	Reference     string        `gorm:"column:reference"`
+	JournalType   string        `gorm:"column:journal_type"`
+	Description   string        `gorm:"column:description"`
+	CreatedAt     time.Time     `gorm:"column:created_at"`
+	Lines         []JournalLine `gorm:"foreignKey:JournalId"`
+}
+
+type JournalLine struct {
+	ID           int64     `gorm:"column:id;primaryKey"`
+	JournalId    int64     `gorm:"column:journal_id"`
+	AccountCode  string    `gorm:"column:account_code"`
+	MerchantCode string    `gorm:"column:merchant_code"`
+	Debit        float64   `gorm:"column:debit"`
+	Credit       float64   `gorm:"column:credit"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
+
+type TrialBalance struct {
+	AccountCode string  `gorm:"column:account_code"`
+	TotalDebit  float64 `gorm:"column:total_debit"`
+	TotalCredit float64 `gorm:"column:total_credit"`
+}
+
+type UnbalancedJournal struct {
+	JournalId   int64   `gorm:"column:journal_id"`
+	Reference   string  `gorm:"column:reference"`
+	JournalType string  `gorm:"column:journal_type"`
+	Difference  float64 `gorm:"column:difference"`
+}
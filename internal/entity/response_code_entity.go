@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// PartnerResponseCode map response code of partner bank to canonical outcome, response code overrides
+// the default response code of outcome when set
+type PartnerResponseCode struct {
+	ID           int64     `gorm:"column:id;primaryKey"`
+	BankCode     string    `gorm:"column:bank_code"`
+	PartnerCode  string    `gorm:"column:partner_code"`
+	Outcome      string    `gorm:"column:outcome"`
+	ResponseCode string    `gorm:"column:response_code"`
+	Description  string    `gorm:"column:description"`
+	UpdatedAt    time.Time `gorm:"column:updated_at"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+type ScheduledTransfer struct {
+	ID                  int64      `gorm:"column:id;primaryKey"`
+	ScheduleReference   string     `gorm:"column:schedule_reference"`
+	MerchantCode        string     `gorm:"column:merchant_code"`
+	PartnerReferenceNo  string     `gorm:"column:partner_reference_no"`
+	Payload             string     `gorm:"column:payload"`
+	Recurrence          string     `gorm:"column:recurrence"`
+	StartAt             time.Time  `gorm:"column:start_at"`
+	NextRunAt           time.Time  `gorm:"column:next_run_at"`
+	EndAt               *time.Time `gorm:"column:end_at"`
+	LastRunAt           *time.Time `gorm:"column:last_run_at"`
+	RunCount            int        `gorm:"column:run_count"`
+	Status              string     `gorm:"column:status"`
+	LastResponseCode    string     `gorm:"column:last_response_code"`
+	LastResponseMessage string     `gorm:"column:last_response_message"`
+	CreatedAt           time.Time  `gorm:"column:created_at"`
+	LastUpdated         time.Time  `gorm:"column:last_updated"`
+}
+
+type ScheduledTransferRun struct {
+	ID                 int64      `gorm:"column:id;primaryKey"`
+	ScheduleId         int64      `gorm:"column:schedule_id"`
+	RunNumber          int        `gorm:"column:run_number"`
+	PartnerReferenceNo string     `gorm:"column:partner_reference_no"`
+	Status             string     `gorm:"column:status"`
+	ResponseCode       string     `gorm:"column:response_code"`
+	ResponseMessage    string     `gorm:"column:response_message"`
+	ReferenceNumber    string     `gorm:"column:reference_number"`
+	ScheduledAt        time.Time  `gorm:"column:scheduled_at"`
+	ClaimedAt          time.Time  `gorm:"column:claimed_at"`
+	ExecutedAt         *time.Time `gorm:"column:executed_at"`
+}
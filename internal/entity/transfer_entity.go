@@ -19,10 +19,10 @@ type Transaction struct {
 	ID                      int64      `gorm:"column:id;primaryKey"`
 	Sender                  int64      `gorm:"column:data_sender_id"`
 	Recipient               int64      `gorm:"column:data_recipient_id"`
-	MerchantCode            string     `gorm:"column:merchant_code"`
-	PartnerReferenceNo      string     `gorm:"column:partner_reference_no"`
+	MerchantCode            string     `gorm:"column:merchant_code;uniqueIndex:idx_transaction_merchant_reference"`
+	PartnerReferenceNo      string     `gorm:"column:partner_reference_no;uniqueIndex:idx_transaction_merchant_reference"`
 	BankReferenceNo         *string    `gorm:"column:bank_reference_no"`
-	SystemReferenceNo       *string    `gorm:"column:system_reference_no"`
+	SystemReferenceNo       *string    `gorm:"column:system_reference_no;uniqueIndex"`
 	Amount                  float64    `gorm:"column:amount"`
 	Currency                string     `gorm:"column:currency"`
 	Remark                  string     `gorm:"column:remark"`
@@ -30,12 +30,41 @@ type Transaction struct {
 	TransactionDate         time.Time  `gorm:"column:transaction_date"`
 	Status                  string     `gorm:"column:status"`
 	IsReversal              bool       `gorm:"column:is_reversal"`
+	OriginalTransactionId   *int64     `gorm:"column:original_transaction_id"`
 	CompanyCharge           float32    `gorm:"column:company_charge"`
 	PartnerCharge           float32    `gorm:"column:partner_charge"`
 	AdditionalPartnerCharge float32    `gorm:"column:additional_partner_charge"`
 	TaxCharge               float32    `gorm:"column:tax_charge"`
 	IsReconcile             bool       `gorm:"column:is_reconcile"`
 	ReconcileDate           *time.Time `gorm:"column:reconcile_date"`
+	Version                 int64      `gorm:"column:version"`
+}
+
+// TransferClearingDetail is SKN and RTGS clearing data of transfer as submitted by merchant, kept for audit
+type TransferClearingDetail struct {
+	ID                        int64     `gorm:"column:id;primaryKey"`
+	TransactionId             int64     `gorm:"column:transaction_id"`
+	Channel                   string    `gorm:"column:channel"`
+	ResidentStatus            string    `gorm:"column:resident_status"`
+	BeneficiaryType           string    `gorm:"column:beneficiary_type"`
+	BeneficiaryBankBranchName string    `gorm:"column:beneficiary_bank_branch_name"`
+	BeneficiaryBankCity       string    `gorm:"column:beneficiary_bank_city"`
+	BeneficiaryBankAddress    string    `gorm:"column:beneficiary_bank_address"`
+	BeneficiaryAddress1       string    `gorm:"column:beneficiary_address_1"`
+	BeneficiaryAddress2       string    `gorm:"column:beneficiary_address_2"`
+	BeneficiaryAddress3       string    `gorm:"column:beneficiary_address_3"`
+	DatiII                    string    `gorm:"column:dati_ii"`
+	CreatedAt                 time.Time `gorm:"column:created_at"`
+}
+
+type TransferStatusHistory struct {
+	ID            int64     `gorm:"column:id;primaryKey"`
+	TransactionId int64     `gorm:"column:transaction_id"`
+	FromStatus    string    `gorm:"column:from_status"`
+	ToStatus      string    `gorm:"column:to_status"`
+	Reason        string    `gorm:"column:reason"`
+	Actor         string    `gorm:"column:actor"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
 }
 
 type DataSender struct {
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// WalletProvider is e-wallet disbursement partner selected by wallet code, amount limit applies per transaction
+type WalletProvider struct {
+	ID           int64     `gorm:"column:id;primaryKey"`
+	WalletCode   string    `gorm:"column:wallet_code"`
+	ProviderName string    `gorm:"column:provider_name"`
+	KafkaTopic   string    `gorm:"column:kafka_topic"`
+	InquiryUrl   string    `gorm:"column:inquiry_url"`
+	MinAmount    float64   `gorm:"column:min_amount"`
+	MaxAmount    float64   `gorm:"column:max_amount"`
+	IsActive     bool      `gorm:"column:is_active"`
+	LastUpdated  time.Time `gorm:"column:last_updated"`
+}
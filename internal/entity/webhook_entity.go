@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+type MerchantWebhook struct {
+	ID           int64     `gorm:"column:id;primaryKey"`
+	MerchantCode string    `gorm:"column:merchant_code"`
+	CallbackUrl  string    `gorm:"column:callback_url"`
+	Secret       string    `gorm:"column:secret" json:"-"`
+	IsActive     bool      `gorm:"column:is_active"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+	LastUpdated  time.Time `gorm:"column:last_updated"`
+}
+
+type WebhookDelivery struct {
+	ID                 int64      `gorm:"column:id;primaryKey"`
+	MerchantCode       string     `gorm:"column:merchant_code"`
+	EventType          string     `gorm:"column:event_type"`
+	PartnerReferenceNo string     `gorm:"column:partner_reference_no"`
+	Payload            string     `gorm:"column:payload"`
+	Status             string     `gorm:"column:status"`
+	Attempts           int        `gorm:"column:attempts"`
+	NextAttemptAt      time.Time  `gorm:"column:next_attempt_at"`
+	LastHttpStatus     int        `gorm:"column:last_http_status"`
+	LastError          string     `gorm:"column:last_error"`
+	DeliveredAt        *time.Time `gorm:"column:delivered_at"`
+	DeadLetteredAt     *time.Time `gorm:"column:dead_lettered_at"`
+	CreatedAt          time.Time  `gorm:"column:created_at"`
+	LastUpdated        time.Time  `gorm:"column:last_updated"`
+}
+
+type WebhookDeliveryLog struct {
+	ID           int64     `gorm:"column:id;primaryKey"`
+	DeliveryId   int64     `gorm:"column:delivery_id"`
+	Attempt      int       `gorm:"column:attempt"`
+	CallbackUrl  string    `gorm:"column:callback_url"`
+	HttpStatus   int       `gorm:"column:http_status"`
+	ResponseBody string    `gorm:"column:response_body"`
+	Error        string    `gorm:"column:error"`
+	DurationMs   int64     `gorm:"column:duration_ms"`
+	Actor        string    `gorm:"column:actor"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
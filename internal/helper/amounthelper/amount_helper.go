@@ -0,0 +1,13 @@
+package amounthelper
+
+import "math"
+
+// ToCent convert rupiah amount to whole cent, compare and sum amount in cent so float error never reach ledger
+func ToCent(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// RoundCent round rupiah amount to nearest cent
+func RoundCent(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
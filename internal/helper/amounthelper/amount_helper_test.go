@@ -0,0 +1,41 @@
+package amounthelper
+
+import "testing"
+
+func TestToCent(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		expected int64
+	}{
+		{150000, 15000000},
+		{0.1 + 0.2, 30},
+		{1666.665, 166667},
+		{100000.10, 10000010},
+		{0.004, 0},
+		{-12.345, -1235},
+	}
+
+	for _, tt := range tests {
+		if got := ToCent(tt.amount); got != tt.expected {
+			t.Errorf("ToCent(%v) = %d, want %d", tt.amount, got, tt.expected)
+		}
+	}
+}
+
+func TestRoundCent(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		expected float64
+	}{
+		{150000, 150000},
+		{0.1 + 0.2, 0.3},
+		{1666.665, 1666.67},
+		{3333.333333, 3333.33},
+	}
+
+	for _, tt := range tests {
+		if got := RoundCent(tt.amount); got != tt.expected {
+			t.Errorf("RoundCent(%v) = %v, want %v", tt.amount, got, tt.expected)
+		}
+	}
+}
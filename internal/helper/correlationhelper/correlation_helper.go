@@ -0,0 +1,32 @@
+package correlationhelper
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HeaderExternalId    = "X-EXTERNAL-ID"
+	HeaderCorrelationId = "X-CORRELATION-ID"
+)
+
+type correlationKey struct{}
+
+func New() string {
+	return uuid.NewString()
+}
+
+// NewContext carry correlation id of request or message down to service and outgoing kafka message
+func NewContext(ctx context.Context, correlationId string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlationId)
+}
+
+// FromContext return correlation id carried by context, empty when there is none
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	correlationId, _ := ctx.Value(correlationKey{}).(string)
+	return correlationId
+}
@@ -8,6 +8,7 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
 type DBHelper struct {
@@ -15,16 +16,19 @@ type DBHelper struct {
 }
 
 type DBConfig struct {
-	Hostname string
-	Port     string
-	DBname   string
-	Username string
-	Password string
-	SslMode  string
+	Hostname        string
+	Port            int
+	DBname          string
+	Username        string
+	Password        string
+	SslMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
 }
 
 func NewDBConfig(cfg DBConfig) (*DBHelper, error) {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Hostname, cfg.Port, cfg.Username, cfg.Password, cfg.DBname, cfg.SslMode,
 	)
 
@@ -39,6 +43,11 @@ func NewDBConfig(cfg DBConfig) (*DBHelper, error) {
 		return nil, fmt.Errorf("failed to establish database connection %w", err)
 	}
 
+	// trace every query as child span of the request
+	if err := db.Use(tracing.NewPlugin(tracing.WithoutMetrics())); err != nil {
+		return nil, fmt.Errorf("failed to register database tracing %w", err)
+	}
+
 	sqlDb, err := db.DB()
 
 	if err != nil {
@@ -53,9 +62,9 @@ func NewDBConfig(cfg DBConfig) (*DBHelper, error) {
 		return nil, fmt.Errorf("failed to ping database %w", err)
 	}
 
-	sqlDb.SetMaxIdleConns(10)
-	sqlDb.SetMaxOpenConns(100)
-	sqlDb.SetConnMaxLifetime(time.Hour)
+	sqlDb.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDb.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDb.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	return &DBHelper{DB: db}, err
 }
@@ -0,0 +1,132 @@
+package kafkahelper
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+const (
+	SaslMechanismNone        = "none"
+	SaslMechanismPlain       = "plain"
+	SaslMechanismScramSha256 = "scram-sha-256"
+	SaslMechanismScramSha512 = "scram-sha-512"
+)
+
+type KafkaConfig struct {
+	Brokers         []string
+	ClientId        string
+	RetryMax        int
+	RetryBackoff    time.Duration
+	ProducerTimeout time.Duration
+	NetTimeout      time.Duration
+	ProducerMode    string
+	AsyncBufferSize int
+	DeadLetterTopic string
+
+	// ConsumerMaxAttempts is how many times handler runs a message before it is dead lettered
+	ConsumerMaxAttempts  int
+	ConsumerRetryBackoff time.Duration
+
+	SaslMechanism string
+	SaslUsername  string
+	SaslPassword  string
+	TlsEnabled    bool
+	TlsCaFile     string
+	TlsCertFile   string
+	TlsKeyFile    string
+	TlsServerName string
+}
+
+var (
+	ErrBufferFull     = errors.New("kafka producer buffer is full")
+	ErrProducerClosed = errors.New("kafka producer is closed")
+)
+
+// newSaramaConfig build client setting shared by producer and consumer
+func newSaramaConfig(cfg KafkaConfig) (*sarama.Config, error) {
+	saramaCfg := sarama.NewConfig()
+	if cfg.ClientId != "" {
+		saramaCfg.ClientID = cfg.ClientId
+	}
+
+	// network config
+	saramaCfg.Net.DialTimeout = cfg.NetTimeout
+	saramaCfg.Net.ReadTimeout = cfg.NetTimeout
+	saramaCfg.Net.WriteTimeout = cfg.NetTimeout
+
+	if err := applySasl(saramaCfg, cfg); err != nil {
+		return nil, err
+	}
+	if cfg.TlsEnabled {
+		tlsConfig, err := newTlsConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		saramaCfg.Net.TLS.Enable = true
+		saramaCfg.Net.TLS.Config = tlsConfig
+	}
+
+	saramaCfg.Version = sarama.V3_0_2_0
+	return saramaCfg, nil
+}
+
+func applySasl(saramaCfg *sarama.Config, cfg KafkaConfig) error {
+	switch cfg.SaslMechanism {
+	case "", SaslMechanismNone:
+		return nil
+	case SaslMechanismPlain:
+		saramaCfg.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	case SaslMechanismScramSha256:
+		saramaCfg.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+		saramaCfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: sha256Generator} }
+	case SaslMechanismScramSha512:
+		saramaCfg.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+		saramaCfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: sha512Generator} }
+	default:
+		return fmt.Errorf("unsupported kafka sasl mechanism %q", cfg.SaslMechanism)
+	}
+
+	saramaCfg.Net.SASL.Enable = true
+	saramaCfg.Net.SASL.Handshake = true
+	saramaCfg.Net.SASL.User = cfg.SaslUsername
+	saramaCfg.Net.SASL.Password = cfg.SaslPassword
+	return nil
+}
+
+// newTlsConfig trust system roots plus custom CA when given, client certificate is presented for mutual TLS
+func newTlsConfig(cfg KafkaConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.TlsServerName,
+	}
+
+	if cfg.TlsCaFile != "" {
+		caCert, err := os.ReadFile(cfg.TlsCaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kafka CA file %s, with error: %w", cfg.TlsCaFile, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("kafka CA file %s has no PEM certificate", cfg.TlsCaFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.TlsCertFile != "" || cfg.TlsKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TlsCertFile, cfg.TlsKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kafka client certificate, with error: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,172 @@
+package kafkahelper
+
+import (
+	"briefcash-transfer/internal/helper/correlationhelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/tracehelper"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// MessageHandler returns error for retryable failure, the message is handled again until attempts run out and then dead lettered.
+// Error wrapping ErrPoisonMessage is dead lettered right away
+type MessageHandler func(ctx context.Context, message *sarama.ConsumerMessage) error
+
+type KafkaConsumer struct {
+	Group      sarama.ConsumerGroup
+	Brokers    []string
+	GroupId    string
+	deadLetter DeadLetterPublisher
+
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+// NewKafkaConsumer create consumer group member, poison message and message failing every attempt are written to deadLetter when it is not nil
+func NewKafkaConsumer(kafkaCfg KafkaConfig, groupId string, deadLetter DeadLetterPublisher) (*KafkaConsumer, error) {
+	cfg, err := newSaramaConfig(kafkaCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// consumer config
+	cfg.Consumer.Return.Errors = true
+	cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	cfg.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRoundRobin()}
+
+	group, err := sarama.NewConsumerGroup(kafkaCfg.Brokers, groupId, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KafkaConsumer{
+		Group:      group,
+		Brokers:    kafkaCfg.Brokers,
+		GroupId:    groupId,
+		deadLetter: deadLetter,
+
+		maxAttempts:  max(kafkaCfg.ConsumerMaxAttempts, 1),
+		retryBackoff: kafkaCfg.ConsumerRetryBackoff,
+	}, nil
+}
+
+// Consume blocks until context is cancelled or consumer group is closed
+func (kc *KafkaConsumer) Consume(ctx context.Context, topics []string, handler MessageHandler) error {
+	go func() {
+		for err := range kc.Group.Errors() {
+			loghelper.Logger.WithError(err).WithField("group", kc.GroupId).Error("Kafka consumer error")
+		}
+	}()
+
+	groupHandler := &consumerGroupHandler{handler: handler, deadLetter: kc.deadLetter, maxAttempts: kc.maxAttempts, retryBackoff: kc.retryBackoff}
+	for {
+		if err := kc.Group.Consume(ctx, topics, groupHandler); err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return nil
+			}
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (kc *KafkaConsumer) Close() error {
+	if kc.Group == nil {
+		return nil
+	}
+	return kc.Group.Close()
+}
+
+type consumerGroupHandler struct {
+	handler      MessageHandler
+	deadLetter   DeadLetterPublisher
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			// message in hand is handled to the end when session stops, a half applied deposit or reversal is worse than a late one
+			ctx, correlationId := ExtractCorrelationId(context.WithoutCancel(session.Context()), message)
+			log := loghelper.Logger.WithField("correlation_id", correlationId)
+			if externalId := HeaderValue(message, correlationhelper.HeaderExternalId); externalId != "" {
+				log = log.WithField("trace_id", externalId)
+			}
+			ctx = loghelper.NewContext(ExtractTraceContext(ctx, message), log)
+			if err := h.handle(ctx, session.Context(), message); err != nil {
+				// leave offset uncommitted so the message is consumed again after rebalance
+				time.Sleep(h.retryBackoff)
+				return err
+			}
+
+			session.MarkMessage(message, "")
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+// handle run handler until it succeeds or attempts run out, failing message is then dead lettered so it cannot block partition.
+// Error is returned when message must be redelivered, that is session stops between attempts or dead letter is not written
+func (h *consumerGroupHandler) handle(ctx, sessionCtx context.Context, message *sarama.ConsumerMessage) error {
+	log := loghelper.FromContext(ctx)
+	for attempt := 1; ; attempt++ {
+		spanCtx, span := tracehelper.StartSpan(ctx, "kafka.consume",
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", message.Topic),
+			attribute.Int64("messaging.kafka.offset", message.Offset),
+			attribute.Int("messaging.kafka.attempt", attempt),
+		)
+		err := h.handler(spanCtx, message)
+		tracehelper.EndSpan(span, err)
+		if err == nil {
+			return nil
+		}
+
+		if errors.Is(err, ErrPoisonMessage) {
+			deadLetterConsumed(spanCtx, h.deadLetter, message, err)
+			return nil
+		}
+
+		if attempt >= h.maxAttempts {
+			if h.deadLetter == nil {
+				log.WithError(err).Errorf("Message failed after %d attempts, redelivering as there is no dead letter topic", attempt)
+				return err
+			}
+			if dlErr := deadLetterConsumed(spanCtx, h.deadLetter, message, fmt.Errorf("failed after %d attempts: %w", attempt, err)); dlErr != nil {
+				return err
+			}
+			return nil
+		}
+
+		wait := h.retryBackoff << (attempt - 1)
+		log.WithError(err).Warnf("Message failed on attempt %d of %d, retrying in %s", attempt, h.maxAttempts, wait)
+		select {
+		case <-time.After(wait):
+		case <-sessionCtx.Done():
+			return err
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package kafkahelper
+
+import (
+	"briefcash-transfer/internal/helper/loghelper"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/sirupsen/logrus"
+)
+
+// stubDeadLetterPublisher keep dead letters in memory, err fails every write
+type stubDeadLetterPublisher struct {
+	letters []DeadLetter
+	err     error
+}
+
+func (s *stubDeadLetterPublisher) PublishDeadLetter(ctx context.Context, letter DeadLetter) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+func testConsumerContext() context.Context {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return loghelper.NewContext(context.Background(), logrus.NewEntry(logger))
+}
+
+func testConsumerMessage() *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{Topic: "deposit", Partition: 1, Offset: 42, Key: []byte("MRC001:DEP-0001"), Value: []byte("deposit")}
+}
+
+// failingHandler fail first failures calls and count every call
+func failingHandler(failures int, cause error) (MessageHandler, *int) {
+	calls := 0
+	return func(ctx context.Context, message *sarama.ConsumerMessage) error {
+		calls++
+		if calls <= failures {
+			return cause
+		}
+		return nil
+	}, &calls
+}
+
+func TestConsumerHandle(t *testing.T) {
+	errDatabase := errors.New("database unavailable")
+	tests := []struct {
+		name        string
+		failures    int
+		cause       error
+		deadLetter  *stubDeadLetterPublisher
+		calls       int
+		redeliver   bool
+		deadLetters int
+	}{
+		{"success after retry", 2, errDatabase, &stubDeadLetterPublisher{}, 3, false, 0},
+		{"dead letter after max attempts", 5, errDatabase, &stubDeadLetterPublisher{}, 3, false, 1},
+		{"poison message is not retried", 1, fmt.Errorf("invalid payload: %w", ErrPoisonMessage), &stubDeadLetterPublisher{}, 1, false, 1},
+		{"redeliver when dead letter fails", 5, errDatabase, &stubDeadLetterPublisher{err: ErrProducerClosed}, 3, true, 0},
+		{"redeliver without dead letter topic", 5, errDatabase, nil, 3, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, calls := failingHandler(tt.failures, tt.cause)
+			groupHandler := &consumerGroupHandler{handler: handler, maxAttempts: 3}
+			if tt.deadLetter != nil {
+				groupHandler.deadLetter = tt.deadLetter
+			}
+
+			err := groupHandler.handle(testConsumerContext(), context.Background(), testConsumerMessage())
+			if (err != nil) != tt.redeliver {
+				t.Errorf("handle() error = %v, want redeliver %t", err, tt.redeliver)
+			}
+			if *calls != tt.calls {
+				t.Errorf("handler called %d times, want %d", *calls, tt.calls)
+			}
+			if tt.deadLetter != nil && len(tt.deadLetter.letters) != tt.deadLetters {
+				t.Fatalf("wrote %d dead letter, want %d", len(tt.deadLetter.letters), tt.deadLetters)
+			}
+			if tt.deadLetters > 0 {
+				letter := tt.deadLetter.letters[0]
+				if letter.Source != DeadLetterSourceConsume || letter.Topic != "deposit" || letter.Partition != 1 || letter.Offset != 42 || letter.Reason == "" {
+					t.Errorf("dead letter = %+v, want consumed message with reason", letter)
+				}
+			}
+		})
+	}
+}
+
+func TestConsumerHandleStopsRetryWhenSessionEnds(t *testing.T) {
+	handler, calls := failingHandler(5, errors.New("database unavailable"))
+	deadLetter := &stubDeadLetterPublisher{}
+	groupHandler := &consumerGroupHandler{handler: handler, deadLetter: deadLetter, maxAttempts: 3, retryBackoff: time.Hour}
+
+	sessionCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := groupHandler.handle(testConsumerContext(), sessionCtx, testConsumerMessage()); err == nil {
+		t.Fatal("handle() error = nil, want message left for redelivery")
+	}
+	if *calls != 1 || len(deadLetter.letters) != 0 {
+		t.Errorf("handler called %d times and wrote %d dead letter, want one call and none", *calls, len(deadLetter.letters))
+	}
+}
@@ -0,0 +1,321 @@
+package kafkahelper
+
+import (
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/metrichelper"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+const (
+	HeaderDeadLetterReason    = "X-DLQ-REASON"
+	HeaderDeadLetterSource    = "X-DLQ-SOURCE"
+	HeaderDeadLetterTopic     = "X-DLQ-ORIGINAL-TOPIC"
+	HeaderDeadLetterPartition = "X-DLQ-ORIGINAL-PARTITION"
+	HeaderDeadLetterOffset    = "X-DLQ-ORIGINAL-OFFSET"
+	HeaderDeadLetterFailedAt  = "X-DLQ-FAILED-AT"
+	HeaderDeadLetterReplayOf  = "X-DLQ-REPLAY-OF"
+
+	// DeadLetterSourceProduce is message broker did not acknowledge, DeadLetterSourceConsume is message consumer could not process
+	DeadLetterSourceProduce = "produce"
+	DeadLetterSourceConsume = "consume"
+
+	deadLetterHeaderPrefix = "X-DLQ-"
+
+	// deadLetterReadTimeout end reading partition whose remaining messages are no longer available
+	deadLetterReadTimeout = 5 * time.Second
+)
+
+// ErrPoisonMessage is returned by MessageHandler for message that will never be processed, it is dead lettered instead of redelivered
+var ErrPoisonMessage = errors.New("poison message")
+
+type DeadLetterPublisher interface {
+	PublishDeadLetter(ctx context.Context, letter DeadLetter) error
+}
+
+// DeadLetter is message that could not be delivered or processed, kept with its error for investigation and replay
+type DeadLetter struct {
+	Source    string
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       string
+	Value     []byte
+	Headers   map[string]string
+	Reason    string
+	FailedAt  time.Time
+
+	// position of dead letter in dead letter topic, known once it is read back
+	DeadLetterPartition int32
+	DeadLetterOffset    int64
+}
+
+// PublishDeadLetter write dead letter along with its original headers, it is no-op when dead letter topic is not configured
+func (kp *KafkaProducer) PublishDeadLetter(ctx context.Context, letter DeadLetter) error {
+	if kp.deadLetterTopic == "" {
+		return nil
+	}
+
+	headers := recordHeaders(letter.Headers)
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterSource), Value: []byte(letter.Source)},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterReason), Value: []byte(letter.Reason)},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterTopic), Value: []byte(letter.Topic)},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterPartition), Value: []byte(strconv.FormatInt(int64(letter.Partition), 10))},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterOffset), Value: []byte(strconv.FormatInt(letter.Offset, 10))},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterFailedAt), Value: []byte(letter.FailedAt.Format(time.RFC3339Nano))},
+	)
+
+	// dead letter does not go through async buffer, it is written while buffer is full or producer is closing
+	producer := kp.Producer
+	if kp.Async != nil {
+		producer = kp.deadLetterProducer
+	}
+
+	msg, report := kp.newRawMessage(ctx, kp.deadLetterTopic, sarama.StringEncoder(letter.Key), letter.Value, headers)
+	_, _, err := producer.SendMessage(msg)
+	report.complete(err)
+	if err != nil {
+		return fmt.Errorf("failed to write dead letter of topic %s, with error: %w", letter.Topic, err)
+	}
+	metrichelper.DeadLetterTotal.WithLabelValues(letter.Source, letter.Topic).Inc()
+	return nil
+}
+
+// Replay publish dead letter back to its original topic, replayed message points to dead letter it came from
+func (kp *KafkaProducer) Replay(ctx context.Context, letter DeadLetter) error {
+	headers := recordHeaders(letter.Headers)
+	headers = append(headers, sarama.RecordHeader{
+		Key:   []byte(HeaderDeadLetterReplayOf),
+		Value: []byte(fmt.Sprintf("%d/%d", letter.DeadLetterPartition, letter.DeadLetterOffset)),
+	})
+
+	msg, report := kp.newRawMessage(ctx, letter.Topic, sarama.StringEncoder(letter.Key), letter.Value, headers)
+	return kp.sendAndWait(ctx, msg, report)
+}
+
+// deadLetterProduced keep message broker failed to acknowledge, failure to do so is only logged as caller compensates anyway
+func (kp *KafkaProducer) deadLetterProduced(ctx context.Context, msg *sarama.ProducerMessage, cause error) {
+	letter := DeadLetter{
+		Source:    DeadLetterSourceProduce,
+		Topic:     msg.Topic,
+		Partition: -1,
+		Offset:    -1,
+		Headers:   map[string]string{},
+		Reason:    cause.Error(),
+		FailedAt:  time.Now(),
+	}
+	if msg.Key != nil {
+		key, _ := msg.Key.Encode()
+		letter.Key = string(key)
+	}
+	if msg.Value != nil {
+		letter.Value, _ = msg.Value.Encode()
+	}
+	for _, header := range msg.Headers {
+		letter.Headers[string(header.Key)] = string(header.Value)
+	}
+
+	if err := kp.PublishDeadLetter(ctx, letter); err != nil {
+		loghelper.FromContext(ctx).WithError(err).Errorf("Undelivered message of topic %s is lost", msg.Topic)
+	}
+}
+
+// deadLetterConsumed keep message consumer gave up on, poison message offset is committed even when it returns error
+func deadLetterConsumed(ctx context.Context, publisher DeadLetterPublisher, message *sarama.ConsumerMessage, cause error) error {
+	log := loghelper.FromContext(ctx).WithError(cause)
+	if publisher == nil {
+		log.Error("Poison message skipped, no dead letter topic")
+		return nil
+	}
+
+	letter := DeadLetter{
+		Source:    DeadLetterSourceConsume,
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		Key:       string(message.Key),
+		Value:     message.Value,
+		Headers:   map[string]string{},
+		Reason:    cause.Error(),
+		FailedAt:  time.Now(),
+	}
+	for _, header := range message.Headers {
+		if header != nil {
+			letter.Headers[string(header.Key)] = string(header.Value)
+		}
+	}
+
+	if err := publisher.PublishDeadLetter(ctx, letter); err != nil {
+		log.WithField("dead_letter_error", err.Error()).Error("Failed to write consumed message to dead letter topic")
+		return err
+	}
+	log.Warn("Consumed message written to dead letter topic")
+	return nil
+}
+
+// ReadDeadLetters return up to limit oldest dead letters of every partition of topic
+func ReadDeadLetters(kafkaCfg KafkaConfig, topic string, limit int) ([]DeadLetter, error) {
+	reader, err := newDeadLetterReader(kafkaCfg)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.close()
+
+	partitions, err := reader.consumer.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions of %s, with error: %w", topic, err)
+	}
+
+	var letters []DeadLetter
+	for _, partition := range partitions {
+		partitionLetters, err := reader.read(topic, partition, sarama.OffsetOldest, limit)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, partitionLetters...)
+	}
+
+	sort.Slice(letters, func(i, j int) bool { return letters[i].FailedAt.Before(letters[j].FailedAt) })
+	if limit > 0 && len(letters) > limit {
+		letters = letters[:limit]
+	}
+	return letters, nil
+}
+
+// ReadDeadLetter return dead letter stored at partition and offset of topic
+func ReadDeadLetter(kafkaCfg KafkaConfig, topic string, partition int32, offset int64) (DeadLetter, error) {
+	reader, err := newDeadLetterReader(kafkaCfg)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+	defer reader.close()
+
+	letters, err := reader.read(topic, partition, offset, 1)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+	if len(letters) == 0 || letters[0].DeadLetterOffset != offset {
+		return DeadLetter{}, fmt.Errorf("no dead letter at %s partition %d offset %d", topic, partition, offset)
+	}
+	return letters[0], nil
+}
+
+type deadLetterReader struct {
+	client   sarama.Client
+	consumer sarama.Consumer
+}
+
+func newDeadLetterReader(kafkaCfg KafkaConfig) (*deadLetterReader, error) {
+	cfg, err := newSaramaConfig(kafkaCfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sarama.NewClient(kafkaCfg.Brokers, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead letter reader, with error: %w", err)
+	}
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to create dead letter reader, with error: %w", err)
+	}
+	return &deadLetterReader{client, consumer}, nil
+}
+
+// read from offset until limit or end of partition at the time it was opened
+func (r *deadLetterReader) read(topic string, partition int32, offset int64, limit int) ([]DeadLetter, error) {
+	newest, err := r.client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get end offset of %s partition %d, with error: %w", topic, partition, err)
+	}
+	if offset >= newest {
+		return nil, nil
+	}
+
+	partitionConsumer, err := r.consumer.ConsumePartition(topic, partition, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s partition %d, with error: %w", topic, partition, err)
+	}
+	defer partitionConsumer.Close()
+
+	var letters []DeadLetter
+	for limit <= 0 || len(letters) < limit {
+		select {
+		case message := <-partitionConsumer.Messages():
+			letters = append(letters, deadLetterFromMessage(message))
+			if message.Offset >= newest-1 {
+				return letters, nil
+			}
+		case err := <-partitionConsumer.Errors():
+			return nil, fmt.Errorf("failed to read %s partition %d, with error: %w", topic, partition, err)
+		case <-time.After(deadLetterReadTimeout):
+			return letters, nil
+		}
+	}
+	return letters, nil
+}
+
+func (r *deadLetterReader) close() {
+	r.consumer.Close()
+	r.client.Close()
+}
+
+func deadLetterFromMessage(message *sarama.ConsumerMessage) DeadLetter {
+	letter := DeadLetter{
+		Key:                 string(message.Key),
+		Value:               message.Value,
+		Headers:             map[string]string{},
+		DeadLetterPartition: message.Partition,
+		DeadLetterOffset:    message.Offset,
+	}
+
+	for _, header := range message.Headers {
+		if header == nil {
+			continue
+		}
+		key, value := string(header.Key), string(header.Value)
+		switch key {
+		case HeaderDeadLetterSource:
+			letter.Source = value
+		case HeaderDeadLetterReason:
+			letter.Reason = value
+		case HeaderDeadLetterTopic:
+			letter.Topic = value
+		case HeaderDeadLetterPartition:
+			partition, _ := strconv.ParseInt(value, 10, 32)
+			letter.Partition = int32(partition)
+		case HeaderDeadLetterOffset:
+			letter.Offset, _ = strconv.ParseInt(value, 10, 64)
+		case HeaderDeadLetterFailedAt:
+			letter.FailedAt, _ = time.Parse(time.RFC3339Nano, value)
+		default:
+			if !strings.HasPrefix(key, deadLetterHeaderPrefix) {
+				letter.Headers[key] = value
+			}
+		}
+	}
+	return letter
+}
+
+// recordHeaders turn header map back into record headers in stable order
+func recordHeaders(headers map[string]string) []sarama.RecordHeader {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	records := make([]sarama.RecordHeader, 0, len(keys))
+	for _, key := range keys {
+		records = append(records, sarama.RecordHeader{Key: []byte(key), Value: []byte(headers[key])})
+	}
+	return records
+}
@@ -0,0 +1,139 @@
+package kafkahelper
+
+import (
+	"briefcash-transfer/internal/helper/correlationhelper"
+	"context"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	HeaderMerchantCode   = "X-MERCHANT-CODE"
+	HeaderSchemaVersion  = "X-SCHEMA-VERSION"
+	HeaderContentType    = "Content-Type"
+	HeaderIdempotencyKey = "X-IDEMPOTENCY-KEY"
+
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeJson     = "application/json"
+)
+
+// MessageHeaders describe message for consumer without decoding its payload, empty value is not sent
+type MessageHeaders struct {
+	ExternalId     string
+	MerchantCode   string
+	SchemaVersion  string
+	ContentType    string
+	IdempotencyKey string
+}
+
+// MessageKey partition message by merchant and its reference, messages of one transfer stay in order,
+// transfers of one merchant spread over partitions and are not ordered between each other
+func MessageKey(merchantCode, reference string) string {
+	return merchantCode + ":" + reference
+}
+
+// InjectHeaders write message headers, trace context and correlation id are injected separately
+func InjectHeaders(message *sarama.ProducerMessage, headers MessageHeaders) {
+	carrier := producerHeaderCarrier{message}
+	for key, value := range map[string]string{
+		correlationhelper.HeaderExternalId: headers.ExternalId,
+		HeaderMerchantCode:                 headers.MerchantCode,
+		HeaderSchemaVersion:                headers.SchemaVersion,
+		HeaderContentType:                  headers.ContentType,
+		HeaderIdempotencyKey:               headers.IdempotencyKey,
+	} {
+		if value != "" {
+			carrier.Set(key, value)
+		}
+	}
+}
+
+// HeaderValue return value of consumed message header, empty when message does not carry it
+func HeaderValue(message *sarama.ConsumerMessage, key string) string {
+	return consumerHeaderCarrier{message}.Get(key)
+}
+
+// producerHeaderCarrier expose sarama producer headers to otel propagator
+type producerHeaderCarrier struct {
+	message *sarama.ProducerMessage
+}
+
+func (c producerHeaderCarrier) Get(key string) string {
+	for _, header := range c.message.Headers {
+		if string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func (c producerHeaderCarrier) Set(key, value string) {
+	for i, header := range c.message.Headers {
+		if string(header.Key) == key {
+			c.message.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.message.Headers = append(c.message.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c producerHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.message.Headers))
+	for _, header := range c.message.Headers {
+		keys = append(keys, string(header.Key))
+	}
+	return keys
+}
+
+// consumerHeaderCarrier expose sarama consumer headers to otel propagator
+type consumerHeaderCarrier struct {
+	message *sarama.ConsumerMessage
+}
+
+func (c consumerHeaderCarrier) Get(key string) string {
+	for _, header := range c.message.Headers {
+		if header != nil && string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func (c consumerHeaderCarrier) Set(key, value string) {}
+
+func (c consumerHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.message.Headers))
+	for _, header := range c.message.Headers {
+		if header != nil {
+			keys = append(keys, string(header.Key))
+		}
+	}
+	return keys
+}
+
+// InjectTraceContext write W3C traceparent of ctx into message headers
+func InjectTraceContext(ctx context.Context, message *sarama.ProducerMessage) {
+	otel.GetTextMapPropagator().Inject(ctx, producerHeaderCarrier{message})
+}
+
+// InjectCorrelationId write correlation id of ctx into message headers, consumer keep it in its logs
+func InjectCorrelationId(ctx context.Context, message *sarama.ProducerMessage) {
+	if correlationId := correlationhelper.FromContext(ctx); correlationId != "" {
+		producerHeaderCarrier{message}.Set(correlationhelper.HeaderCorrelationId, correlationId)
+	}
+}
+
+// ExtractCorrelationId continue correlation id of producer, new one is generated when message has none
+func ExtractCorrelationId(ctx context.Context, message *sarama.ConsumerMessage) (context.Context, string) {
+	correlationId := consumerHeaderCarrier{message}.Get(correlationhelper.HeaderCorrelationId)
+	if correlationId == "" {
+		correlationId = correlationhelper.New()
+	}
+	return correlationhelper.NewContext(ctx, correlationId), correlationId
+}
+
+// ExtractTraceContext continue trace of producer from message headers
+func ExtractTraceContext(ctx context.Context, message *sarama.ConsumerMessage) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, consumerHeaderCarrier{message})
+}
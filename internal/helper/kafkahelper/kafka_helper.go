@@ -1,64 +1,323 @@
 package kafkahelper
 
 import (
+	"briefcash-transfer/internal/helper/metrichelper"
+	"briefcash-transfer/internal/helper/tracehelper"
+	"context"
+	"fmt"
 	"log"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	ProducerModeSync  = "sync"
+	ProducerModeAsync = "async"
+)
+
+var saramaLogger sync.Once
+
+// DeliveryCallback receive broker result of one message, nil error means message is acknowledged by all in sync replica
+type DeliveryCallback func(err error)
+
 type KafkaProducer struct {
+	Client   sarama.Client
 	Producer sarama.SyncProducer
+	Async    sarama.AsyncProducer
 	Brokers  []string
+
+	// deadLetterTopic receive message broker failed to acknowledge, empty disables dead lettering
+	deadLetterTopic string
+
+	// deadLetterProducer write dead letter of async producer outside its buffer, so they are kept when buffer is full or producer is closing
+	deadLetterProducer sarama.SyncProducer
+
+	// buffer hold one slot per message not yet reported by async producer, full buffer reject new message
+	buffer     chan struct{}
+	mutex      sync.RWMutex
+	closed     bool
+	dispatched chan struct{}
+	callbacks  sync.WaitGroup
 }
 
-func NewKafkaProducer(brokers []string) (*KafkaProducer, error) {
-	cfg := sarama.NewConfig()
+func NewKafkaProducer(kafkaCfg KafkaConfig) (*KafkaProducer, error) {
+	cfg, err := newSaramaConfig(kafkaCfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// producer config
 	cfg.Producer.Return.Successes = true
 	cfg.Producer.Return.Errors = true
 	cfg.Producer.RequiredAcks = sarama.WaitForAll
-	cfg.Producer.Retry.Max = 3
-	cfg.Producer.Retry.Backoff = 100 * time.Millisecond
-	cfg.Producer.Timeout = 5 * time.Second
+	cfg.Producer.Retry.Max = kafkaCfg.RetryMax
+	cfg.Producer.Retry.Backoff = kafkaCfg.RetryBackoff
+	cfg.Producer.Timeout = kafkaCfg.ProducerTimeout
 	cfg.Producer.Partitioner = sarama.NewHashPartitioner
 
-	// network config
-	cfg.Net.DialTimeout = 5 * time.Second
-	cfg.Net.ReadTimeout = 5 * time.Second
-	cfg.Net.WriteTimeout = 5 * time.Second
+	// idempotent producer let broker drop duplicate of retried batch, it needs single in flight request and at least one retry
+	cfg.Producer.Idempotent = true
+	cfg.Net.MaxOpenRequests = 1
+	if cfg.Producer.Retry.Max < 1 {
+		cfg.Producer.Retry.Max = 1
+	}
+
+	// sarama logger is global and read by running clients, it is set only once
+	saramaLogger.Do(func() {
+		sarama.Logger = log.New(os.Stdout, "[Sarama]", log.LstdFlags)
+	})
+
+	if kafkaCfg.ProducerMode == ProducerModeAsync {
+		// input channel can hold every buffered message, so handing message to producer never blocks request
+		cfg.ChannelBufferSize = kafkaCfg.AsyncBufferSize
+	}
+
+	// keep client so broker metadata can be checked after startup
+	client, err := sarama.NewClient(kafkaCfg.Brokers, cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	cfg.Version = sarama.V3_0_2_0
+	kafkaProducer := &KafkaProducer{
+		Client:          client,
+		Brokers:         kafkaCfg.Brokers,
+		deadLetterTopic: kafkaCfg.DeadLetterTopic,
+	}
 
-	sarama.Logger = log.New(os.Stdout, "[Sarama]", log.LstdFlags)
+	if kafkaCfg.ProducerMode == ProducerModeAsync {
+		kafkaProducer.Async, err = sarama.NewAsyncProducerFromClient(client)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+		if kafkaCfg.DeadLetterTopic != "" {
+			kafkaProducer.deadLetterProducer, err = sarama.NewSyncProducerFromClient(client)
+			if err != nil {
+				kafkaProducer.Async.Close()
+				client.Close()
+				return nil, err
+			}
+		}
+		kafkaProducer.buffer = make(chan struct{}, kafkaCfg.AsyncBufferSize)
+		kafkaProducer.dispatched = make(chan struct{})
+		go kafkaProducer.dispatchDeliveries()
+		return kafkaProducer, nil
+	}
 
-	producer, err := sarama.NewSyncProducer(brokers, cfg)
+	kafkaProducer.Producer, err = sarama.NewSyncProducerFromClient(client)
 	if err != nil {
+		client.Close()
 		return nil, err
 	}
+	return kafkaProducer, nil
+}
+
+// Publish send message and wait for broker acknowledgement in either producer mode
+func (kp *KafkaProducer) Publish(ctx context.Context, topic, key string, value []byte, headers MessageHeaders) error {
+	msg, report := kp.newMessage(ctx, topic, key, value, headers, nil)
+	return kp.sendAndWait(ctx, msg, report)
+}
+
+// PublishAsync hand message to producer, onDelivery receives broker result once it is known.
+// Error is returned only when message is not accepted, onDelivery is not called then.
+// Sync producer delivers message before returning, so onDelivery runs before PublishAsync returns.
+func (kp *KafkaProducer) PublishAsync(ctx context.Context, topic, key string, value []byte, headers MessageHeaders, onDelivery DeliveryCallback) error {
+	msg, report := kp.newMessage(ctx, topic, key, value, headers, onDelivery)
+	if kp.Async == nil {
+		report.onDelivery = nil
+		_, _, err := kp.Producer.SendMessage(msg)
+		report.complete(err)
+		if err != nil {
+			return err
+		}
+		onDelivery(nil)
+		return nil
+	}
+	return kp.enqueue(msg, report)
+}
+
+func (kp *KafkaProducer) sendAndWait(ctx context.Context, msg *sarama.ProducerMessage, report *deliveryReport) error {
+	if kp.Async == nil {
+		_, _, err := kp.Producer.SendMessage(msg)
+		report.complete(err)
+		return err
+	}
 
-	return &KafkaProducer{
-		Producer: producer,
-		Brokers:  brokers,
-	}, nil
+	result := make(chan error, 1)
+	report.onDelivery = func(err error) { result <- err }
+	if err := kp.enqueue(msg, report); err != nil {
+		return err
+	}
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-func (kp *KafkaProducer) Publish(topic, key string, value []byte) error {
+// enqueue hand message to async producer when buffer has room, rejected message is never reported
+func (kp *KafkaProducer) enqueue(msg *sarama.ProducerMessage, report *deliveryReport) error {
+	// read lock keep producer input open until message is handed over
+	kp.mutex.RLock()
+	defer kp.mutex.RUnlock()
+	if kp.closed {
+		tracehelper.EndSpan(report.span, ErrProducerClosed)
+		return ErrProducerClosed
+	}
+
+	select {
+	case kp.buffer <- struct{}{}:
+	default:
+		metrichelper.KafkaBufferFullTotal.WithLabelValues(msg.Topic).Inc()
+		tracehelper.EndSpan(report.span, ErrBufferFull)
+		return ErrBufferFull
+	}
+	metrichelper.KafkaBufferedMessages.Inc()
+
+	msg.Metadata = report
+	kp.Async.Input() <- msg
+	return nil
+}
+
+// newMessage build message with transfer headers, its failed delivery is written to dead letter topic
+func (kp *KafkaProducer) newMessage(ctx context.Context, topic, key string, value []byte, headers MessageHeaders, onDelivery DeliveryCallback) (*sarama.ProducerMessage, *deliveryReport) {
+	msg, report := kp.newRawMessage(ctx, topic, sarama.StringEncoder(key), value, nil)
+	report.span.SetAttributes(attribute.String("messaging.kafka.message.key", key))
+	InjectHeaders(msg, headers)
+	InjectTraceContext(ctx, msg)
+	InjectCorrelationId(ctx, msg)
+
+	report.onDelivery = onDelivery
+	report.deadLetter = true
+	return msg, report
+}
+
+func (kp *KafkaProducer) newRawMessage(ctx context.Context, topic string, key sarama.Encoder, value []byte, headers []sarama.RecordHeader) (*sarama.ProducerMessage, *deliveryReport) {
+	ctx, span := tracehelper.StartSpan(ctx, "kafka.publish",
+		attribute.String("messaging.system", "kafka"),
+		attribute.String("messaging.destination.name", topic),
+	)
+
 	msg := &sarama.ProducerMessage{
-		Topic: topic,
-		Key:   sarama.StringEncoder(key),
-		Value: sarama.ByteEncoder(value),
+		Topic:   topic,
+		Key:     key,
+		Value:   sarama.ByteEncoder(value),
+		Headers: headers,
+	}
+	return msg, &deliveryReport{ctx: context.WithoutCancel(ctx), span: span, topic: topic, start: time.Now(), producer: kp, message: msg}
+}
+
+// dispatchDeliveries route async producer result to callback of each message until producer is closed
+func (kp *KafkaProducer) dispatchDeliveries() {
+	defer close(kp.dispatched)
+
+	successes, errs := kp.Async.Successes(), kp.Async.Errors()
+	for successes != nil || errs != nil {
+		select {
+		case msg, ok := <-successes:
+			if !ok {
+				successes = nil
+				continue
+			}
+			kp.report(msg, nil)
+		case producerErr, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			kp.report(producerErr.Msg, producerErr.Err)
+		}
+	}
+}
+
+// report run callback outside dispatcher so slow callback does not hold back other deliveries,
+// buffer slot is released after callback so running callbacks are bounded by buffer size
+func (kp *KafkaProducer) report(msg *sarama.ProducerMessage, err error) {
+	metrichelper.KafkaBufferedMessages.Dec()
+	report, ok := msg.Metadata.(*deliveryReport)
+	if !ok {
+		<-kp.buffer
+		return
 	}
 
-	_, _, err := kp.Producer.SendMessage(msg)
-	return err
+	kp.callbacks.Add(1)
+	go func() {
+		defer kp.callbacks.Done()
+		defer func() { <-kp.buffer }()
+		report.complete(err)
+	}()
 }
 
+// Ping refresh cluster metadata, fails when no broker is reachable
+func (kp *KafkaProducer) Ping() error {
+	if err := kp.Client.RefreshMetadata(); err != nil {
+		return fmt.Errorf("failed to refresh kafka metadata: %w", err)
+	}
+	if len(kp.Client.Brokers()) == 0 {
+		return fmt.Errorf("no kafka broker available")
+	}
+	return nil
+}
+
+// Close flush buffered message and wait for their callbacks, which may still write dead letters, before closing client
 func (kp *KafkaProducer) Close() error {
+	if kp.Async != nil {
+		kp.mutex.Lock()
+		if kp.closed {
+			kp.mutex.Unlock()
+			return nil
+		}
+		kp.closed = true
+		kp.mutex.Unlock()
+
+		kp.Async.AsyncClose()
+		<-kp.dispatched
+		kp.callbacks.Wait()
+		if kp.deadLetterProducer != nil {
+			if err := kp.deadLetterProducer.Close(); err != nil {
+				return err
+			}
+		}
+		return kp.Client.Close()
+	}
+
 	if kp.Producer == nil {
 		return nil
 	}
-	return kp.Producer.Close()
+	if err := kp.Producer.Close(); err != nil {
+		return err
+	}
+	return kp.Client.Close()
+}
+
+// deliveryReport follow one message from publish until broker result is known
+type deliveryReport struct {
+	ctx        context.Context
+	span       trace.Span
+	topic      string
+	start      time.Time
+	onDelivery DeliveryCallback
+	producer   *KafkaProducer
+	message    *sarama.ProducerMessage
+	deadLetter bool
+}
+
+func (r *deliveryReport) complete(err error) {
+	tracehelper.EndSpan(r.span, err)
+	metrichelper.KafkaPublishDuration.WithLabelValues(r.topic).Observe(time.Since(r.start).Seconds())
+	if err != nil {
+		metrichelper.KafkaPublishErrorTotal.WithLabelValues(r.topic).Inc()
+		if r.deadLetter {
+			r.producer.deadLetterProduced(r.ctx, r.message, err)
+		}
+	}
+	if r.onDelivery != nil {
+		r.onDelivery(err)
+	}
 }
@@ -0,0 +1,194 @@
+package kafkahelper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func TestMain(m *testing.M) {
+	saramaLogger.Do(func() {
+		sarama.Logger = log.New(io.Discard, "", 0)
+	})
+	os.Exit(m.Run())
+}
+
+const (
+	testTopic           = "transfer-instruction"
+	testDeadLetterTopic = "transfer-dead-letter"
+)
+
+// brokerLatency stand in for WaitForAll round trip to in sync replicas
+const brokerLatency = 2 * time.Millisecond
+
+func newMockBroker(tb testing.TB, latency time.Duration) *sarama.MockBroker {
+	tb.Helper()
+	broker := sarama.NewMockBroker(tb, 1)
+	broker.SetLatency(latency)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(tb),
+		"MetadataRequest": sarama.NewMockMetadataResponse(tb).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetController(broker.BrokerID()).
+			SetLeader(testTopic, 0, broker.BrokerID()).
+			SetLeader(testDeadLetterTopic, 0, broker.BrokerID()),
+		"InitProducerIDRequest": sarama.NewMockInitProducerIDResponse(tb).SetProducerID(1000),
+		"ProduceRequest":        sarama.NewMockProduceResponse(tb),
+	})
+	tb.Cleanup(broker.Close)
+	return broker
+}
+
+func newTestProducer(tb testing.TB, broker *sarama.MockBroker, mode string, bufferSize int) *KafkaProducer {
+	tb.Helper()
+	return newTestProducerWithDeadLetter(tb, broker, mode, bufferSize, "")
+}
+
+func newTestProducerWithDeadLetter(tb testing.TB, broker *sarama.MockBroker, mode string, bufferSize int, deadLetterTopic string) *KafkaProducer {
+	tb.Helper()
+	producer, err := NewKafkaProducer(KafkaConfig{
+		Brokers:         []string{broker.Addr()},
+		RetryMax:        3,
+		RetryBackoff:    10 * time.Millisecond,
+		ProducerTimeout: time.Second,
+		NetTimeout:      time.Second,
+		ProducerMode:    mode,
+		AsyncBufferSize: bufferSize,
+		DeadLetterTopic: deadLetterTopic,
+	})
+	if err != nil {
+		tb.Fatalf("failed to create %s producer: %v", mode, err)
+	}
+	return producer
+}
+
+var testHeaders = MessageHeaders{MerchantCode: "MRC001", SchemaVersion: "3", ContentType: ContentTypeProtobuf}
+
+func TestAsyncProducerReportsDelivery(t *testing.T) {
+	producer := newTestProducer(t, newMockBroker(t, 0), ProducerModeAsync, 10)
+
+	delivered := make(chan error, 1)
+	err := producer.PublishAsync(context.Background(), testTopic, MessageKey("MRC001", "PRN-0001"), []byte("instruction"), testHeaders, func(err error) {
+		delivered <- err
+	})
+	if err != nil {
+		t.Fatalf("message was not accepted: %v", err)
+	}
+
+	select {
+	case err := <-delivered:
+		if err != nil {
+			t.Fatalf("delivery reported error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery was never reported")
+	}
+
+	if err := producer.Close(); err != nil {
+		t.Fatalf("failed to close producer: %v", err)
+	}
+	if err := producer.PublishAsync(context.Background(), testTopic, "key", nil, testHeaders, func(error) {}); !errors.Is(err, ErrProducerClosed) {
+		t.Fatalf("publish after close = %v, want %v", err, ErrProducerClosed)
+	}
+}
+
+func TestAsyncProducerRejectsWhenBufferFull(t *testing.T) {
+	producer := newTestProducer(t, newMockBroker(t, 200*time.Millisecond), ProducerModeAsync, 2)
+
+	var delivered sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		delivered.Add(1)
+		if err := producer.PublishAsync(context.Background(), testTopic, strconv.Itoa(i), []byte("instruction"), testHeaders, func(error) { delivered.Done() }); err != nil {
+			t.Fatalf("message %d was not accepted: %v", i, err)
+		}
+	}
+
+	err := producer.PublishAsync(context.Background(), testTopic, "overflow", []byte("instruction"), testHeaders, func(error) {
+		t.Error("rejected message must not be reported")
+	})
+	if !errors.Is(err, ErrBufferFull) {
+		t.Fatalf("publish on full buffer = %v, want %v", err, ErrBufferFull)
+	}
+
+	// slot is released once buffered message is acknowledged
+	delivered.Wait()
+	if err := producer.PublishAsync(context.Background(), testTopic, "after", []byte("instruction"), testHeaders, func(error) {}); err != nil {
+		t.Fatalf("message after buffer drained was not accepted: %v", err)
+	}
+	if err := producer.Close(); err != nil {
+		t.Fatalf("failed to close producer: %v", err)
+	}
+}
+
+func TestAsyncProducerWritesDeadLetterWhenBufferFull(t *testing.T) {
+	producer := newTestProducerWithDeadLetter(t, newMockBroker(t, 200*time.Millisecond), ProducerModeAsync, 1, testDeadLetterTopic)
+
+	delivered := make(chan error, 1)
+	if err := producer.PublishAsync(context.Background(), testTopic, "0", []byte("instruction"), testHeaders, func(err error) { delivered <- err }); err != nil {
+		t.Fatalf("message was not accepted: %v", err)
+	}
+	if err := producer.PublishAsync(context.Background(), testTopic, "overflow", []byte("instruction"), testHeaders, func(error) {}); !errors.Is(err, ErrBufferFull) {
+		t.Fatalf("publish on full buffer = %v, want %v", err, ErrBufferFull)
+	}
+
+	err := producer.PublishDeadLetter(context.Background(), DeadLetter{
+		Source:   DeadLetterSourceProduce,
+		Topic:    testTopic,
+		Key:      "overflow",
+		Value:    []byte("instruction"),
+		Reason:   "broker unavailable",
+		FailedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("dead letter on full buffer = %v, want written", err)
+	}
+
+	<-delivered
+	if err := producer.Close(); err != nil {
+		t.Fatalf("failed to close producer: %v", err)
+	}
+}
+
+// BenchmarkPublish compare time request spends handing one transfer instruction to kafka, run with -cpu to vary concurrent requests
+func BenchmarkPublish(b *testing.B) {
+	for _, mode := range []string{ProducerModeSync, ProducerModeAsync} {
+		b.Run(mode, func(b *testing.B) {
+			producer := newTestProducer(b, newMockBroker(b, brokerLatency), mode, 10000)
+			value := make([]byte, 512)
+
+			var delivered sync.WaitGroup
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					i++
+					delivered.Add(1)
+					err := producer.PublishAsync(context.Background(), testTopic, strconv.Itoa(i), value, testHeaders, func(error) { delivered.Done() })
+					if errors.Is(err, ErrBufferFull) {
+						delivered.Done()
+						continue
+					}
+					if err != nil {
+						b.Error(err)
+						delivered.Done()
+					}
+				}
+			})
+			b.StopTimer()
+
+			delivered.Wait()
+			if err := producer.Close(); err != nil {
+				b.Fatal(err)
+			}
+		})
+	}
+}
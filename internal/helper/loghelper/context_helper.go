@@ -0,0 +1,24 @@
+package loghelper
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type loggerKey struct{}
+
+// NewContext carry request scoped logger, fields added by caller are kept by every layer below
+func NewContext(ctx context.Context, log *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey{}, log)
+}
+
+// FromContext return logger carried by context, fallback to root logger when there is none
+func FromContext(ctx context.Context) *logrus.Entry {
+	if ctx != nil {
+		if log, ok := ctx.Value(loggerKey{}).(*logrus.Entry); ok && log != nil {
+			return log
+		}
+	}
+	return logrus.NewEntry(Logger)
+}
@@ -7,20 +7,47 @@ import (
 	"path/filepath"
 
 	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
 )
 
 var Logger *logrus.Logger
 
-func InitLogger(logFile string, level logrus.Level) {
+type LoggerConfig struct {
+	File       string
+	Level      logrus.Level
+	Format     string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	Compress   bool
+}
+
+func InitLogger(cfg LoggerConfig) {
 	Logger = logrus.New()
 
-	Logger.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp: true,
-	})
+	if cfg.Format == FormatJSON {
+		Logger.SetFormatter(&logrus.JSONFormatter{
+			TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
+			FieldMap: logrus.FieldMap{
+				logrus.FieldKeyTime: "timestamp",
+				logrus.FieldKeyMsg:  "message",
+			},
+		})
+	} else {
+		Logger.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp: true,
+		})
+	}
 
-	Logger.SetLevel(level)
+	Logger.SetLevel(cfg.Level)
+	Logger.AddHook(&maskingHook{})
 
-	directory := filepath.Dir(logFile)
+	directory := filepath.Dir(cfg.File)
 	if _, err := os.Stat(directory); os.IsNotExist(err) {
 		err := os.MkdirAll(directory, 0755)
 		if err != nil {
@@ -30,16 +57,18 @@ func InitLogger(logFile string, level logrus.Level) {
 		}
 	}
 
-	file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_RDONLY, 0666)
-
-	if err != nil {
-		fmt.Printf("Failed to open log file %v", err)
-		Logger.SetOutput(os.Stdout)
-		return
+	// log file rotated by size, old file removed by count and age
+	file := &lumberjack.Logger{
+		Filename:   cfg.File,
+		MaxSize:    cfg.MaxSizeMB,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAgeDays,
+		Compress:   cfg.Compress,
+		LocalTime:  true,
 	}
 
 	multiWriter := io.MultiWriter(os.Stdout, file)
 	Logger.SetOutput(multiWriter)
 
-	Logger.Infof("Logger initiate, log file %s", logFile)
+	Logger.Infof("Logger initiate, log file %s", cfg.File)
 }
@@ -0,0 +1,173 @@
+package loghelper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maskNone = iota
+	maskNumber
+	maskEmail
+	maskFull
+)
+
+const maskVisibleDigit = 4
+
+var emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+// keyValuePattern match key and value written as json member, query parameter or key: value inside free text
+var keyValuePattern = regexp.MustCompile(`([A-Za-z][A-Za-z0-9_.\-]*)("?\s*[:=]\s*)("[^"]*"|[^\s"',;&}\]]+)`)
+
+// maskKind decide masking of field by its normalized name, so snake, camel and kebab case are all covered
+func maskKind(key string) int {
+	normalized := strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(key))
+
+	switch {
+	case strings.Contains(normalized, "beneficiaryaccount"), strings.Contains(normalized, "accountno"), strings.Contains(normalized, "accountnumber"),
+		strings.Contains(normalized, "customerno"), strings.Contains(normalized, "customernumber"),
+		strings.Contains(normalized, "phone"):
+		return maskNumber
+	case strings.Contains(normalized, "email"):
+		return maskEmail
+	case strings.Contains(normalized, "address") && !strings.Contains(normalized, "ip"),
+		strings.Contains(normalized, "secret"):
+		return maskFull
+	}
+	return maskNone
+}
+
+// MaskString mask value of sensitive field, non sensitive field returned as is
+func MaskString(key, value string) string {
+	switch maskKind(key) {
+	case maskNumber:
+		if len(value) <= maskVisibleDigit {
+			return strings.Repeat("*", len(value))
+		}
+		return strings.Repeat("*", len(value)-maskVisibleDigit) + value[len(value)-maskVisibleDigit:]
+	case maskEmail:
+		return MaskEmail(value)
+	case maskFull:
+		if value == "" {
+			return value
+		}
+		return "***"
+	}
+	return value
+}
+
+// MaskEmail keep first character of local part and the domain of every email in text
+func MaskEmail(text string) string {
+	return emailPattern.ReplaceAllString(text, "$1***@$2")
+}
+
+// MaskText mask value of sensitive key written inside free text and every email, used for message and error text
+func MaskText(text string) string {
+	masked := keyValuePattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := keyValuePattern.FindStringSubmatch(match)
+		if maskKind(parts[1]) == maskNone {
+			return match
+		}
+		if value, quoted := strings.CutPrefix(parts[3], `"`); quoted {
+			return parts[1] + parts[2] + `"` + MaskString(parts[1], strings.TrimSuffix(value, `"`)) + `"`
+		}
+		return parts[1] + parts[2] + MaskString(parts[1], parts[3])
+	})
+	return MaskEmail(masked)
+}
+
+// MaskJSON mask sensitive field in json document, non json body only has its email masked
+func MaskJSON(body []byte) []byte {
+	// number is kept as written, long account number decoded to float would be masked from its exponent form
+	var document any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil || decoder.More() {
+		return []byte(MaskEmail(string(body)))
+	}
+
+	masked, err := json.Marshal(maskAny("", document))
+	if err != nil {
+		return []byte(MaskEmail(string(body)))
+	}
+	return masked
+}
+
+func maskAny(key string, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = maskAny(k, item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = maskAny(key, item)
+		}
+		return v
+	case string:
+		return MaskString(key, v)
+	case nil, bool:
+		return v
+	default:
+		if maskKind(key) == maskNone {
+			return v
+		}
+		return MaskString(key, fmt.Sprint(v))
+	}
+}
+
+// maskingHook mask sensitive field, error and message before entry is formatted
+type maskingHook struct{}
+
+func (h *maskingHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *maskingHook) Fire(entry *logrus.Entry) error {
+	entry.Message = MaskText(entry.Message)
+
+	for key, value := range entry.Data {
+		if value == nil {
+			continue
+		}
+
+		// error text may carry request payload or sql argument, replaced only when something is masked
+		if err, ok := value.(error); ok {
+			if masked := MaskText(err.Error()); masked != err.Error() {
+				entry.Data[key] = masked
+			}
+			continue
+		}
+
+		switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+			// structured value is masked through its json representation
+			raw, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+			masked := MaskJSON(raw)
+			if string(masked) == string(raw) {
+				continue
+			}
+			var document any
+			if err := json.Unmarshal(masked, &document); err == nil {
+				entry.Data[key] = document
+			}
+		case reflect.String:
+			entry.Data[key] = MaskText(MaskString(key, fmt.Sprint(value)))
+		default:
+			if maskKind(key) != maskNone {
+				entry.Data[key] = MaskString(key, fmt.Sprint(value))
+			}
+		}
+	}
+	return nil
+}
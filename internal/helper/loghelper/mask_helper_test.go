@@ -0,0 +1,149 @@
+package loghelper
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestMaskString(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		expected string
+	}{
+		{"non sensitive field", "partnerReferenceNo", "MRC-20250101-0001", "MRC-20250101-0001"},
+		{"account number camel case", "beneficiaryAccountNo", "1234567890", "******7890"},
+		{"account number snake case", "beneficiary_account_number", "1234567890", "******7890"},
+		{"account number kebab case", "source-account-no", "9876543210", "******3210"},
+		{"customer number", "customerNo", "6281234567890", "*********7890"},
+		{"phone", "phoneNumber", "081234567890", "********7890"},
+		{"number not longer than visible digit", "accountNo", "1234", "****"},
+		{"email", "beneficiaryEmail", "john.doe@example.com", "j***@example.com"},
+		{"address", "beneficiaryAddress", "Jl. Jend. Sudirman Kav. 52-53", "***"},
+		{"empty address", "address", "", ""},
+		{"ip address is not masked", "ipAddress", "10.0.0.1", "10.0.0.1"},
+		{"secret", "secret", "merchant-signing-secret", "***"},
+		{"client secret", "client_secret", "abc123", "***"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaskString(tt.key, tt.value); got != tt.expected {
+				t.Errorf("MaskString(%q, %q) = %q, want %q", tt.key, tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMaskJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "nested sensitive field",
+			body:     `{"partnerReferenceNo":"MRC-1","beneficiaryAccountNo":"1234567890","additionalInfo":{"beneficiaryEmail":"john.doe@example.com"}}`,
+			expected: `{"additionalInfo":{"beneficiaryEmail":"j***@example.com"},"beneficiaryAccountNo":"******7890","partnerReferenceNo":"MRC-1"}`,
+		},
+		{
+			name:     "array keeps key of parent",
+			body:     `{"beneficiaryAddress":["Jl. Sudirman","Senayan"],"amount":{"value":"150000.00"}}`,
+			expected: `{"amount":{"value":"150000.00"},"beneficiaryAddress":["***","***"]}`,
+		},
+		{
+			name:     "number of sensitive field",
+			body:     `{"customerNumber":6281234567890,"count":3}`,
+			expected: `{"count":3,"customerNumber":"*********7890"}`,
+		},
+		{
+			name:     "webhook signing secret",
+			body:     `{"callbackUrl":"https://merchant.example.com/hook","secret":"merchant-signing-secret"}`,
+			expected: `{"callbackUrl":"https://merchant.example.com/hook","secret":"***"}`,
+		},
+		{
+			name:     "non json body only has email masked",
+			body:     `beneficiaryEmail john.doe@example.com`,
+			expected: `beneficiaryEmail j***@example.com`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(MaskJSON([]byte(tt.body))); got != tt.expected {
+				t.Errorf("MaskJSON() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMaskText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"plain text", "Transfer status updated to DONE", "Transfer status updated to DONE"},
+		{"email", "Notify john.doe@example.com", "Notify j***@example.com"},
+		{"key value", "Invalid beneficiaryAccountNo: 1234567890 for bank 014", "Invalid beneficiaryAccountNo: ******7890 for bank 014"},
+		{"query parameter", "GET /inquiry?accountNo=1234567890&bankCode=014", "GET /inquiry?accountNo=******7890&bankCode=014"},
+		{"json fragment", `partner responded {"accountNumber":"1234567890","responseCode":"2001600"}`, `partner responded {"accountNumber":"******7890","responseCode":"2001600"}`},
+		{"quoted address", `{"beneficiaryAddress":"Jl. Jend. Sudirman Kav. 52"}`, `{"beneficiaryAddress":"***"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaskText(tt.text); got != tt.expected {
+				t.Errorf("MaskText(%q) = %q, want %q", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMaskingHookFire(t *testing.T) {
+	type recipient struct {
+		Name          string `json:"name"`
+		AccountNumber string `json:"accountNumber"`
+	}
+
+	plainErr := errors.New("connection refused")
+	entry := &logrus.Entry{
+		Message: "Inquiry accountNo=1234567890 for john.doe@example.com",
+		Data: logrus.Fields{
+			"beneficiary_account": "1234567890",
+			"merchant":            "MRC001",
+			"phone":               int64(81234567890),
+			"recipient":           recipient{Name: "John", AccountNumber: "1234567890"},
+			"error":               errors.New(`failed to insert {"beneficiaryAccountNo":"1234567890"}`),
+			"cause":               plainErr,
+		},
+	}
+
+	if err := (&maskingHook{}).Fire(entry); err != nil {
+		t.Fatalf("Fire() returned error: %v", err)
+	}
+
+	if expected := "Inquiry accountNo=******7890 for j***@example.com"; entry.Message != expected {
+		t.Errorf("message = %q, want %q", entry.Message, expected)
+	}
+
+	expected := map[string]any{
+		"beneficiary_account": "******7890",
+		"merchant":            "MRC001",
+		"phone":               "*******7890",
+		"recipient":           map[string]any{"name": "John", "accountNumber": "******7890"},
+		"error":               `failed to insert {"beneficiaryAccountNo":"******7890"}`,
+		"cause":               plainErr,
+	}
+	for key, want := range expected {
+		if got := entry.Data[key]; !reflect.DeepEqual(got, want) {
+			raw, _ := json.Marshal(got)
+			t.Errorf("field %s = %s (%T), want %v", key, raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package metrichelper
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+)
+
+const namespace = "briefcash_transfer"
+
+var (
+	RequestTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Handled http request by route, http status, response code and channel",
+	}, []string{"method", "path", "status", "response_code", "channel"})
+
+	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Http request latency by route, response code and channel",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "path", "response_code", "channel"})
+
+	LockFailureTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_lock_failures_total",
+		Help:      "Failed acquisition of merchant balance lock by operation",
+	}, []string{"operation"})
+
+	FeeCacheTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fee_cache_requests_total",
+		Help:      "Fee setting lookup in redis by result hit or miss",
+	}, []string{"result"})
+
+	KafkaPublishDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "kafka_publish_duration_seconds",
+		Help:      "Kafka publish latency by topic",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"topic"})
+
+	KafkaPublishErrorTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_publish_errors_total",
+		Help:      "Failed kafka publish by topic",
+	}, []string{"topic"})
+
+	KafkaBufferedMessages = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "kafka_producer_buffered_messages",
+		Help:      "Message handed to async kafka producer and not yet acknowledged",
+	})
+
+	KafkaBufferFullTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_producer_buffer_full_total",
+		Help:      "Message rejected because async kafka producer buffer is full, by topic",
+	}, []string{"topic"})
+
+	DeadLetterTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_dead_letters_total",
+		Help:      "Message written to dead letter topic by source produce or consume and original topic",
+	}, []string{"source", "topic"})
+
+	PersistTransferDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "persist_transfer_duration_seconds",
+		Help:      "Database transaction duration of persisting transfer by result",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"result"})
+
+	CompensationTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "compensations_total",
+		Help:      "Refund and compensation executed by store and reason",
+	}, []string{"store", "reason"})
+
+	BalanceSyncFailureTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_balance_sync_failures_total",
+		Help:      "Database balance change redis could not follow after retry, merchant balance must be reloaded, alert on any increase",
+	}, []string{"operation"})
+
+	UnknownPartnerCodeTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "unknown_partner_response_codes_total",
+		Help:      "Partner response code without mapping, transfer is marked suspect, alert on any increase. Code itself is logged",
+	}, []string{"bank_code"})
+
+	VAInquiryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "va_inquiry_duration_seconds",
+		Help:      "Virtual account inquiry latency to partner by bank and result",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"bank_code", "result"})
+
+	WalletInquiryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "wallet_inquiry_duration_seconds",
+		Help:      "E-wallet account inquiry latency to provider by wallet code and result",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"wallet_code", "result"})
+)
+
+// RegisterPoolCollector expose connection pool stats of redis and database
+func RegisterPoolCollector(redisClient *redis.Client, db *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+	prometheus.MustRegister(&redisPoolCollector{client: redisClient})
+}
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+var (
+	redisHitsDesc       = prometheus.NewDesc(namespace+"_redis_pool_hits_total", "Free connection found in redis pool", nil, nil)
+	redisMissesDesc     = prometheus.NewDesc(namespace+"_redis_pool_misses_total", "Free connection not found in redis pool", nil, nil)
+	redisTimeoutsDesc   = prometheus.NewDesc(namespace+"_redis_pool_timeouts_total", "Wait for redis connection timed out", nil, nil)
+	redisTotalConnsDesc = prometheus.NewDesc(namespace+"_redis_pool_total_connections", "Connections in redis pool", nil, nil)
+	redisIdleConnsDesc  = prometheus.NewDesc(namespace+"_redis_pool_idle_connections", "Idle connections in redis pool", nil, nil)
+	redisStaleConnsDesc = prometheus.NewDesc(namespace+"_redis_pool_stale_connections_total", "Stale connections removed from redis pool", nil, nil)
+)
+
+type redisPoolCollector struct {
+	client *redis.Client
+}
+
+func (r *redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- redisHitsDesc
+	ch <- redisMissesDesc
+	ch <- redisTimeoutsDesc
+	ch <- redisTotalConnsDesc
+	ch <- redisIdleConnsDesc
+	ch <- redisStaleConnsDesc
+}
+
+func (r *redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := r.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(redisHitsDesc, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(redisMissesDesc, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(redisTimeoutsDesc, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(redisTotalConnsDesc, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(redisIdleConnsDesc, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(redisStaleConnsDesc, prometheus.CounterValue, float64(stats.StaleConns))
+}
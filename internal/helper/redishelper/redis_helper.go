@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-redsync/redsync/v4"
 	goredis "github.com/go-redsync/redsync/v4/redis/goredis/v9"
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -17,18 +18,19 @@ type RedisHelper struct {
 }
 
 func NewRedisHelper(cfg *config.Config) (*RedisHelper, error) {
-	if cfg.RedisHost == "" || cfg.RedisPort == "" {
+	if cfg.RedisHost == "" || cfg.RedisPort == 0 {
 		loghelper.Logger.Info("Redis host or port is not configured in environment")
 		return nil, fmt.Errorf("redis host or port is empty")
 	}
-	address := fmt.Sprintf("%s:%s", cfg.RedisHost, cfg.RedisPort)
+	address := fmt.Sprintf("%s:%d", cfg.RedisHost, cfg.RedisPort)
 	client := redis.NewClient(&redis.Options{
 		Addr:         address,
-		DB:           0,
-		PoolSize:     50,
-		MinIdleConns: 10,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
+		Password:     cfg.RedisPassword,
+		DB:           cfg.RedisDB,
+		PoolSize:     cfg.RedisPoolSize,
+		MinIdleConns: cfg.RedisMinIdleConns,
+		ReadTimeout:  cfg.RedisReadTimeout,
+		WriteTimeout: cfg.RedisWriteTimeout,
 	})
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -39,6 +41,11 @@ func NewRedisHelper(cfg *config.Config) (*RedisHelper, error) {
 		return nil, fmt.Errorf("failed to ping redis server")
 	}
 
+	// trace every command as child span of the request
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		return nil, fmt.Errorf("failed to register redis tracing: %w", err)
+	}
+
 	loghelper.Logger.Info("Redis server connected successfully")
 
 	return &RedisHelper{Client: client}, nil
@@ -4,11 +4,20 @@ import "time"
 
 const ISOLayoutWithMillisAndTimezone = "2006-01-02T15:04:05.000-07.00"
 
+var WIB = time.FixedZone("WIB", 7*60*60)
+
 func FormatTimeToISO7(t time.Time) string {
-	location := time.FixedZone("WIB", 7*60*60)
-	return t.In(location).Format(ISOLayoutWithMillisAndTimezone)
+	return t.In(WIB).Format(ISOLayoutWithMillisAndTimezone)
 }
 
 func FormatISO7ToTime(value string) (time.Time, error) {
 	return time.Parse(ISOLayoutWithMillisAndTimezone, value)
 }
+
+// ParseDateTime accept RFC3339 from merchant or ISO7 layout used in our response
+func ParseDateTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return FormatISO7ToTime(value)
+}
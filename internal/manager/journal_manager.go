@@ -0,0 +1,172 @@
+package manager
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/amounthelper"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrUnbalancedJournal = errors.New("journal debit and credit are not balanced")
+
+type JournalManager interface {
+	PostTransferJournal(ctx context.Context, transfer *entity.Transaction, feeSetting entity.FeeSettings) error
+	PostDepositJournal(ctx context.Context, deposit *entity.Deposit) error
+	PostReversalJournal(ctx context.Context, originalId, transactionId int64, reference, journalType string) error
+	PostPrincipalReversalJournal(ctx context.Context, reversal *entity.Transaction) error
+	ChargedAmount(ctx context.Context, transactionId int64) (float64, error)
+}
+
+type journalManager struct {
+	journalRepo repository.JournalRepository
+}
+
+func NewJournalManager(journalRepo repository.JournalRepository) JournalManager {
+	return &journalManager{journalRepo}
+}
+
+// PostTransferJournal book merchant debit against settlement, partner fee, company revenue and tax.
+// Fee components that do not add up to total charge leave difference in fee rounding account instead of failing transfer
+func (jm *journalManager) PostTransferJournal(ctx context.Context, transfer *entity.Transaction, feeSetting entity.FeeSettings) error {
+	lines := []entity.JournalLine{
+		jm.debit(constants.AccountMerchantBalance, transfer.MerchantCode, transfer.Amount+feeSetting.TotalCharge),
+		jm.credit(constants.AccountSettlement, "", transfer.Amount),
+		jm.credit(constants.AccountPartnerPayable, "", feeSetting.FeePartner+feeSetting.AdditionalFee),
+		jm.credit(constants.AccountCompanyRevenue, "", feeSetting.FeeService),
+		jm.credit(constants.AccountTaxPayable, "", feeSetting.FeeTax),
+	}
+
+	difference := amounthelper.ToCent(feeSetting.TotalCharge) - amounthelper.ToCent(feeSetting.FeePartner+feeSetting.AdditionalFee) -
+		amounthelper.ToCent(feeSetting.FeeService) - amounthelper.ToCent(feeSetting.FeeTax)
+	switch {
+	case difference > 0:
+		lines = append(lines, jm.credit(constants.AccountFeeRounding, "", float64(difference)/100))
+	case difference < 0:
+		lines = append(lines, jm.debit(constants.AccountFeeRounding, "", float64(-difference)/100))
+	}
+
+	return jm.post(ctx, transfer.ID, transfer.PartnerReferenceNo, constants.JournalTransfer, fmt.Sprintf("Transfer %s", transfer.PartnerReferenceNo), lines)
+}
+
+// PostDepositJournal book incoming fund in settlement account as merchant balance
+func (jm *journalManager) PostDepositJournal(ctx context.Context, deposit *entity.Deposit) error {
+	lines := []entity.JournalLine{
+		jm.debit(constants.AccountSettlement, "", deposit.Amount),
+		jm.credit(constants.AccountMerchantBalance, deposit.MerchantCode, deposit.Amount),
+	}
+
+	return jm.post(ctx, deposit.ID, deposit.DepositReference, constants.JournalDeposit, fmt.Sprintf("Top up %s", deposit.DepositReference), lines)
+}
+
+// PostReversalJournal swap debit and credit of the original transfer journal
+func (jm *journalManager) PostReversalJournal(ctx context.Context, originalId, transactionId int64, reference, journalType string) error {
+	original, err := jm.journalRepo.FindByTransaction(ctx, originalId, constants.JournalTransfer)
+	if err != nil {
+		return err
+	}
+
+	if original == nil {
+		return fmt.Errorf("transfer journal for transaction id %d: %w", originalId, repository.ErrRecordNotFound)
+	}
+
+	lines := make([]entity.JournalLine, 0, len(original.Lines))
+	for _, line := range original.Lines {
+		lines = append(lines, entity.JournalLine{
+			AccountCode:  line.AccountCode,
+			MerchantCode: line.MerchantCode,
+			Debit:        line.Credit,
+			Credit:       line.Debit,
+		})
+	}
+
+	return jm.post(ctx, transactionId, reference, journalType, fmt.Sprintf("Reversal of %s", original.Reference), lines)
+}
+
+// PostPrincipalReversalJournal return transfer principal from settlement, fee stays booked
+func (jm *journalManager) PostPrincipalReversalJournal(ctx context.Context, reversal *entity.Transaction) error {
+	lines := []entity.JournalLine{
+		jm.debit(constants.AccountSettlement, "", reversal.Amount),
+		jm.credit(constants.AccountMerchantBalance, reversal.MerchantCode, reversal.Amount),
+	}
+
+	return jm.post(ctx, reversal.ID, reversal.PartnerReferenceNo, constants.JournalReversal, fmt.Sprintf("Reversal principal of %s", reversal.PartnerReferenceNo), lines)
+}
+
+// ChargedAmount return principal and fee merchant was debited by transfer journal, as booked
+func (jm *journalManager) ChargedAmount(ctx context.Context, transactionId int64) (float64, error) {
+	journal, err := jm.journalRepo.FindByTransaction(ctx, transactionId, constants.JournalTransfer)
+	if err != nil {
+		return 0, err
+	}
+
+	if journal == nil {
+		return 0, fmt.Errorf("transfer journal for transaction id %d: %w", transactionId, repository.ErrRecordNotFound)
+	}
+
+	var charged int64
+	for _, line := range journal.Lines {
+		if line.AccountCode == constants.AccountMerchantBalance {
+			charged += amounthelper.ToCent(line.Debit) - amounthelper.ToCent(line.Credit)
+		}
+	}
+	return float64(charged) / 100, nil
+}
+
+func (jm *journalManager) post(ctx context.Context, transactionId int64, reference, journalType, description string, lines []entity.JournalLine) error {
+	now := time.Now()
+	entries := make([]entity.JournalLine, 0, len(lines))
+	for _, line := range lines {
+		// skip empty fee component
+		if line.Debit == 0 && line.Credit == 0 {
+			continue
+		}
+		line.CreatedAt = now
+		entries = append(entries, line)
+	}
+
+	if err := validateJournal(entries); err != nil {
+		return fmt.Errorf("journal %s %s: %w", journalType, reference, err)
+	}
+
+	journal := &entity.Journal{
+		TransactionId: transactionId,
+		Reference:     reference,
+		JournalType:   journalType,
+		Description:   description,
+		CreatedAt:     now,
+		Lines:         entries,
+	}
+	return jm.journalRepo.Save(ctx, journal)
+}
+
+func (jm *journalManager) debit(accountCode, merchantCode string, amount float64) entity.JournalLine {
+	return entity.JournalLine{AccountCode: accountCode, MerchantCode: merchantCode, Debit: amount}
+}
+
+func (jm *journalManager) credit(accountCode, merchantCode string, amount float64) entity.JournalLine {
+	return entity.JournalLine{AccountCode: accountCode, MerchantCode: merchantCode, Credit: amount}
+}
+
+// validateJournal enforce every journal sums to zero, in cent precision
+func validateJournal(lines []entity.JournalLine) error {
+	if len(lines) < 2 {
+		return fmt.Errorf("journal need at least two lines")
+	}
+
+	var total int64
+	for _, line := range lines {
+		if line.Debit < 0 || line.Credit < 0 {
+			return fmt.Errorf("negative amount on account %s", line.AccountCode)
+		}
+		total += amounthelper.ToCent(line.Debit) - amounthelper.ToCent(line.Credit)
+	}
+
+	if total != 0 {
+		return fmt.Errorf("%w, difference %.2f", ErrUnbalancedJournal, float64(total)/100)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package manager
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/amounthelper"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+// stubJournalRepository keep posted journals in memory, methods not used by journal manager are left unimplemented
+type stubJournalRepository struct {
+	repository.JournalRepository
+	journals []entity.Journal
+}
+
+func (s *stubJournalRepository) Save(ctx context.Context, journal *entity.Journal) error {
+	s.journals = append(s.journals, *journal)
+	return nil
+}
+
+func (s *stubJournalRepository) FindByTransaction(ctx context.Context, transactionId int64, journalType string) (*entity.Journal, error) {
+	for i := range s.journals {
+		if s.journals[i].TransactionId == transactionId && s.journals[i].JournalType == journalType {
+			return &s.journals[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// assertBalanced fail when debit and credit of journal differ by any cent
+func assertBalanced(t *testing.T, journal entity.Journal) {
+	t.Helper()
+	var debit, credit int64
+	for _, line := range journal.Lines {
+		debit += amounthelper.ToCent(line.Debit)
+		credit += amounthelper.ToCent(line.Credit)
+	}
+	if debit != credit {
+		t.Errorf("%s journal debit %d cent, credit %d cent", journal.JournalType, debit, credit)
+	}
+}
+
+// accountCents sum debit minus credit of account over journals in cent
+func accountCents(journals []entity.Journal, accountCode string) int64 {
+	var total int64
+	for _, journal := range journals {
+		for _, line := range journal.Lines {
+			if line.AccountCode == accountCode {
+				total += amounthelper.ToCent(line.Debit) - amounthelper.ToCent(line.Credit)
+			}
+		}
+	}
+	return total
+}
+
+func TestPostTransferJournalIsBalanced(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		fee      entity.FeeSettings
+		rounding int64
+	}{
+		{"fee split adds up", 150000, entity.FeeSettings{FeePartner: 2500, FeeService: 1500, FeeTax: 165, TotalCharge: 4165}, 0},
+		{"additional fee goes to partner", 250000.50, entity.FeeSettings{FeePartner: 2500, AdditionalFee: 1000, FeeService: 1500, FeeTax: 165, TotalCharge: 5165}, 0},
+		{"split short of total charge", 100000, entity.FeeSettings{FeePartner: 3333.33, FeeService: 3333.33, FeeTax: 3333.33, TotalCharge: 10000}, -1},
+		{"split over total charge", 100000, entity.FeeSettings{FeePartner: 1666.67, FeeService: 1666.67, FeeTax: 1666.67, TotalCharge: 5000}, 1},
+		{"float noise in split", 0.3, entity.FeeSettings{FeePartner: 0.1, FeeService: 0.2, FeeTax: 0.0, TotalCharge: 0.1 + 0.2}, 0},
+		{"no fee", 75000, entity.FeeSettings{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubJournalRepository{}
+			jm := NewJournalManager(repo)
+			transfer := &entity.Transaction{ID: 1, MerchantCode: "MRC001", PartnerReferenceNo: "MRC-0001", Amount: tt.amount}
+
+			if err := jm.PostTransferJournal(context.Background(), transfer, tt.fee); err != nil {
+				t.Fatalf("PostTransferJournal() error: %v", err)
+			}
+			assertBalanced(t, repo.journals[0])
+
+			// fee rounding is debited when split exceeds total charge and credited when it falls short
+			if got := accountCents(repo.journals, constants.AccountFeeRounding); got != tt.rounding {
+				t.Errorf("fee rounding = %d cent, want %d", got, tt.rounding)
+			}
+			if got, want := accountCents(repo.journals, constants.AccountMerchantBalance), amounthelper.ToCent(tt.amount+tt.fee.TotalCharge); got != want {
+				t.Errorf("merchant debited %d cent, want %d", got, want)
+			}
+			for _, line := range repo.journals[0].Lines {
+				if line.Debit == 0 && line.Credit == 0 {
+					t.Errorf("empty line on account %s is posted", line.AccountCode)
+				}
+			}
+		})
+	}
+}
+
+func TestPostRefundAndReversalJournalUndoTransfer(t *testing.T) {
+	fee := entity.FeeSettings{FeePartner: 1666.67, FeeService: 1666.67, FeeTax: 1666.67, TotalCharge: 5000}
+
+	for _, journalType := range []string{constants.JournalRefund, constants.JournalReversal} {
+		t.Run(journalType, func(t *testing.T) {
+			repo := &stubJournalRepository{}
+			jm := NewJournalManager(repo)
+			transfer := &entity.Transaction{ID: 1, MerchantCode: "MRC001", PartnerReferenceNo: "MRC-0001", Amount: 100000.10}
+
+			if err := jm.PostTransferJournal(context.Background(), transfer, fee); err != nil {
+				t.Fatalf("PostTransferJournal() error: %v", err)
+			}
+			if err := jm.PostReversalJournal(context.Background(), transfer.ID, 2, "MRC-0001-R", journalType); err != nil {
+				t.Fatalf("PostReversalJournal() error: %v", err)
+			}
+
+			if len(repo.journals) != 2 || repo.journals[1].JournalType != journalType {
+				t.Fatalf("posted %d journals, want transfer and %s", len(repo.journals), journalType)
+			}
+			assertBalanced(t, repo.journals[1])
+
+			// every account including fee rounding is back to zero
+			for _, account := range []string{constants.AccountMerchantBalance, constants.AccountSettlement, constants.AccountPartnerPayable,
+				constants.AccountCompanyRevenue, constants.AccountTaxPayable, constants.AccountFeeRounding} {
+				if got := accountCents(repo.journals, account); got != 0 {
+					t.Errorf("account %s = %d cent after %s, want 0", account, got, journalType)
+				}
+			}
+		})
+	}
+}
+
+func TestPostPrincipalReversalJournalKeepsFee(t *testing.T) {
+	repo := &stubJournalRepository{}
+	jm := NewJournalManager(repo)
+	fee := entity.FeeSettings{FeePartner: 2500, FeeService: 1500, FeeTax: 165, TotalCharge: 4165}
+	transfer := &entity.Transaction{ID: 1, MerchantCode: "MRC001", PartnerReferenceNo: "MRC-0001", Amount: 150000.55}
+
+	if err := jm.PostTransferJournal(context.Background(), transfer, fee); err != nil {
+		t.Fatalf("PostTransferJournal() error: %v", err)
+	}
+	reversal := &entity.Transaction{ID: 2, MerchantCode: "MRC001", PartnerReferenceNo: "MRC-0001-R", Amount: 150000.55}
+	if err := jm.PostPrincipalReversalJournal(context.Background(), reversal); err != nil {
+		t.Fatalf("PostPrincipalReversalJournal() error: %v", err)
+	}
+	assertBalanced(t, repo.journals[1])
+
+	if got := accountCents(repo.journals, constants.AccountSettlement); got != 0 {
+		t.Errorf("settlement = %d cent, want 0", got)
+	}
+	if got := accountCents(repo.journals, constants.AccountMerchantBalance); got != amounthelper.ToCent(fee.TotalCharge) {
+		t.Errorf("merchant charged %d cent after principal reversal, want fee %d", got, amounthelper.ToCent(fee.TotalCharge))
+	}
+
+	charged, err := jm.ChargedAmount(context.Background(), transfer.ID)
+	if err != nil || amounthelper.ToCent(charged) != amounthelper.ToCent(transfer.Amount+fee.TotalCharge) {
+		t.Errorf("ChargedAmount() = %.2f, %v, want %.2f", charged, err, transfer.Amount+fee.TotalCharge)
+	}
+}
+
+func TestPostReversalJournalWithoutTransferJournal(t *testing.T) {
+	jm := NewJournalManager(&stubJournalRepository{})
+	err := jm.PostReversalJournal(context.Background(), 1, 2, "MRC-0001-R", constants.JournalReversal)
+	if !errors.Is(err, repository.ErrRecordNotFound) {
+		t.Errorf("PostReversalJournal() error = %v, want %v", err, repository.ErrRecordNotFound)
+	}
+}
+
+func TestValidateJournal(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []entity.JournalLine
+		valid bool
+	}{
+		{"balanced", []entity.JournalLine{{AccountCode: "A", Debit: 100.10}, {AccountCode: "B", Credit: 100.05}, {AccountCode: "C", Credit: 0.05}}, true},
+		{"balanced in cent with float noise", []entity.JournalLine{{AccountCode: "A", Debit: 0.1 + 0.2}, {AccountCode: "B", Credit: 0.3}}, true},
+		{"off by one cent", []entity.JournalLine{{AccountCode: "A", Debit: 100.01}, {AccountCode: "B", Credit: 100}}, false},
+		{"single line", []entity.JournalLine{{AccountCode: "A", Debit: 0}}, false},
+		{"negative amount", []entity.JournalLine{{AccountCode: "A", Debit: -10}, {AccountCode: "B", Credit: -10}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateJournal(tt.lines); (err == nil) != tt.valid {
+				t.Errorf("validateJournal() error = %v, want valid %t", err, tt.valid)
+			}
+		})
+	}
+}
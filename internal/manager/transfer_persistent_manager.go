@@ -13,13 +13,16 @@ import (
 type AccountStatementManager interface {
 	CreateRecipient(ctx context.Context, request dto.TransferRequest) (*entity.DataRecipient, error)
 	CreateTransfer(ctx context.Context, recipient *entity.DataRecipient, request dto.TransferRequest, adminFee entity.FeeSettings, partnerId, referenceNumber string, amountTransfer float64) (*entity.Transaction, error)
-	FindTransferByPartnerReference(ctx context.Context, partnerReferenceNo string) (*entity.TransferTemp, error)
+	CreateClearingDetail(ctx context.Context, transferId int64, request dto.TransferRequest) error
+	FindTransferByPartnerReference(ctx context.Context, merchantCode, partnerReferenceNo string) (*entity.Transaction, error)
+	CreateReversal(ctx context.Context, original *entity.Transaction, reversalReference, reason string, amount float64) (*entity.Transaction, error)
 	DebitMerchant(ctx context.Context, merchantCode string, totalAmount float64) (float64, error)
 	CreditMerchant(ctx context.Context, merchantCode string, amount float64) (float64, error)
 	CreateTransferLedger(ctx context.Context, transferId int64, request dto.TransferRequest, balance float64, merchantCode string, amountTransfer float64) error
 	CreateAdminFeeLedger(ctx context.Context, transferId int64, request dto.TransferRequest, balance float64, feeSetting entity.FeeSettings, merchantCode string) error
 	CreateRefundLedger(ctx context.Context, transferId int64, request dto.TransferRequest, refundAmount, balance float64, merchantCode string) error
-	UpdateTransferStatus(ctx context.Context, transferId int64, status string) error
+	CreateDepositLedger(ctx context.Context, deposit *entity.Deposit, balance float64) error
+	CreateReversalLedger(ctx context.Context, reversal *entity.Transaction, balance float64) error
 }
 
 type transferPersistenceService struct {
@@ -61,7 +64,7 @@ func (tp *transferPersistenceService) CreateTransfer(ctx context.Context, recipi
 		Remark:                  request.AdditionalInfo.Remarks,
 		TransactionType:         request.AdditionalInfo.Channel,
 		TransactionDate:         time.Now(),
-		Status:                  constants.ResponseMap[constants.PendingTransfer],
+		Status:                  constants.StatusCreated,
 		IsReversal:              false,
 		IsReconcile:             false,
 		ReconcileDate:           nil,
@@ -79,16 +82,71 @@ func (tp *transferPersistenceService) CreateTransfer(ctx context.Context, recipi
 	return transfer, nil
 }
 
-func (tp *transferPersistenceService) FindTransferByPartnerReference(ctx context.Context, partnerReferenceNo string) (*entity.TransferTemp, error) {
-	transfer, err := tp.transferRepo.FindByRefNo(ctx, partnerReferenceNo)
-	if err != nil {
+// data clearing, transfer without clearing info has nothing to keep
+func (tp *transferPersistenceService) CreateClearingDetail(ctx context.Context, transferId int64, request dto.TransferRequest) error {
+	clearing := request.ClearingInfo
+	if clearing == nil {
+		return nil
+	}
+
+	address := make([]string, 3)
+	copy(address, clearing.BeneficiaryAddress)
+	return tp.transferRepo.SaveClearingDetail(ctx, &entity.TransferClearingDetail{
+		TransactionId:             transferId,
+		Channel:                   request.AdditionalInfo.Channel,
+		ResidentStatus:            clearing.ResidentStatus,
+		BeneficiaryType:           clearing.BeneficiaryType,
+		BeneficiaryBankBranchName: clearing.BeneficiaryBankBranchName,
+		BeneficiaryBankCity:       clearing.BeneficiaryBankCity,
+		BeneficiaryBankAddress:    clearing.BeneficiaryBankAddress,
+		BeneficiaryAddress1:       address[0],
+		BeneficiaryAddress2:       address[1],
+		BeneficiaryAddress3:       address[2],
+		DatiII:                    clearing.DatiII,
+		CreatedAt:                 time.Now(),
+	})
+}
+
+func (tp *transferPersistenceService) CreateReversal(ctx context.Context, original *entity.Transaction, reversalReference, reason string, amount float64) (*entity.Transaction, error) {
+	// reversal gets own system reference, only one reversal may exist per transfer
+	systemReference := "REV-" + original.PartnerReferenceNo
+	if original.SystemReferenceNo != nil {
+		systemReference = "REV-" + *original.SystemReferenceNo
+	}
+
+	reversal := &entity.Transaction{
+		Sender:                original.Sender,
+		Recipient:             original.Recipient,
+		MerchantCode:          original.MerchantCode,
+		PartnerReferenceNo:    reversalReference,
+		BankReferenceNo:       original.BankReferenceNo,
+		SystemReferenceNo:     &systemReference,
+		Amount:                amount,
+		Currency:              original.Currency,
+		Remark:                reason,
+		TransactionType:       original.TransactionType,
+		TransactionDate:       time.Now(),
+		Status:                constants.StatusDone,
+		IsReversal:            true,
+		OriginalTransactionId: &original.ID,
+		IsReconcile:           false,
+		ReconcileDate:         nil,
+	}
+
+	if err := tp.transferRepo.Save(ctx, reversal); err != nil {
 		return nil, err
 	}
-	return transfer, nil
+
+	return reversal, nil
 }
 
-func (tp *transferPersistenceService) UpdateTransferStatus(ctx context.Context, id int64, status string) error {
-	return tp.transferRepo.Update(ctx, id, status)
+// FindTransferByPartnerReference lock transfer row of merchant until transaction ends
+func (tp *transferPersistenceService) FindTransferByPartnerReference(ctx context.Context, merchantCode, partnerReferenceNo string) (*entity.Transaction, error) {
+	transfer, err := tp.transferRepo.FindForUpdate(ctx, merchantCode, partnerReferenceNo)
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
 }
 
 // data merchant account
@@ -128,6 +186,41 @@ func (tp *transferPersistenceService) CreateRefundLedger(ctx context.Context, tr
 	return tp.ledgerRepo.Save(ctx, statement)
 }
 
+func (tp *transferPersistenceService) CreateDepositLedger(ctx context.Context, deposit *entity.Deposit, balance float64) error {
+	description := deposit.Description
+	if description == "" {
+		description = fmt.Sprintf("Top up %s: %s", deposit.Source, deposit.SourceReference)
+	}
+
+	statement := &entity.AccountStatement{
+		TransactionId:       deposit.ID,
+		TransctionReference: deposit.DepositReference,
+		MerchantCode:        deposit.MerchantCode,
+		Status:              constants.StatusCredit,
+		Channel:             constants.ChannelDeposit,
+		Description:         description,
+		Amount:              deposit.Amount,
+		BalanceAfter:        balance,
+		CreatedAt:           time.Now(),
+	}
+	return tp.ledgerRepo.Save(ctx, statement)
+}
+
+func (tp *transferPersistenceService) CreateReversalLedger(ctx context.Context, reversal *entity.Transaction, balance float64) error {
+	statement := &entity.AccountStatement{
+		TransactionId:       reversal.ID,
+		TransctionReference: reversal.PartnerReferenceNo,
+		MerchantCode:        reversal.MerchantCode,
+		Status:              constants.StatusCredit,
+		Channel:             reversal.TransactionType,
+		Description:         fmt.Sprintf("Reversal: %s", reversal.Remark),
+		Amount:              reversal.Amount,
+		BalanceAfter:        balance,
+		CreatedAt:           time.Now(),
+	}
+	return tp.ledgerRepo.Save(ctx, statement)
+}
+
 func (tp *transferPersistenceService) buildStatement(transferId int64, requestDto dto.TransferRequest, balance, amount float64, description, status, merchantCode string) *entity.AccountStatement {
 	return &entity.AccountStatement{
 		TransactionId:       transferId,
@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidTransition = errors.New("invalid transfer status transition")
+	ErrStaleTransfer     = errors.New("transfer was modified concurrently")
+)
+
+// transferTransitions is the only place allowed status moves are defined,
+// pending goes back to created only when dead letter replay fails to send the instruction it claimed
+var transferTransitions = map[string][]string{
+	constants.StatusCreated:    {constants.StatusPending, constants.StatusFailedPublish},
+	constants.StatusPending:    {constants.StatusCreated, constants.StatusInProgress, constants.StatusDone, constants.StatusRejected, constants.StatusTimeout, constants.StatusRefunded},
+	constants.StatusInProgress: {constants.StatusDone, constants.StatusRejected, constants.StatusTimeout},
+	constants.StatusTimeout:    {constants.StatusDone, constants.StatusRejected, constants.StatusRefunded},
+	constants.StatusRejected:   {constants.StatusRefunded},
+	constants.StatusDone:       {constants.StatusReversed},
+}
+
+// CanTransition reports whether transfer in status from may move to status to
+func CanTransition(from, to string) bool {
+	return slices.Contains(transferTransitions[from], to)
+}
+
+type TransferStateManager interface {
+	Init(ctx context.Context, transfer *entity.Transaction, reason, actor string) error
+	Transition(ctx context.Context, transfer *entity.Transaction, to, reason, actor string) error
+}
+
+type transferStateManager struct {
+	transferRepo repository.TransferRepository
+}
+
+// NewTransferStateManager must be given transaction scoped repository, status and history are written together
+func NewTransferStateManager(transferRepo repository.TransferRepository) TransferStateManager {
+	return &transferStateManager{transferRepo}
+}
+
+// Init record initial status of newly saved transfer
+func (sm *transferStateManager) Init(ctx context.Context, transfer *entity.Transaction, reason, actor string) error {
+	return sm.saveHistory(ctx, transfer.ID, "", transfer.Status, reason, actor)
+}
+
+// Transition validate and apply status change, transfer version must match the stored row
+func (sm *transferStateManager) Transition(ctx context.Context, transfer *entity.Transaction, to, reason, actor string) error {
+	from := transfer.Status
+	if !CanTransition(from, to) {
+		return fmt.Errorf("transfer %s from %s to %s: %w", transfer.PartnerReferenceNo, from, to, ErrInvalidTransition)
+	}
+
+	updated, err := sm.transferRepo.UpdateStatus(ctx, transfer.ID, transfer.Version, to)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return fmt.Errorf("transfer %s version %d: %w", transfer.PartnerReferenceNo, transfer.Version, ErrStaleTransfer)
+	}
+
+	transfer.Status = to
+	transfer.Version++
+	return sm.saveHistory(ctx, transfer.ID, from, to, reason, actor)
+}
+
+func (sm *transferStateManager) saveHistory(ctx context.Context, transactionId int64, from, to, reason, actor string) error {
+	return sm.transferRepo.SaveStatusHistory(ctx, &entity.TransferStatusHistory{
+		TransactionId: transactionId,
+		FromStatus:    from,
+		ToStatus:      to,
+		Reason:        reason,
+		Actor:         actor,
+		CreatedAt:     time.Now(),
+	})
+}
@@ -0,0 +1,137 @@
+package manager
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+var transferStatuses = []string{
+	constants.StatusCreated, constants.StatusFailedPublish, constants.StatusPending, constants.StatusInProgress, constants.StatusDone,
+	constants.StatusRejected, constants.StatusTimeout, constants.StatusRefunded, constants.StatusReversed,
+}
+
+func TestCanTransition(t *testing.T) {
+	allowed := map[[2]string]bool{
+		{constants.StatusCreated, constants.StatusPending}:       true,
+		{constants.StatusCreated, constants.StatusFailedPublish}: true,
+		{constants.StatusPending, constants.StatusCreated}:       true,
+		{constants.StatusPending, constants.StatusInProgress}:    true,
+		{constants.StatusPending, constants.StatusDone}:          true,
+		{constants.StatusPending, constants.StatusRejected}:      true,
+		{constants.StatusPending, constants.StatusTimeout}:       true,
+		{constants.StatusPending, constants.StatusRefunded}:      true,
+		{constants.StatusInProgress, constants.StatusDone}:       true,
+		{constants.StatusInProgress, constants.StatusRejected}:   true,
+		{constants.StatusInProgress, constants.StatusTimeout}:    true,
+		{constants.StatusTimeout, constants.StatusDone}:          true,
+		{constants.StatusTimeout, constants.StatusRejected}:      true,
+		{constants.StatusTimeout, constants.StatusRefunded}:      true,
+		{constants.StatusRejected, constants.StatusRefunded}:     true,
+		{constants.StatusDone, constants.StatusReversed}:         true,
+	}
+
+	// every pair of status including staying in the same status, terminal status allow no move at all
+	for _, from := range transferStatuses {
+		for _, to := range transferStatuses {
+			want := allowed[[2]string{from, to}]
+			if got := CanTransition(from, to); got != want {
+				t.Errorf("CanTransition(%s, %s) = %t, want %t", from, to, got, want)
+			}
+		}
+	}
+
+	for _, terminal := range []string{constants.StatusFailedPublish, constants.StatusRefunded, constants.StatusReversed} {
+		if len(transferTransitions[terminal]) != 0 {
+			t.Errorf("terminal status %s allows %v", terminal, transferTransitions[terminal])
+		}
+	}
+	if CanTransition("", constants.StatusCreated) || CanTransition(constants.StatusCreated, "UNKNOWN") {
+		t.Error("unknown status must not transition")
+	}
+}
+
+// stubTransferRepository apply status update when version matches and keep history, methods not used by state manager are left unimplemented
+type stubTransferRepository struct {
+	repository.TransferRepository
+	version   int64
+	updateErr error
+	updates   int
+	histories []entity.TransferStatusHistory
+}
+
+func (s *stubTransferRepository) UpdateStatus(ctx context.Context, id, version int64, status string) (bool, error) {
+	if s.updateErr != nil {
+		return false, s.updateErr
+	}
+	if version != s.version {
+		return false, nil
+	}
+	s.version++
+	s.updates++
+	return true, nil
+}
+
+func (s *stubTransferRepository) SaveStatusHistory(ctx context.Context, history *entity.TransferStatusHistory) error {
+	s.histories = append(s.histories, *history)
+	return nil
+}
+
+func TestTransition(t *testing.T) {
+	repo := &stubTransferRepository{version: 3}
+	sm := NewTransferStateManager(repo)
+	transfer := &entity.Transaction{ID: 7, PartnerReferenceNo: "MRC-0001", Status: constants.StatusPending, Version: 3}
+
+	if err := sm.Transition(context.Background(), transfer, constants.StatusDone, "reported by partner bank", constants.ActorPartnerStatus); err != nil {
+		t.Fatalf("Transition() error: %v", err)
+	}
+	if transfer.Status != constants.StatusDone || transfer.Version != 4 {
+		t.Errorf("transfer is %s version %d, want %s version 4", transfer.Status, transfer.Version, constants.StatusDone)
+	}
+
+	if len(repo.histories) != 1 {
+		t.Fatalf("saved %d history, want 1", len(repo.histories))
+	}
+	history := repo.histories[0]
+	if history.TransactionId != 7 || history.FromStatus != constants.StatusPending || history.ToStatus != constants.StatusDone || history.Actor != constants.ActorPartnerStatus {
+		t.Errorf("history = %+v, want pending to done by partner status", history)
+	}
+}
+
+func TestTransitionRejected(t *testing.T) {
+	errDatabase := errors.New("connection refused")
+	tests := []struct {
+		name    string
+		repo    *stubTransferRepository
+		status  string
+		version int64
+		to      string
+		wantErr error
+	}{
+		{"forbidden move", &stubTransferRepository{version: 1}, constants.StatusRefunded, 1, constants.StatusDone, ErrInvalidTransition},
+		{"out of terminal failed publish", &stubTransferRepository{version: 1}, constants.StatusFailedPublish, 1, constants.StatusPending, ErrInvalidTransition},
+		{"stale version", &stubTransferRepository{version: 2}, constants.StatusPending, 1, constants.StatusDone, ErrStaleTransfer},
+		{"database error", &stubTransferRepository{version: 1, updateErr: errDatabase}, constants.StatusPending, 1, constants.StatusDone, errDatabase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := NewTransferStateManager(tt.repo)
+			transfer := &entity.Transaction{ID: 7, PartnerReferenceNo: "MRC-0001", Status: tt.status, Version: tt.version}
+
+			err := sm.Transition(context.Background(), transfer, tt.to, "reported by partner bank", constants.ActorPartnerStatus)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Transition() error = %v, want %v", err, tt.wantErr)
+			}
+			if transfer.Status != tt.status || transfer.Version != tt.version {
+				t.Errorf("transfer changed to %s version %d on failed transition", transfer.Status, transfer.Version)
+			}
+			if tt.repo.updates != 0 || len(tt.repo.histories) != 0 {
+				t.Errorf("failed transition wrote %d update and %d history", tt.repo.updates, len(tt.repo.histories))
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+type WebhookManager interface {
+	EnqueueStatusChange(ctx context.Context, transfer *entity.Transaction, previousStatus string, event dto.TransferStatusEvent) error
+}
+
+type webhookManager struct {
+	webhookRepo repository.WebhookRepository
+}
+
+func NewWebhookManager(webhookRepo repository.WebhookRepository) WebhookManager {
+	return &webhookManager{webhookRepo}
+}
+
+// EnqueueStatusChange store delivery in the same transaction as status update, merchant without active endpoint is skipped
+func (wm *webhookManager) EnqueueStatusChange(ctx context.Context, transfer *entity.Transaction, previousStatus string, event dto.TransferStatusEvent) error {
+	endpoint, err := wm.webhookRepo.FindEndpoint(ctx, transfer.MerchantCode)
+	if err != nil {
+		return err
+	}
+
+	if endpoint == nil || !endpoint.IsActive {
+		return nil
+	}
+
+	now := time.Now()
+	payload := dto.WebhookPayload{
+		Event:              constants.EventTransferStatusChanged,
+		MerchantCode:       transfer.MerchantCode,
+		PartnerReferenceNo: transfer.PartnerReferenceNo,
+		BankReferenceNo:    event.BankReferenceNo,
+		PreviousStatus:     previousStatus,
+		Status:             event.Status,
+		Amount:             strconv.FormatFloat(transfer.Amount, 'f', 2, 64),
+		Reason:             event.Reason,
+		OccurredAt:         timehelper.FormatTimeToISO7(now),
+	}
+	if transfer.SystemReferenceNo != nil {
+		payload.ReferenceNumber = *transfer.SystemReferenceNo
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return wm.webhookRepo.SaveDelivery(ctx, &entity.WebhookDelivery{
+		MerchantCode:       transfer.MerchantCode,
+		EventType:          constants.EventTransferStatusChanged,
+		PartnerReferenceNo: transfer.PartnerReferenceNo,
+		Payload:            string(body),
+		Status:             constants.WebhookPending,
+		NextAttemptAt:      now,
+		CreatedAt:          now,
+		LastUpdated:        now,
+	})
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"briefcash-transfer/internal/constants"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const adminIdKey = "admin_id"
+
+// AdminAuthMiddleware identify admin by personal api key in X-ADMIN-KEY header, keys map api key to admin id
+func AdminAuthMiddleware(keys map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		presented := []byte(c.GetHeader("X-ADMIN-KEY"))
+
+		// every key is compared so response time does not reveal which key was close
+		adminId := ""
+		for key, id := range keys {
+			if subtle.ConstantTimeCompare(presented, []byte(key)) == 1 {
+				adminId = id
+			}
+		}
+
+		if adminId == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"responseCode":    constants.ErrUnauthorized,
+				"responseMessage": constants.ResponseMap[constants.ErrUnauthorized],
+			})
+			return
+		}
+		c.Set(adminIdKey, adminId)
+		c.Next()
+	}
+}
+
+// AdminId return admin authenticated by AdminAuthMiddleware, empty outside admin route
+func AdminId(c *gin.Context) string {
+	return c.GetString(adminIdKey)
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"briefcash-transfer/internal/constants"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InternalAuthMiddleware guard internal endpoint with shared api key in X-INTERNAL-KEY header
+func InternalAuthMiddleware(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-INTERNAL-KEY")
+		if apiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"responseCode":    constants.ErrUnauthorized,
+				"responseMessage": constants.ResponseMap[constants.ErrUnauthorized],
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"briefcash-transfer/internal/helper/metrichelper"
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const metricsBodyLimit = 4096
+
+// MetricsMiddleware record request count and latency, response code and channel are read from json response body
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer, limit: metricsBodyLimit}
+		c.Writer = writer
+
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = "unmatched"
+		}
+		responseCode, channel := writer.responseLabels()
+
+		metrichelper.RequestTotal.WithLabelValues(c.Request.Method, path, strconv.Itoa(c.Writer.Status()), responseCode, channel).Inc()
+		metrichelper.RequestDuration.WithLabelValues(c.Request.Method, path, responseCode, channel).Observe(time.Since(start).Seconds())
+	}
+}
+
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body  bytes.Buffer
+	limit int
+}
+
+func (w *bodyCaptureWriter) Write(data []byte) (int, error) {
+	if remaining := w.limit - w.body.Len(); remaining > 0 {
+		w.body.Write(data[:min(len(data), remaining)])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyCaptureWriter) responseLabels() (string, string) {
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		return "", ""
+	}
+
+	var response struct {
+		ResponseCode   string            `json:"responseCode"`
+		AdditionalInfo map[string]string `json:"additionalInfo"`
+	}
+	if err := json.Unmarshal(w.body.Bytes(), &response); err != nil {
+		return "", ""
+	}
+	return response.ResponseCode, response.AdditionalInfo["channel"]
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"briefcash-transfer/internal/helper/correlationhelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// responseCaptureLimit is larger than body log limit so the response can still be parsed and masked before truncated
+const responseCaptureLimit = 64 * 1024
+
+type RequestLogConfig struct {
+	LogBody   bool
+	BodyLimit int
+}
+
+// RequestLoggerMiddleware write access log after handler completes, request carries correlation id and logger in its context
+func RequestLoggerMiddleware(cfg RequestLogConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		externalId := c.GetHeader(correlationhelper.HeaderExternalId)
+		if externalId == "" {
+			externalId = correlationhelper.New()
+			c.Request.Header.Set(correlationhelper.HeaderExternalId, externalId)
+		}
+
+		correlationId := c.GetHeader(correlationhelper.HeaderCorrelationId)
+		if correlationId == "" {
+			correlationId = externalId
+		}
+		c.Header(correlationhelper.HeaderExternalId, externalId)
+		c.Header(correlationhelper.HeaderCorrelationId, correlationId)
+
+		fields := logrus.Fields{"correlation_id": correlationId}
+		if merchantCode := c.GetHeader("X-PARTNER-ID"); merchantCode != "" {
+			fields["merchant_id"] = merchantCode
+		}
+		log := loghelper.Logger.WithFields(fields)
+		ctx := correlationhelper.NewContext(c.Request.Context(), correlationId)
+		c.Request = c.Request.WithContext(loghelper.NewContext(ctx, log))
+
+		var requestBody []byte
+		var writer *bodyCaptureWriter
+		if cfg.LogBody {
+			if c.Request.Body != nil && strings.HasPrefix(c.ContentType(), "application/json") {
+				requestBody, _ = io.ReadAll(c.Request.Body)
+				c.Request.Body = io.NopCloser(bytes.NewReader(requestBody))
+			}
+			writer = &bodyCaptureWriter{ResponseWriter: c.Writer, limit: responseCaptureLimit}
+			c.Writer = writer
+		}
+
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+
+		status := c.Writer.Status()
+		duration := time.Since(start)
+		entry := log.WithFields(logrus.Fields{
+			"method":        c.Request.Method,
+			"path":          path,
+			"status":        status,
+			"duration":      duration.String(),
+			"duration_ms":   duration.Milliseconds(),
+			"clientIp":      c.ClientIP(),
+			"user_agent":    c.Request.UserAgent(),
+			"request_size":  max(c.Request.ContentLength, 0),
+			"response_size": max(c.Writer.Size(), 0),
+		})
+
+		if cfg.LogBody {
+			entry = entry.WithFields(logrus.Fields{
+				"request_body":  maskedBody(requestBody, cfg.BodyLimit),
+				"response_body": maskedBody(writer.body.Bytes(), cfg.BodyLimit),
+			})
+		}
+
+		if len(c.Errors) > 0 {
+			entry = entry.WithField("errors", c.Errors.String())
+		}
+
+		switch {
+		case status >= 500:
+			entry.Error("Handled request")
+		case status >= 400:
+			entry.Warn("Handled request")
+		default:
+			entry.Info("Handled request")
+		}
+	}
+}
+
+// maskedBody mask json body before truncated, non json body is omitted because it can not be masked reliably
+func maskedBody(body []byte, limit int) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if !json.Valid(body) {
+		return fmt.Sprintf("[%d bytes non json body omitted]", len(body))
+	}
+
+	masked := loghelper.MaskJSON(body)
+	if len(masked) > limit {
+		return string(masked[:limit]) + "...(truncated)"
+	}
+	return string(masked)
+}
@@ -0,0 +1,286 @@
+package protobuf
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var update = flag.Bool("update", false, "rewrite binary fixtures with current output")
+
+// legacyTransferRequestFields is TransferRequest as shipped before clearing info and envelope, consumer on it is still deployed
+var legacyTransferRequestFields = []string{
+	"external_id", "partner_ref_no", "customer_number", "account_type", "beneficiary_account_no",
+	"beneficiary_bank_code", "amount", "transaction_date", "customer_reference", "channel", "remarks",
+	"email", "address", "citizenship", "transfer_purpose", "transfer_activity", "customer_type",
+}
+
+// frozen field number of every released field, a failing entry means a consumer in production would misread the message
+var frozenFields = map[protoreflect.FullName]map[protoreflect.Name]protoreflect.FieldNumber{
+	"protobuf.TransferRequest": {
+		"external_id": 1, "partner_ref_no": 2, "customer_number": 3, "account_type": 4, "beneficiary_account_no": 5,
+		"beneficiary_bank_code": 6, "amount": 7, "transaction_date": 8, "customer_reference": 9, "channel": 10,
+		"remarks": 11, "email": 12, "address": 13, "citizenship": 14, "transfer_purpose": 15,
+		"transfer_activity": 16, "customer_type": 17, "resident_status": 18, "beneficiary_type": 19,
+		"beneficiary_bank_branch_name": 20, "beneficiary_bank_city": 21, "beneficiary_bank_address": 22,
+		"beneficiary_address": 23, "dati_ii": 24, "currency": 25, "merchant_code": 26, "reference_number": 27,
+		"routing_code": 28, "envelope": 100,
+	},
+	"protobuf.TransferResult": {
+		"external_id": 1, "partner_ref_no": 2, "reference_number": 3, "bank_reference_no": 4, "status": 5,
+		"outcome": 6, "response_code": 7, "response_message": 8, "partner_response_code": 9,
+		"partner_response_message": 10, "amount": 11, "currency": 12, "routing_code": 13, "completed_at": 14,
+		"envelope": 100,
+	},
+	"protobuf.Envelope": {
+		"message_type": 1, "schema_version": 2, "message_id": 3, "trace_headers": 4, "created_at": 5,
+	},
+}
+
+// legacyTransferRequest build descriptor of schema version 1 TransferRequest, the way an old consumer sees the message
+func legacyTransferRequest(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	message := &descriptorpb.DescriptorProto{Name: proto.String("TransferRequest")}
+	for i, name := range legacyTransferRequestFields {
+		message.Field = append(message.Field, &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(int32(i + 1)),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		})
+	}
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:        proto.String("legacy/transfer_instruction.proto"),
+		Package:     proto.String("legacy"),
+		Syntax:      proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{message},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to build legacy descriptor: %v", err)
+	}
+	return file.Messages().Get(0)
+}
+
+func sampleTransferRequest() *TransferRequest {
+	return &TransferRequest{
+		ExternalId:                "EXT-20240501-0001",
+		PartnerRefNo:              "PRN-0001",
+		CustomerNumber:            "3171012345670001",
+		AccountType:               "SAVINGS",
+		BeneficiaryAccountNo:      "1234567890",
+		BeneficiaryBankCode:       "013",
+		Amount:                    "150000.00",
+		TransactionDate:           "2024-05-01T10:00:00+07:00",
+		CustomerReference:         "INV-0001",
+		Channel:                   "rtgs",
+		Remarks:                   "invoice payment",
+		Email:                     "finance@merchant.co.id",
+		Address:                   "Jl. Sudirman No. 1",
+		Citizenship:               "ID",
+		TransferPurpose:           "01",
+		TransferActivity:          "99",
+		CustomerType:              "01",
+		ResidentStatus:            "01",
+		BeneficiaryType:           "1",
+		BeneficiaryBankBranchName: "KCU Thamrin",
+		BeneficiaryBankCity:       "Jakarta",
+		BeneficiaryBankAddress:    "Jl. MH Thamrin No. 1",
+		BeneficiaryAddress:        []string{"Jl. Gatot Subroto No. 2", "Jakarta Selatan"},
+		DatiIi:                    "0391",
+		Currency:                  "IDR",
+		MerchantCode:              "MRC001",
+		ReferenceNumber:           "rtg-013-20240501100000000",
+		RoutingCode:               "013",
+		Envelope: &Envelope{
+			MessageType:   MessageTypeTransferRequest,
+			SchemaVersion: TransferRequestSchemaVersion,
+			MessageId:     "5f0c6d0e-7a43-4d8e-9a55-0d1c7f3f1a10",
+			TraceHeaders:  map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			CreatedAt:     "2024-05-01T10:00:00.0000000+07:00",
+		},
+	}
+}
+
+func sampleTransferResult() *TransferResult {
+	return &TransferResult{
+		ExternalId:             "EXT-20240501-0001",
+		PartnerRefNo:           "PRN-0001",
+		ReferenceNumber:        "rtg-013-20240501100000000",
+		BankReferenceNo:        "BANK-REF-0001",
+		Status:                 "DONE",
+		Outcome:                "SUCCESS",
+		ResponseCode:           "2004300",
+		ResponseMessage:        "Successful",
+		PartnerResponseCode:    "2001800",
+		PartnerResponseMessage: "Successful",
+		Amount:                 "150000.00",
+		Currency:               "IDR",
+		RoutingCode:            "013",
+		CompletedAt:            "2024-05-01T10:00:05.0000000+07:00",
+		Envelope: &Envelope{
+			MessageType:   MessageTypeTransferResult,
+			SchemaVersion: TransferResultSchemaVersion,
+			MessageId:     "8b1e7c52-2d7f-4a7b-8f0e-3c9b6f1d2e44",
+			CreatedAt:     "2024-05-01T10:00:05.0000000+07:00",
+		},
+	}
+}
+
+func marshal(t *testing.T, message proto.Message) []byte {
+	t.Helper()
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		t.Fatalf("failed to marshal %s: %v", message.ProtoReflect().Descriptor().FullName(), err)
+	}
+	return payload
+}
+
+func TestLegacyConsumerDecodesCurrentTransferRequest(t *testing.T) {
+	current := sampleTransferRequest()
+	legacy := dynamicpb.NewMessage(legacyTransferRequest(t))
+	if err := proto.Unmarshal(marshal(t, current), legacy); err != nil {
+		t.Fatalf("legacy consumer failed to decode current message: %v", err)
+	}
+
+	currentFields := current.ProtoReflect().Descriptor().Fields()
+	legacyFields := legacy.Descriptor().Fields()
+	for i := 0; i < legacyFields.Len(); i++ {
+		field := legacyFields.Get(i)
+		got := legacy.Get(field).String()
+		want := current.ProtoReflect().Get(currentFields.ByName(field.Name())).String()
+		if got != want {
+			t.Errorf("field %s = %q, want %q", field.Name(), got, want)
+		}
+	}
+
+	// newer fields travel as unknown fields, legacy consumer relaying the message keeps them
+	if len(legacy.GetUnknown()) == 0 {
+		t.Error("newer fields are dropped instead of kept as unknown fields")
+	}
+}
+
+func TestCurrentConsumerDecodesLegacyTransferRequest(t *testing.T) {
+	legacy := dynamicpb.NewMessage(legacyTransferRequest(t))
+	fields := legacy.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		legacy.Set(fields.Get(i), protoreflect.ValueOfString("legacy-"+string(fields.Get(i).Name())))
+	}
+
+	var current TransferRequest
+	if err := proto.Unmarshal(marshal(t, legacy), &current); err != nil {
+		t.Fatalf("current consumer failed to decode legacy message: %v", err)
+	}
+
+	for i := 0; i < fields.Len(); i++ {
+		name := fields.Get(i).Name()
+		got := current.ProtoReflect().Get(current.ProtoReflect().Descriptor().Fields().ByName(name)).String()
+		if want := "legacy-" + string(name); got != want {
+			t.Errorf("field %s = %q, want %q", name, got, want)
+		}
+	}
+	if current.GetEnvelope() != nil {
+		t.Error("legacy message must decode without envelope")
+	}
+	if version := current.GetEnvelope().Version(); version != 1 {
+		t.Errorf("legacy message schema version = %d, want 1", version)
+	}
+}
+
+func TestFieldNumbersAreFrozen(t *testing.T) {
+	messages := []protoreflect.MessageDescriptor{
+		(&TransferRequest{}).ProtoReflect().Descriptor(),
+		(&TransferResult{}).ProtoReflect().Descriptor(),
+		(&Envelope{}).ProtoReflect().Descriptor(),
+	}
+	for _, message := range messages {
+		frozen, ok := frozenFields[message.FullName()]
+		if !ok {
+			t.Errorf("message %s has no frozen field table", message.FullName())
+			continue
+		}
+
+		for name, number := range frozen {
+			field := message.Fields().ByName(name)
+			if field == nil {
+				t.Errorf("%s.%s was removed, reserve its number instead", message.FullName(), name)
+				continue
+			}
+			if field.Number() != number {
+				t.Errorf("%s.%s renumbered to %d, released as %d", message.FullName(), name, field.Number(), number)
+			}
+		}
+
+		fields := message.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			if _, ok := frozen[fields.Get(i).Name()]; !ok {
+				t.Errorf("%s.%s is not in frozen field table, add it once released", message.FullName(), fields.Get(i).Name())
+			}
+		}
+	}
+
+	legacy := legacyTransferRequest(t).Fields()
+	current := (&TransferRequest{}).ProtoReflect().Descriptor().Fields()
+	for i := 0; i < legacy.Len(); i++ {
+		field := current.ByName(legacy.Get(i).Name())
+		if field.Kind() != legacy.Get(i).Kind() || field.Cardinality() != legacy.Get(i).Cardinality() {
+			t.Errorf("TransferRequest.%s changed wire type, legacy consumer cannot decode it", field.Name())
+		}
+	}
+}
+
+// TestBinaryFixtures decode message bytes captured at each schema version, fixture of released version must never be rewritten
+func TestBinaryFixtures(t *testing.T) {
+	legacy := &TransferRequest{}
+	proto.Merge(legacy, sampleTransferRequest())
+	legacy.ResidentStatus, legacy.BeneficiaryType, legacy.BeneficiaryBankBranchName = "", "", ""
+	legacy.BeneficiaryBankCity, legacy.BeneficiaryBankAddress, legacy.BeneficiaryAddress, legacy.DatiIi = "", "", nil, ""
+	legacy.Currency, legacy.MerchantCode, legacy.ReferenceNumber, legacy.RoutingCode, legacy.Envelope = "", "", "", "", nil
+
+	tests := []struct {
+		fixture string
+		message proto.Message
+	}{
+		{"transfer_request_v1.bin", legacy},
+		{"transfer_request_v3.bin", sampleTransferRequest()},
+		{"transfer_result_v1.bin", sampleTransferResult()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			path := filepath.Join("testdata", tt.fixture)
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, marshal(t, tt.message), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			payload, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read fixture %s: %v", path, err)
+			}
+
+			decoded := tt.message.ProtoReflect().New().Interface()
+			if err := proto.Unmarshal(payload, decoded); err != nil {
+				t.Fatalf("failed to decode fixture %s: %v", path, err)
+			}
+			if !proto.Equal(decoded, tt.message) {
+				t.Errorf("fixture %s decoded to %v, want %v", path, decoded, tt.message)
+			}
+			if !bytes.Equal(marshal(t, decoded), payload) {
+				t.Errorf("fixture %s does not round trip to the same bytes", path)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Package protobuf is kafka message contract of transfer service, *.pb.go is generated from .proto source in this directory.
+package protobuf
+
+import "google.golang.org/protobuf/proto"
+
+//go:generate protoc --proto_path=. --go_out=../.. envelope.proto transfer_instruction.proto transfer_result.proto
+
+// message type carried in envelope, consumer dispatch on it instead of topic name
+const (
+	MessageTypeTransferRequest = "transfer.request"
+	MessageTypeTransferResult  = "transfer.result"
+)
+
+// current schema version of each message, message without envelope is version 1
+const (
+	TransferRequestSchemaVersion uint32 = 3
+	TransferResultSchemaVersion  uint32 = 1
+)
+
+// Version return schema version of message, version 1 when producer predates envelope
+func (e *Envelope) Version() uint32 {
+	if e == nil || e.GetSchemaVersion() == 0 {
+		return 1
+	}
+	return e.GetSchemaVersion()
+}
+
+// DecodeMessage decode payload by message type in its envelope, payload without envelope is transfer instruction
+func DecodeMessage(payload []byte) (proto.Message, error) {
+	// every message carries envelope as field 100, so any of them reads it
+	var request TransferRequest
+	if err := proto.Unmarshal(payload, &request); err != nil {
+		return nil, err
+	}
+	if request.GetEnvelope().GetMessageType() != MessageTypeTransferResult {
+		return &request, nil
+	}
+
+	var result TransferResult
+	if err := proto.Unmarshal(payload, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -21,6 +21,8 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// TransferRequest is transfer instruction published to partner topic.
+// Field number must never be reused or renumbered, append new field and bump schema version instead.
 type TransferRequest struct {
 	state                protoimpl.MessageState `protogen:"open.v1"`
 	ExternalId           string                 `protobuf:"bytes,1,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
@@ -40,8 +42,23 @@ type TransferRequest struct {
 	TransferPurpose      string                 `protobuf:"bytes,15,opt,name=transfer_purpose,json=transferPurpose,proto3" json:"transfer_purpose,omitempty"`
 	TransferActivity     string                 `protobuf:"bytes,16,opt,name=transfer_activity,json=transferActivity,proto3" json:"transfer_activity,omitempty"`
 	CustomerType         string                 `protobuf:"bytes,17,opt,name=customer_type,json=customerType,proto3" json:"customer_type,omitempty"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+	// clearing info, only filled for sknbi and rtgs channel
+	ResidentStatus            string   `protobuf:"bytes,18,opt,name=resident_status,json=residentStatus,proto3" json:"resident_status,omitempty"`
+	BeneficiaryType           string   `protobuf:"bytes,19,opt,name=beneficiary_type,json=beneficiaryType,proto3" json:"beneficiary_type,omitempty"`
+	BeneficiaryBankBranchName string   `protobuf:"bytes,20,opt,name=beneficiary_bank_branch_name,json=beneficiaryBankBranchName,proto3" json:"beneficiary_bank_branch_name,omitempty"`
+	BeneficiaryBankCity       string   `protobuf:"bytes,21,opt,name=beneficiary_bank_city,json=beneficiaryBankCity,proto3" json:"beneficiary_bank_city,omitempty"`
+	BeneficiaryBankAddress    string   `protobuf:"bytes,22,opt,name=beneficiary_bank_address,json=beneficiaryBankAddress,proto3" json:"beneficiary_bank_address,omitempty"`
+	BeneficiaryAddress        []string `protobuf:"bytes,23,rep,name=beneficiary_address,json=beneficiaryAddress,proto3" json:"beneficiary_address,omitempty"`
+	DatiIi                    string   `protobuf:"bytes,24,opt,name=dati_ii,json=datiIi,proto3" json:"dati_ii,omitempty"`
+	// schema version 3
+	Currency        string `protobuf:"bytes,25,opt,name=currency,proto3" json:"currency,omitempty"`
+	MerchantCode    string `protobuf:"bytes,26,opt,name=merchant_code,json=merchantCode,proto3" json:"merchant_code,omitempty"`
+	ReferenceNumber string `protobuf:"bytes,27,opt,name=reference_number,json=referenceNumber,proto3" json:"reference_number,omitempty"`
+	// bank partner or wallet provider code instruction is routed to
+	RoutingCode   string    `protobuf:"bytes,28,opt,name=routing_code,json=routingCode,proto3" json:"routing_code,omitempty"`
+	Envelope      *Envelope `protobuf:"bytes,100,opt,name=envelope,proto3" json:"envelope,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *TransferRequest) Reset() {
@@ -193,11 +210,95 @@ func (x *TransferRequest) GetCustomerType() string {
 	return ""
 }
 
+func (x *TransferRequest) GetResidentStatus() string {
+	if x != nil {
+		return x.ResidentStatus
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetBeneficiaryType() string {
+	if x != nil {
+		return x.BeneficiaryType
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetBeneficiaryBankBranchName() string {
+	if x != nil {
+		return x.BeneficiaryBankBranchName
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetBeneficiaryBankCity() string {
+	if x != nil {
+		return x.BeneficiaryBankCity
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetBeneficiaryBankAddress() string {
+	if x != nil {
+		return x.BeneficiaryBankAddress
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetBeneficiaryAddress() []string {
+	if x != nil {
+		return x.BeneficiaryAddress
+	}
+	return nil
+}
+
+func (x *TransferRequest) GetDatiIi() string {
+	if x != nil {
+		return x.DatiIi
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetMerchantCode() string {
+	if x != nil {
+		return x.MerchantCode
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetReferenceNumber() string {
+	if x != nil {
+		return x.ReferenceNumber
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetRoutingCode() string {
+	if x != nil {
+		return x.RoutingCode
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetEnvelope() *Envelope {
+	if x != nil {
+		return x.Envelope
+	}
+	return nil
+}
+
 var File_transfer_instruction_proto protoreflect.FileDescriptor
 
 const file_transfer_instruction_proto_rawDesc = "" +
 	"\n" +
-	"\x1atransfer_instruction.proto\x12\bprotobuf\"\x83\x05\n" +
+	"\x1atransfer_instruction.proto\x12\bprotobuf\x1a\x0eenvelope.proto\"\x8f\t\n" +
 	"\x0fTransferRequest\x12\x1f\n" +
 	"\vexternal_id\x18\x01 \x01(\tR\n" +
 	"externalId\x12$\n" +
@@ -217,7 +318,19 @@ const file_transfer_instruction_proto_rawDesc = "" +
 	"\vcitizenship\x18\x0e \x01(\tR\vcitizenship\x12)\n" +
 	"\x10transfer_purpose\x18\x0f \x01(\tR\x0ftransferPurpose\x12+\n" +
 	"\x11transfer_activity\x18\x10 \x01(\tR\x10transferActivity\x12#\n" +
-	"\rcustomer_type\x18\x11 \x01(\tR\fcustomerTypeB\x15Z\x13./internal/protobufb\x06proto3"
+	"\rcustomer_type\x18\x11 \x01(\tR\fcustomerType\x12'\n" +
+	"\x0fresident_status\x18\x12 \x01(\tR\x0eresidentStatus\x12)\n" +
+	"\x10beneficiary_type\x18\x13 \x01(\tR\x0fbeneficiaryType\x12?\n" +
+	"\x1cbeneficiary_bank_branch_name\x18\x14 \x01(\tR\x19beneficiaryBankBranchName\x122\n" +
+	"\x15beneficiary_bank_city\x18\x15 \x01(\tR\x13beneficiaryBankCity\x128\n" +
+	"\x18beneficiary_bank_address\x18\x16 \x01(\tR\x16beneficiaryBankAddress\x12/\n" +
+	"\x13beneficiary_address\x18\x17 \x03(\tR\x12beneficiaryAddress\x12\x17\n" +
+	"\adati_ii\x18\x18 \x01(\tR\x06datiIi\x12\x1a\n" +
+	"\bcurrency\x18\x19 \x01(\tR\bcurrency\x12#\n" +
+	"\rmerchant_code\x18\x1a \x01(\tR\fmerchantCode\x12)\n" +
+	"\x10reference_number\x18\x1b \x01(\tR\x0freferenceNumber\x12!\n" +
+	"\frouting_code\x18\x1c \x01(\tR\vroutingCode\x12.\n" +
+	"\benvelope\x18d \x01(\v2\x12.protobuf.EnvelopeR\benvelopeB\x15Z\x13./internal/protobufb\x06proto3"
 
 var (
 	file_transfer_instruction_proto_rawDescOnce sync.Once
@@ -234,13 +347,15 @@ func file_transfer_instruction_proto_rawDescGZIP() []byte {
 var file_transfer_instruction_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_transfer_instruction_proto_goTypes = []any{
 	(*TransferRequest)(nil), // 0: protobuf.TransferRequest
+	(*Envelope)(nil),        // 1: protobuf.Envelope
 }
 var file_transfer_instruction_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: protobuf.TransferRequest.envelope:type_name -> protobuf.Envelope
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_transfer_instruction_proto_init() }
@@ -248,6 +363,7 @@ func file_transfer_instruction_proto_init() {
 	if File_transfer_instruction_proto != nil {
 		return
 	}
+	file_envelope_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
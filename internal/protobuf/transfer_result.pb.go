@@ -0,0 +1,256 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.0
+// source: transfer_result.proto
+
+package protobuf
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TransferResult is outcome of transfer instruction reported back by partner service
+type TransferResult struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ExternalId      string                 `protobuf:"bytes,1,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	PartnerRefNo    string                 `protobuf:"bytes,2,opt,name=partner_ref_no,json=partnerRefNo,proto3" json:"partner_ref_no,omitempty"`
+	ReferenceNumber string                 `protobuf:"bytes,3,opt,name=reference_number,json=referenceNumber,proto3" json:"reference_number,omitempty"`
+	BankReferenceNo string                 `protobuf:"bytes,4,opt,name=bank_reference_no,json=bankReferenceNo,proto3" json:"bank_reference_no,omitempty"`
+	// DONE, PENDING, REJECTED, TIMEOUT
+	Status string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	// SUCCESS, PENDING, FAILED_FINAL, FAILED_RETRYABLE, SUSPECT
+	Outcome                string    `protobuf:"bytes,6,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	ResponseCode           string    `protobuf:"bytes,7,opt,name=response_code,json=responseCode,proto3" json:"response_code,omitempty"`
+	ResponseMessage        string    `protobuf:"bytes,8,opt,name=response_message,json=responseMessage,proto3" json:"response_message,omitempty"`
+	PartnerResponseCode    string    `protobuf:"bytes,9,opt,name=partner_response_code,json=partnerResponseCode,proto3" json:"partner_response_code,omitempty"`
+	PartnerResponseMessage string    `protobuf:"bytes,10,opt,name=partner_response_message,json=partnerResponseMessage,proto3" json:"partner_response_message,omitempty"`
+	Amount                 string    `protobuf:"bytes,11,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency               string    `protobuf:"bytes,12,opt,name=currency,proto3" json:"currency,omitempty"`
+	RoutingCode            string    `protobuf:"bytes,13,opt,name=routing_code,json=routingCode,proto3" json:"routing_code,omitempty"`
+	CompletedAt            string    `protobuf:"bytes,14,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Envelope               *Envelope `protobuf:"bytes,100,opt,name=envelope,proto3" json:"envelope,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *TransferResult) Reset() {
+	*x = TransferResult{}
+	mi := &file_transfer_result_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferResult) ProtoMessage() {}
+
+func (x *TransferResult) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_result_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferResult.ProtoReflect.Descriptor instead.
+func (*TransferResult) Descriptor() ([]byte, []int) {
+	return file_transfer_result_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TransferResult) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *TransferResult) GetPartnerRefNo() string {
+	if x != nil {
+		return x.PartnerRefNo
+	}
+	return ""
+}
+
+func (x *TransferResult) GetReferenceNumber() string {
+	if x != nil {
+		return x.ReferenceNumber
+	}
+	return ""
+}
+
+func (x *TransferResult) GetBankReferenceNo() string {
+	if x != nil {
+		return x.BankReferenceNo
+	}
+	return ""
+}
+
+func (x *TransferResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TransferResult) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *TransferResult) GetResponseCode() string {
+	if x != nil {
+		return x.ResponseCode
+	}
+	return ""
+}
+
+func (x *TransferResult) GetResponseMessage() string {
+	if x != nil {
+		return x.ResponseMessage
+	}
+	return ""
+}
+
+func (x *TransferResult) GetPartnerResponseCode() string {
+	if x != nil {
+		return x.PartnerResponseCode
+	}
+	return ""
+}
+
+func (x *TransferResult) GetPartnerResponseMessage() string {
+	if x != nil {
+		return x.PartnerResponseMessage
+	}
+	return ""
+}
+
+func (x *TransferResult) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *TransferResult) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *TransferResult) GetRoutingCode() string {
+	if x != nil {
+		return x.RoutingCode
+	}
+	return ""
+}
+
+func (x *TransferResult) GetCompletedAt() string {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return ""
+}
+
+func (x *TransferResult) GetEnvelope() *Envelope {
+	if x != nil {
+		return x.Envelope
+	}
+	return nil
+}
+
+var File_transfer_result_proto protoreflect.FileDescriptor
+
+const file_transfer_result_proto_rawDesc = "" +
+	"\n" +
+	"\x15transfer_result.proto\x12\bprotobuf\x1a\x0eenvelope.proto\"\xc8\x04\n" +
+	"\x0eTransferResult\x12\x1f\n" +
+	"\vexternal_id\x18\x01 \x01(\tR\n" +
+	"externalId\x12$\n" +
+	"\x0epartner_ref_no\x18\x02 \x01(\tR\fpartnerRefNo\x12)\n" +
+	"\x10reference_number\x18\x03 \x01(\tR\x0freferenceNumber\x12*\n" +
+	"\x11bank_reference_no\x18\x04 \x01(\tR\x0fbankReferenceNo\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x18\n" +
+	"\aoutcome\x18\x06 \x01(\tR\aoutcome\x12#\n" +
+	"\rresponse_code\x18\a \x01(\tR\fresponseCode\x12)\n" +
+	"\x10response_message\x18\b \x01(\tR\x0fresponseMessage\x122\n" +
+	"\x15partner_response_code\x18\t \x01(\tR\x13partnerResponseCode\x128\n" +
+	"\x18partner_response_message\x18\n" +
+	" \x01(\tR\x16partnerResponseMessage\x12\x16\n" +
+	"\x06amount\x18\v \x01(\tR\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\f \x01(\tR\bcurrency\x12!\n" +
+	"\frouting_code\x18\r \x01(\tR\vroutingCode\x12!\n" +
+	"\fcompleted_at\x18\x0e \x01(\tR\vcompletedAt\x12.\n" +
+	"\benvelope\x18d \x01(\v2\x12.protobuf.EnvelopeR\benvelopeB\x15Z\x13./internal/protobufb\x06proto3"
+
+var (
+	file_transfer_result_proto_rawDescOnce sync.Once
+	file_transfer_result_proto_rawDescData []byte
+)
+
+func file_transfer_result_proto_rawDescGZIP() []byte {
+	file_transfer_result_proto_rawDescOnce.Do(func() {
+		file_transfer_result_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_transfer_result_proto_rawDesc), len(file_transfer_result_proto_rawDesc)))
+	})
+	return file_transfer_result_proto_rawDescData
+}
+
+var file_transfer_result_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_transfer_result_proto_goTypes = []any{
+	(*TransferResult)(nil), // 0: protobuf.TransferResult
+	(*Envelope)(nil),       // 1: protobuf.Envelope
+}
+var file_transfer_result_proto_depIdxs = []int32{
+	1, // 0: protobuf.TransferResult.envelope:type_name -> protobuf.Envelope
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_transfer_result_proto_init() }
+func file_transfer_result_proto_init() {
+	if File_transfer_result_proto != nil {
+		return
+	}
+	file_envelope_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_transfer_result_proto_rawDesc), len(file_transfer_result_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_transfer_result_proto_goTypes,
+		DependencyIndexes: file_transfer_result_proto_depIdxs,
+		MessageInfos:      file_transfer_result_proto_msgTypes,
+	}.Build()
+	File_transfer_result_proto = out.File
+	file_transfer_result_proto_goTypes = nil
+	file_transfer_result_proto_depIdxs = nil
+}
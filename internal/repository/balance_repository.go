@@ -42,14 +42,14 @@ func (a *balanceRepository) Debit(ctx context.Context, merchantCode string, tota
 func (a *balanceRepository) Credit(ctx context.Context, merchantCode string, amount float64) (float64, error) {
 	var balance float64
 	result := a.db.WithContext(ctx).Model(&entity.MerchantAccounts{}).
-		Where("merchant_code = ?", merchantCode, amount).
+		Where("merchant_code = ?", merchantCode).
 		Clauses(clause.Returning{Columns: []clause.Column{{Name: "balance"}}}).
 		UpdateColumn("balance", gorm.Expr("balance + ?", amount)).Scan(&balance)
 	if result.Error != nil {
 		return 0, result.Error
 	}
 	if result.RowsAffected == 0 {
-		return -1, fmt.Errorf("insufficient balance")
+		return -1, fmt.Errorf("merchant account %s: %w", merchantCode, ErrRecordNotFound)
 	}
 	return balance, nil
 }
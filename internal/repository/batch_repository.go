@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type BatchRepository interface {
+	Save(ctx context.Context, batch *entity.TransferBatch) error
+	FindByReference(ctx context.Context, merchantCode, batchReference string) (*entity.TransferBatch, error)
+	FindByStatus(ctx context.Context, status string) ([]entity.TransferBatch, error)
+	UpdateItem(ctx context.Context, item *entity.TransferBatchItem) error
+	Complete(ctx context.Context, id int64, successCount, failedCount int) error
+	WithTransaction(trx *gorm.DB) BatchRepository
+}
+
+type batchRepository struct {
+	db *gorm.DB
+}
+
+func NewBatchRepository(db *gorm.DB) BatchRepository {
+	return &batchRepository{db}
+}
+
+func (b *batchRepository) Save(ctx context.Context, batch *entity.TransferBatch) error {
+	if err := b.db.WithContext(ctx).Create(batch).Error; err != nil {
+		return fmt.Errorf("failed to save transfer batch %s, with error: %w", batch.BatchReference, err)
+	}
+	return nil
+}
+
+func (b *batchRepository) FindByReference(ctx context.Context, merchantCode, batchReference string) (*entity.TransferBatch, error) {
+	var batch entity.TransferBatch
+	err := b.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("row_number ASC") }).
+		Where("merchant_code = ? AND batch_reference = ?", merchantCode, batchReference).
+		First(&batch).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get transfer batch %s, with error: %w", batchReference, err)
+	}
+	return &batch, nil
+}
+
+func (b *batchRepository) FindByStatus(ctx context.Context, status string) ([]entity.TransferBatch, error) {
+	var batches []entity.TransferBatch
+	err := b.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("row_number ASC") }).
+		Where("status = ?", status).Order("id ASC").Find(&batches).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer batch with status %s, with error: %w", status, err)
+	}
+	return batches, nil
+}
+
+func (b *batchRepository) UpdateItem(ctx context.Context, item *entity.TransferBatchItem) error {
+	err := b.db.WithContext(ctx).Model(&entity.TransferBatchItem{}).Where("id = ?", item.ID).
+		Updates(map[string]any{
+			"status":           item.Status,
+			"response_code":    item.ResponseCode,
+			"response_message": item.ResponseMessage,
+			"reference_number": item.ReferenceNumber,
+			"last_updated":     time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update transfer batch item id %d, with error: %w", item.ID, err)
+	}
+	return nil
+}
+
+func (b *batchRepository) Complete(ctx context.Context, id int64, successCount, failedCount int) error {
+	err := b.db.WithContext(ctx).Model(&entity.TransferBatch{}).Where("id = ?", id).
+		Updates(map[string]any{
+			"status":        constants.BatchCompleted,
+			"success_count": successCount,
+			"failed_count":  failedCount,
+			"completed_at":  time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to complete transfer batch id %d, with error: %w", id, err)
+	}
+	return nil
+}
+
+func (b *batchRepository) WithTransaction(trx *gorm.DB) BatchRepository {
+	return &batchRepository{db: trx}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"briefcash-transfer/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalendarRepository interface {
+	FindHolidays(ctx context.Context, from, to time.Time) ([]entity.Holiday, error)
+	SaveHoliday(ctx context.Context, holiday *entity.Holiday) error
+	FindCutoffs(ctx context.Context) ([]entity.ChannelCutoff, error)
+	FindPreference(ctx context.Context, merchantCode string) (*entity.MerchantTransferPreference, error)
+}
+
+type calendarRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) CalendarRepository {
+	return &calendarRepository{db}
+}
+
+func (c *calendarRepository) FindHolidays(ctx context.Context, from, to time.Time) ([]entity.Holiday, error) {
+	var holidays []entity.Holiday
+	err := c.db.WithContext(ctx).Where("holiday_date >= ? AND holiday_date < ?", from, to).Order("holiday_date ASC").Find(&holidays).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays, with error: %w", err)
+	}
+	return holidays, nil
+}
+
+// SaveHoliday insert holiday or replace description of existing date
+func (c *calendarRepository) SaveHoliday(ctx context.Context, holiday *entity.Holiday) error {
+	err := c.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "holiday_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description"}),
+	}).Create(holiday).Error
+	if err != nil {
+		return fmt.Errorf("failed to save holiday %s, with error: %w", holiday.HolidayDate.Format(time.DateOnly), err)
+	}
+	return nil
+}
+
+func (c *calendarRepository) FindCutoffs(ctx context.Context) ([]entity.ChannelCutoff, error) {
+	var cutoffs []entity.ChannelCutoff
+	if err := c.db.WithContext(ctx).Find(&cutoffs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get channel cutoff, with error: %w", err)
+	}
+	return cutoffs, nil
+}
+
+func (c *calendarRepository) FindPreference(ctx context.Context, merchantCode string) (*entity.MerchantTransferPreference, error) {
+	var preference entity.MerchantTransferPreference
+	if err := c.db.WithContext(ctx).Where("merchant_code = ?", merchantCode).First(&preference).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get transfer preference of merchant %s, with error: %w", merchantCode, err)
+	}
+	return &preference, nil
+}
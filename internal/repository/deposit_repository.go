@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"briefcash-transfer/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DepositRepository interface {
+	Save(ctx context.Context, deposit *entity.Deposit) (bool, error)
+	FindByReference(ctx context.Context, depositReference string) (*entity.Deposit, error)
+	WithTransaction(trx *gorm.DB) DepositRepository
+}
+
+type depositRepository struct {
+	db *gorm.DB
+}
+
+func NewDepositRepository(db *gorm.DB) DepositRepository {
+	return &depositRepository{db}
+}
+
+// Save inserts the deposit and reports false when the deposit reference was already recorded
+func (d *depositRepository) Save(ctx context.Context, deposit *entity.Deposit) (bool, error) {
+	result := d.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "deposit_reference"}}, DoNothing: true}).
+		Create(deposit)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to save deposit %s, with error: %w", deposit.DepositReference, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (d *depositRepository) FindByReference(ctx context.Context, depositReference string) (*entity.Deposit, error) {
+	var deposit entity.Deposit
+	if err := d.db.WithContext(ctx).Where("deposit_reference = ?", depositReference).First(&deposit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get deposit %s, with error: %w", depositReference, err)
+	}
+	return &deposit, nil
+}
+
+func (d *depositRepository) WithTransaction(trx *gorm.DB) DepositRepository {
+	return &depositRepository{db: trx}
+}
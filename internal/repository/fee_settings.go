@@ -9,8 +9,6 @@ import (
 	"gorm.io/gorm"
 )
 
-var ErrRecordNotFound = errors.New("record not found in database")
-
 type FeeRepository interface {
 	FindAll(ctx context.Context) ([]entity.FeeSettings, error)
 	WithTransaction(trx *gorm.DB) FeeRepository
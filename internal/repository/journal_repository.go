@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"briefcash-transfer/internal/entity"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type JournalRepository interface {
+	Save(ctx context.Context, journal *entity.Journal) error
+	FindByTransaction(ctx context.Context, transactionId int64, journalType string) (*entity.Journal, error)
+	TrialBalance(ctx context.Context, from, to time.Time) ([]entity.TrialBalance, error)
+	FindUnbalanced(ctx context.Context, from, to time.Time) ([]entity.UnbalancedJournal, error)
+	WithTransaction(trx *gorm.DB) JournalRepository
+}
+
+type journalRepository struct {
+	db *gorm.DB
+}
+
+func NewJournalRepository(db *gorm.DB) JournalRepository {
+	return &journalRepository{db}
+}
+
+func (j *journalRepository) Save(ctx context.Context, journal *entity.Journal) error {
+	if err := j.db.WithContext(ctx).Create(journal).Error; err != nil {
+		return fmt.Errorf("failed to save journal %s, with error: %w", journal.Reference, err)
+	}
+	return nil
+}
+
+func (j *journalRepository) FindByTransaction(ctx context.Context, transactionId int64, journalType string) (*entity.Journal, error) {
+	var journal entity.Journal
+	result := j.db.WithContext(ctx).Preload("Lines").
+		Where("transaction_id = ? AND journal_type = ?", transactionId, journalType).
+		Limit(1).Find(&journal)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get journal for transaction id %d, with error: %w", transactionId, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &journal, nil
+}
+
+func (j *journalRepository) TrialBalance(ctx context.Context, from, to time.Time) ([]entity.TrialBalance, error) {
+	var trialBalance []entity.TrialBalance
+	err := j.db.WithContext(ctx).Model(&entity.JournalLine{}).
+		Select("account_code, SUM(debit) AS total_debit, SUM(credit) AS total_credit").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("account_code").Order("account_code ASC").
+		Scan(&trialBalance).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate trial balance, with error: %w", err)
+	}
+	return trialBalance, nil
+}
+
+func (j *journalRepository) FindUnbalanced(ctx context.Context, from, to time.Time) ([]entity.UnbalancedJournal, error) {
+	var journals []entity.UnbalancedJournal
+	err := j.db.WithContext(ctx).Model(&entity.JournalLine{}).
+		Select("journal_lines.journal_id, journals.reference, journals.journal_type, SUM(journal_lines.debit - journal_lines.credit) AS difference").
+		Joins("INNER JOIN journals ON journals.id = journal_lines.journal_id").
+		Where("journals.created_at >= ? AND journals.created_at < ?", from, to).
+		Group("journal_lines.journal_id, journals.reference, journals.journal_type").
+		Having("ABS(SUM(journal_lines.debit - journal_lines.credit)) >= 0.01").
+		Scan(&journals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check journal balance, with error: %w", err)
+	}
+	return journals, nil
+}
+
+func (j *journalRepository) WithTransaction(trx *gorm.DB) JournalRepository {
+	return &journalRepository{db: trx}
+}
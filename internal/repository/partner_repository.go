@@ -24,7 +24,7 @@ func (r *partnerRepository) FindAll(ctx context.Context) ([]entity.BankConfig, e
 	var listConfig []entity.BankConfig
 
 	err := r.db.WithContext(ctx).
-		Select("partner.company_bank_code AS bank_code, domestic_bank.short_name AS bank_name, partner_url.kafka_topic, partner_url.kafka_topic_group").
+		Select("partner.company_bank_code AS bank_code, domestic_bank.short_name AS bank_name, partner_url.kafka_topic, partner_url.kafka_topic_group, partner_url.inquiry_url").
 		Joins("INNER JOIN partner_url ON partner.company_id = partner_url.company_id").
 		Joins("INNER JOIN domestic_bank ON partner.company_id = domestic_bank.company_id").
 		Scan(&listConfig).Error
@@ -19,15 +19,20 @@ const (
 	TotalCharge    string = "total_charge"
 )
 
+// KeyFeeLoaded is written with full fee list, flushing redis removes it together with cached fee
+const KeyFeeLoaded string = "fee_settings_loaded"
+
 type RedisRepository interface {
 	SetListFee(ctx context.Context, settings []entity.FeeSettings) error
 	SetFee(ctx context.Context, feeSetting entity.FeeSettings) error
 	SetBalance(ctx context.Context, balance []entity.MerchantBalance) error
 	SetPendingStatus(ctx context.Context, externalId string) error
 	FindByCodeAndChannel(ctx context.Context, merchantCode, channel string) (entity.FeeSettings, error)
+	HasFeeSettings(ctx context.Context) (bool, error)
 	FindByMerchantCode(ctx context.Context, merchantCode string) (float64, error)
 	UpdateBalance(ctx context.Context, merchantCode, amount string) error
 	RefundBalance(ctx context.Context, merchantCode string, amount float64) error
+	CreditBalance(ctx context.Context, merchantCode string, amount, databaseBalance float64) (float64, error)
 	DeletePendingStatus(ctx context.Context, externalId string) error
 }
 
@@ -58,6 +63,7 @@ func (r *redisRepository) SetListFee(ctx context.Context, listFee []entity.FeeSe
 
 		pipe.HSet(ctx, key, data)
 	}
+	pipe.Set(ctx, KeyFeeLoaded, len(listFee), 0)
 
 	if _, err := pipe.Exec(ctx); err != nil {
 		return fmt.Errorf("failed to cache list fee setting to redis, with error: %w", err)
@@ -115,6 +121,15 @@ func (r *redisRepository) SetPendingStatus(ctx context.Context, externalId strin
 	return nil
 }
 
+// HasFeeSettings reports whether fee list is still cached, checked by single key lookup so readiness probe does not scan keyspace
+func (r *redisRepository) HasFeeSettings(ctx context.Context) (bool, error) {
+	count, err := r.client.Exists(ctx, KeyFeeLoaded).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check fee setting in redis, with error: %w", err)
+	}
+	return count > 0, nil
+}
+
 func (r *redisRepository) FindByCodeAndChannel(ctx context.Context, merchantCode, channel string) (entity.FeeSettings, error) {
 	key := fmt.Sprintf("%s:%s:%s", KeyFeeSettings, merchantCode, channel)
 
@@ -154,7 +169,7 @@ func (r *redisRepository) FindByMerchantCode(ctx context.Context, merchantCode s
 
 	if err != nil {
 		if err == redis.Nil {
-			return 0, fmt.Errorf("key or field not found in redis")
+			return 0, fmt.Errorf("merchant %s balance not found in redis: %w", merchantCode, err)
 		}
 
 		return 0, fmt.Errorf("error in redis server while retrieving merchant balance, with error: %w", err)
@@ -178,6 +193,26 @@ func (r *redisRepository) RefundBalance(ctx context.Context, merchantCode string
 	return nil
 }
 
+// creditBalanceScript add amount to cached balance, merchant missing from cache is set to its database balance instead,
+// so credit never creates balance holding only the credited amount
+var creditBalanceScript = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 1 then
+	return redis.call('HINCRBYFLOAT', KEYS[1], ARGV[1], ARGV[2])
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[3])
+return ARGV[3]
+`)
+
+// CreditBalance apply credit already committed to database, databaseBalance is merchant balance after that commit
+func (r *redisRepository) CreditBalance(ctx context.Context, merchantCode string, amount, databaseBalance float64) (float64, error) {
+	result, err := creditBalanceScript.Run(ctx, r.client, []string{KeyBalance}, merchantCode,
+		strconv.FormatFloat(amount, 'f', 2, 64), strconv.FormatFloat(databaseBalance, 'f', 2, 64)).Text()
+	if err != nil {
+		return 0, fmt.Errorf("failed to credit balance in redis, with error: %w", err)
+	}
+	return strconv.ParseFloat(result, 64)
+}
+
 func (r *redisRepository) DeletePendingStatus(ctx context.Context, externalId string) error {
 	key := "pending_transaction"
 
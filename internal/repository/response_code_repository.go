@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"briefcash-transfer/internal/entity"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ResponseCodeRepository interface {
+	FindAll(ctx context.Context) ([]entity.PartnerResponseCode, error)
+	FindByBank(ctx context.Context, bankCode string) ([]entity.PartnerResponseCode, error)
+	Save(ctx context.Context, mapping *entity.PartnerResponseCode) error
+}
+
+type responseCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewResponseCodeRepository(db *gorm.DB) ResponseCodeRepository {
+	return &responseCodeRepository{db}
+}
+
+func (r *responseCodeRepository) FindAll(ctx context.Context) ([]entity.PartnerResponseCode, error) {
+	var mappings []entity.PartnerResponseCode
+	if err := r.db.WithContext(ctx).Find(&mappings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get partner response code mapping, with error: %w", err)
+	}
+	return mappings, nil
+}
+
+func (r *responseCodeRepository) FindByBank(ctx context.Context, bankCode string) ([]entity.PartnerResponseCode, error) {
+	var mappings []entity.PartnerResponseCode
+	err := r.db.WithContext(ctx).Where("bank_code = ?", bankCode).Order("partner_code ASC").Find(&mappings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response code mapping of bank %s, with error: %w", bankCode, err)
+	}
+	return mappings, nil
+}
+
+// Save insert mapping or replace outcome of existing bank and partner code
+func (r *responseCodeRepository) Save(ctx context.Context, mapping *entity.PartnerResponseCode) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "bank_code"}, {Name: "partner_code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"outcome", "response_code", "description", "updated_at"}),
+	}).Create(mapping).Error
+	if err != nil {
+		return fmt.Errorf("failed to save response code %s of bank %s, with error: %w", mapping.PartnerCode, mapping.BankCode, err)
+	}
+	return nil
+}
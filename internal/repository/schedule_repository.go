@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ScheduleRepository interface {
+	Save(ctx context.Context, schedule *entity.ScheduledTransfer) error
+	FindByReference(ctx context.Context, merchantCode, scheduleReference string) (*entity.ScheduledTransfer, error)
+	FindByMerchant(ctx context.Context, merchantCode, status string) ([]entity.ScheduledTransfer, error)
+	FindDueForUpdate(ctx context.Context, now time.Time, limit int) ([]entity.ScheduledTransfer, error)
+	Update(ctx context.Context, schedule *entity.ScheduledTransfer) error
+	UpdateResult(ctx context.Context, id int64, responseCode, responseMessage string, failedOnce bool) error
+	Cancel(ctx context.Context, id int64) (bool, error)
+	FindById(ctx context.Context, id int64) (*entity.ScheduledTransfer, error)
+	FindStaleRunsForUpdate(ctx context.Context, claimedBefore time.Time, limit int) ([]entity.ScheduledTransferRun, error)
+	SaveRun(ctx context.Context, run *entity.ScheduledTransferRun) error
+	UpdateRun(ctx context.Context, run *entity.ScheduledTransferRun) error
+	WithTransaction(trx *gorm.DB) ScheduleRepository
+}
+
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository(db *gorm.DB) ScheduleRepository {
+	return &scheduleRepository{db}
+}
+
+func (s *scheduleRepository) Save(ctx context.Context, schedule *entity.ScheduledTransfer) error {
+	if err := s.db.WithContext(ctx).Create(schedule).Error; err != nil {
+		return fmt.Errorf("failed to save scheduled transfer %s, with error: %w", schedule.ScheduleReference, err)
+	}
+	return nil
+}
+
+func (s *scheduleRepository) FindByReference(ctx context.Context, merchantCode, scheduleReference string) (*entity.ScheduledTransfer, error) {
+	var schedule entity.ScheduledTransfer
+	err := s.db.WithContext(ctx).Where("merchant_code = ? AND schedule_reference = ?", merchantCode, scheduleReference).First(&schedule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get scheduled transfer %s, with error: %w", scheduleReference, err)
+	}
+	return &schedule, nil
+}
+
+func (s *scheduleRepository) FindByMerchant(ctx context.Context, merchantCode, status string) ([]entity.ScheduledTransfer, error) {
+	var schedules []entity.ScheduledTransfer
+	query := s.db.WithContext(ctx).Where("merchant_code = ?", merchantCode)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("next_run_at ASC").Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("failed to get scheduled transfer of merchant %s, with error: %w", merchantCode, err)
+	}
+	return schedules, nil
+}
+
+// FindDueForUpdate lock due schedules, rows locked by other instance are skipped
+func (s *scheduleRepository) FindDueForUpdate(ctx context.Context, now time.Time, limit int) ([]entity.ScheduledTransfer, error) {
+	var schedules []entity.ScheduledTransfer
+	err := s.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_run_at <= ?", constants.ScheduleActive, now).
+		Order("next_run_at ASC").Limit(limit).Find(&schedules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due scheduled transfer, with error: %w", err)
+	}
+	return schedules, nil
+}
+
+func (s *scheduleRepository) Update(ctx context.Context, schedule *entity.ScheduledTransfer) error {
+	schedule.LastUpdated = time.Now()
+	if err := s.db.WithContext(ctx).Save(schedule).Error; err != nil {
+		return fmt.Errorf("failed to update scheduled transfer id %d, with error: %w", schedule.ID, err)
+	}
+	return nil
+}
+
+// UpdateResult record last run result without overwriting status changed by cancellation, single schedule is marked failed
+func (s *scheduleRepository) UpdateResult(ctx context.Context, id int64, responseCode, responseMessage string, failedOnce bool) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.ScheduledTransfer{}).Where("id = ?", id).
+			Updates(map[string]any{"last_response_code": responseCode, "last_response_message": responseMessage, "last_updated": time.Now()}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update scheduled transfer id %d, with error: %w", id, err)
+		}
+
+		if !failedOnce {
+			return nil
+		}
+
+		err = tx.Model(&entity.ScheduledTransfer{}).Where("id = ? AND status = ?", id, constants.ScheduleCompleted).
+			Update("status", constants.ScheduleFailed).Error
+		if err != nil {
+			return fmt.Errorf("failed to update scheduled transfer id %d, with error: %w", id, err)
+		}
+		return nil
+	})
+}
+
+// Cancel reports false when schedule is no longer active
+func (s *scheduleRepository) Cancel(ctx context.Context, id int64) (bool, error) {
+	result := s.db.WithContext(ctx).Model(&entity.ScheduledTransfer{}).
+		Where("id = ? AND status = ?", id, constants.ScheduleActive).
+		Updates(map[string]any{"status": constants.ScheduleCancelled, "last_updated": time.Now()})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to cancel scheduled transfer id %d, with error: %w", id, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *scheduleRepository) FindById(ctx context.Context, id int64) (*entity.ScheduledTransfer, error) {
+	var schedule entity.ScheduledTransfer
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get scheduled transfer id %d, with error: %w", id, err)
+	}
+	return &schedule, nil
+}
+
+// FindStaleRunsForUpdate lock runs claimed before claimedBefore and never finished, rows locked by other instance are skipped
+func (s *scheduleRepository) FindStaleRunsForUpdate(ctx context.Context, claimedBefore time.Time, limit int) ([]entity.ScheduledTransferRun, error) {
+	var runs []entity.ScheduledTransferRun
+	err := s.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND claimed_at <= ?", constants.ScheduleRunClaimed, claimedBefore).
+		Order("claimed_at ASC").Limit(limit).Find(&runs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stale scheduled transfer run, with error: %w", err)
+	}
+	return runs, nil
+}
+
+func (s *scheduleRepository) SaveRun(ctx context.Context, run *entity.ScheduledTransferRun) error {
+	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
+		return fmt.Errorf("failed to save scheduled transfer run, with error: %w", err)
+	}
+	return nil
+}
+
+func (s *scheduleRepository) UpdateRun(ctx context.Context, run *entity.ScheduledTransferRun) error {
+	if err := s.db.WithContext(ctx).Save(run).Error; err != nil {
+		return fmt.Errorf("failed to update scheduled transfer run id %d, with error: %w", run.ID, err)
+	}
+	return nil
+}
+
+func (s *scheduleRepository) WithTransaction(trx *gorm.DB) ScheduleRepository {
+	return &scheduleRepository{db: trx}
+}
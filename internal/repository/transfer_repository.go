@@ -7,12 +7,19 @@ import (
 	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TransferRepository interface {
 	Save(ctx context.Context, trx *entity.Transaction) error
 	FindByRefNo(ctx context.Context, partnerReferenceNo string) (*entity.TransferTemp, error)
-	Update(ctx context.Context, id int64, status string) error
+	FindExistingReferences(ctx context.Context, merchantCode string, partnerReferenceNos []string) ([]string, error)
+	FindForUpdate(ctx context.Context, merchantCode, partnerReferenceNo string) (*entity.Transaction, error)
+	FindForUpdateByReferenceNumber(ctx context.Context, referenceNumber string) (*entity.Transaction, error)
+	FindReversal(ctx context.Context, originalId int64) (*entity.Transaction, error)
+	UpdateStatus(ctx context.Context, id, version int64, status string) (bool, error)
+	SaveStatusHistory(ctx context.Context, history *entity.TransferStatusHistory) error
+	SaveClearingDetail(ctx context.Context, detail *entity.TransferClearingDetail) error
 	WithTransaction(trx *gorm.DB) TransferRepository
 }
 
@@ -34,20 +41,102 @@ func (r *transferRepository) Save(ctx context.Context, transaction *entity.Trans
 func (r *transferRepository) FindByRefNo(ctx context.Context, partnerReferenceNo string) (*entity.TransferTemp, error) {
 	var transferTemp entity.TransferTemp
 
-	if err := r.db.WithContext(ctx).Select("id", "status").Where("partner_reference_no = ?", partnerReferenceNo).Scan(&transferTemp).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&entity.Transaction{}).Select("id", "status").Where("partner_reference_no = ?", partnerReferenceNo).Limit(1).Scan(&transferTemp).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to query partner reference no %s: %w", partnerReferenceNo, err)
 	}
 
+	if transferTemp.ID == 0 {
+		return nil, nil
+	}
+
 	return &transferTemp, nil
 }
 
-func (r *transferRepository) Update(ctx context.Context, id int64, status string) error {
-	if err := r.db.WithContext(ctx).Model(&entity.Transaction{}).
-		Where("id = ? and status = 'PENDING'", id).Update("status", status).Error; err != nil {
-		return fmt.Errorf("failed to update transfer data in id %d:%w", id, err)
+// FindExistingReferences return which of partner reference no merchant already used
+func (r *transferRepository) FindExistingReferences(ctx context.Context, merchantCode string, partnerReferenceNos []string) ([]string, error) {
+	var existing []string
+	if len(partnerReferenceNos) == 0 {
+		return existing, nil
+	}
+
+	err := r.db.WithContext(ctx).Model(&entity.Transaction{}).
+		Where("merchant_code = ? AND partner_reference_no IN ? AND is_reversal = ?", merchantCode, partnerReferenceNos, false).
+		Distinct().Pluck("partner_reference_no", &existing).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partner reference no of merchant %s: %w", merchantCode, err)
+	}
+	return existing, nil
+}
+
+// FindForUpdate lock transfer of merchant, partner reference no is only unique per merchant
+func (r *transferRepository) FindForUpdate(ctx context.Context, merchantCode, partnerReferenceNo string) (*entity.Transaction, error) {
+	var transaction entity.Transaction
+
+	if err := r.db.WithContext(ctx).Clauses(clause.Locking{
+		Strength: "UPDATE", Table: clause.Table{Name: clause.CurrentTable},
+	}).Where("merchant_code = ? AND partner_reference_no = ? AND is_reversal = ?", merchantCode, partnerReferenceNo, false).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query partner reference no %s of merchant %s: %w", partnerReferenceNo, merchantCode, err)
+	}
+
+	return &transaction, nil
+}
+
+// FindForUpdateByReferenceNumber lock transfer by reference number we generated, it is unique across merchants
+func (r *transferRepository) FindForUpdateByReferenceNumber(ctx context.Context, referenceNumber string) (*entity.Transaction, error) {
+	var transaction entity.Transaction
+
+	if err := r.db.WithContext(ctx).Clauses(clause.Locking{
+		Strength: "UPDATE", Table: clause.Table{Name: clause.CurrentTable},
+	}).Where("system_reference_no = ? AND is_reversal = ?", referenceNumber, false).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query reference number %s: %w", referenceNumber, err)
+	}
+
+	return &transaction, nil
+}
+
+func (r *transferRepository) FindReversal(ctx context.Context, originalId int64) (*entity.Transaction, error) {
+	var transaction entity.Transaction
+
+	if err := r.db.WithContext(ctx).Where("original_transaction_id = ? AND is_reversal = ?", originalId, true).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query reversal of transaction id %d: %w", originalId, err)
+	}
+
+	return &transaction, nil
+}
+
+// UpdateStatus apply status with optimistic locking, reports false when version has moved on
+func (r *transferRepository) UpdateStatus(ctx context.Context, id, version int64, status string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Transaction{}).
+		Where("id = ? AND version = ?", id, version).
+		Updates(map[string]any{"status": status, "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update transfer data in id %d:%w", id, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *transferRepository) SaveStatusHistory(ctx context.Context, history *entity.TransferStatusHistory) error {
+	if err := r.db.WithContext(ctx).Create(history).Error; err != nil {
+		return fmt.Errorf("failed to save status history of transfer id %d, with error: %w", history.TransactionId, err)
+	}
+	return nil
+}
+
+func (r *transferRepository) SaveClearingDetail(ctx context.Context, detail *entity.TransferClearingDetail) error {
+	if err := r.db.WithContext(ctx).Create(detail).Error; err != nil {
+		return fmt.Errorf("failed to save clearing detail of transfer id %d, with error: %w", detail.TransactionId, err)
 	}
 	return nil
 }
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"briefcash-transfer/internal/entity"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type WalletProviderRepository interface {
+	FindAll(ctx context.Context) ([]entity.WalletProvider, error)
+}
+
+type walletProviderRepository struct {
+	db *gorm.DB
+}
+
+func NewWalletProviderRepository(db *gorm.DB) WalletProviderRepository {
+	return &walletProviderRepository{db}
+}
+
+func (r *walletProviderRepository) FindAll(ctx context.Context) ([]entity.WalletProvider, error) {
+	var providers []entity.WalletProvider
+	if err := r.db.WithContext(ctx).Order("wallet_code ASC").Find(&providers).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet provider configuration, with error: %w", err)
+	}
+	return providers, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	SaveEndpoint(ctx context.Context, webhook *entity.MerchantWebhook) error
+	FindEndpoint(ctx context.Context, merchantCode string) (*entity.MerchantWebhook, error)
+	SaveDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	FindDelivery(ctx context.Context, id int64) (*entity.WebhookDelivery, error)
+	FindDeliveryForUpdate(ctx context.Context, id int64) (*entity.WebhookDelivery, error)
+	FindDeliveries(ctx context.Context, merchantCode, status string, limit int) ([]entity.WebhookDelivery, error)
+	FindDueForUpdate(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	SaveLog(ctx context.Context, log *entity.WebhookDeliveryLog) error
+	FindLogs(ctx context.Context, deliveryId int64) ([]entity.WebhookDeliveryLog, error)
+	WithTransaction(trx *gorm.DB) WebhookRepository
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db}
+}
+
+// SaveEndpoint insert or replace callback url and secret of merchant
+func (w *webhookRepository) SaveEndpoint(ctx context.Context, webhook *entity.MerchantWebhook) error {
+	err := w.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "merchant_code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"callback_url", "secret", "is_active", "last_updated"}),
+	}).Create(webhook).Error
+	if err != nil {
+		return fmt.Errorf("failed to save webhook of merchant %s, with error: %w", webhook.MerchantCode, err)
+	}
+	return nil
+}
+
+func (w *webhookRepository) FindEndpoint(ctx context.Context, merchantCode string) (*entity.MerchantWebhook, error) {
+	var webhook entity.MerchantWebhook
+	if err := w.db.WithContext(ctx).Where("merchant_code = ?", merchantCode).First(&webhook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook of merchant %s, with error: %w", merchantCode, err)
+	}
+	return &webhook, nil
+}
+
+func (w *webhookRepository) SaveDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	if err := w.db.WithContext(ctx).Create(delivery).Error; err != nil {
+		return fmt.Errorf("failed to save webhook delivery %s, with error: %w", delivery.PartnerReferenceNo, err)
+	}
+	return nil
+}
+
+func (w *webhookRepository) FindDelivery(ctx context.Context, id int64) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	if err := w.db.WithContext(ctx).Where("id = ?", id).First(&delivery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery id %d, with error: %w", id, err)
+	}
+	return &delivery, nil
+}
+
+func (w *webhookRepository) FindDeliveryForUpdate(ctx context.Context, id int64) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := w.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery id %d, with error: %w", id, err)
+	}
+	return &delivery, nil
+}
+
+func (w *webhookRepository) FindDeliveries(ctx context.Context, merchantCode, status string, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	query := w.db.WithContext(ctx)
+	if merchantCode != "" {
+		query = query.Where("merchant_code = ?", merchantCode)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("id DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries, with error: %w", err)
+	}
+	return deliveries, nil
+}
+
+// FindDueForUpdate lock pending deliveries, rows locked by other instance are skipped
+func (w *webhookRepository) FindDueForUpdate(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	err := w.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", constants.WebhookPending, now).
+		Order("next_attempt_at ASC").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due webhook deliveries, with error: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (w *webhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	delivery.LastUpdated = time.Now()
+	if err := w.db.WithContext(ctx).Save(delivery).Error; err != nil {
+		return fmt.Errorf("failed to update webhook delivery id %d, with error: %w", delivery.ID, err)
+	}
+	return nil
+}
+
+func (w *webhookRepository) SaveLog(ctx context.Context, log *entity.WebhookDeliveryLog) error {
+	if err := w.db.WithContext(ctx).Create(log).Error; err != nil {
+		return fmt.Errorf("failed to save webhook delivery log of id %d, with error: %w", log.DeliveryId, err)
+	}
+	return nil
+}
+
+func (w *webhookRepository) FindLogs(ctx context.Context, deliveryId int64) ([]entity.WebhookDeliveryLog, error) {
+	var logs []entity.WebhookDeliveryLog
+	if err := w.db.WithContext(ctx).Where("delivery_id = ?", deliveryId).Order("id ASC").Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery log of id %d, with error: %w", deliveryId, err)
+	}
+	return logs, nil
+}
+
+func (w *webhookRepository) WithTransaction(trx *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: trx}
+}
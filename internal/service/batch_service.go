@@ -0,0 +1,727 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/amounthelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/repository"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var batchCSVHeader = []string{"row_number", "partner_reference_no", "status", "response_code", "response_message", "reference_number"}
+
+type BatchService interface {
+	Submit(ctx context.Context, request dto.BatchTransferRequest, merchantCode, externalId string) dto.BatchTransferResponse
+	GetBatch(ctx context.Context, merchantCode, batchReference string) dto.BatchTransferResponse
+	ResultFile(ctx context.Context, merchantCode, batchReference string) ([]byte, error)
+	ResumeBatches(ctx context.Context) error
+	Drain(ctx context.Context) error
+}
+
+type batchService struct {
+	batchRepo       repository.BatchRepository
+	transferRepo    repository.TransferRepository
+	transferService TransferService
+	redisService    TransferRedisService
+	calendar        CalendarService
+	scheduleService ScheduleService
+	concurrency     int
+	maxRows         int
+	inflight        *inflightTracker
+}
+
+func NewBatchService(batchRepo repository.BatchRepository, transferRepo repository.TransferRepository, transferService TransferService,
+	redisService TransferRedisService, calendar CalendarService, scheduleService ScheduleService, concurrency, maxRows int) BatchService {
+	return &batchService{batchRepo, transferRepo, transferService, redisService, calendar, scheduleService, concurrency, maxRows, newInflightTracker()}
+}
+
+func (b *batchService) Submit(ctx context.Context, request dto.BatchTransferRequest, merchantCode, externalId string) dto.BatchTransferResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":         "batch_service",
+		"operation":       "submit_batch",
+		"batch_reference": request.BatchReference,
+		"trace_id":        externalId,
+		"merchant":        merchantCode,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	// validate every row before any balance is reserved
+	log.Infof("Validating batch with %d rows", len(request.Transfers))
+	if rowErrors := b.validateBatch(request); len(rowErrors) > 0 {
+		log.Warnf("Batch rejected, %d invalid rows", len(rowErrors))
+		response := b.handleBatchResponse(constants.ErrBadRequest, request.BatchReference, nil)
+		response.TotalRows = len(request.Transfers)
+		response.FailedCount = len(rowErrors)
+		response.Rows = rowErrors
+		return response
+	}
+
+	// partner reference no must be new for merchant, so every executed transfer belongs to exactly one row
+	rowErrors, err := b.checkExistingReferences(ctx, request, merchantCode)
+	if err != nil {
+		log.WithError(err).Error("Failed to check partner reference no of batch rows")
+		return b.handleBatchResponse(constants.ErrInternalServerError, request.BatchReference, nil)
+	}
+
+	if len(rowErrors) > 0 {
+		log.Warnf("Batch rejected, %d rows reuse partner reference no", len(rowErrors))
+		response := b.handleBatchResponse(constants.ErrBadRequest, request.BatchReference, nil)
+		response.TotalRows = len(request.Transfers)
+		response.FailedCount = len(rowErrors)
+		response.Rows = rowErrors
+		return response
+	}
+
+	existing, err := b.batchRepo.FindByReference(ctx, merchantCode, request.BatchReference)
+	if err != nil {
+		log.WithError(err).Error("Failed to check batch reference")
+		return b.handleBatchResponse(constants.ErrInternalServerError, request.BatchReference, nil)
+	}
+
+	if existing != nil {
+		log.Warn("Batch reference already submitted")
+		return b.handleBatchResponse(constants.ErrDuplicateReference, request.BatchReference, existing)
+	}
+
+	// calculate total amount and service fee for all rows
+	batch, err := b.buildBatch(ctx, request, merchantCode)
+	if err != nil {
+		log.WithError(err).Error("Failed to calculate batch amount")
+		return b.handleBatchResponse(constants.ErrInternalServerError, request.BatchReference, nil)
+	}
+
+	// reserve total amount once in redis
+	log.Infof("Reserve %.2f from merchant balance in redis", batch.ReservedAmount)
+	_, err = b.redisService.DebitBalance(ctx, merchantCode, batch.ReservedAmount)
+	if errors.Is(err, ErrBalanceNotFound) {
+		return b.handleBatchResponse(constants.ErrBalanceNotAvailable, request.BatchReference, nil)
+	}
+
+	if errors.Is(err, ErrInsufficientBalance) {
+		return b.handleBatchResponse(constants.ErrInsufficientFunds, request.BatchReference, nil)
+	}
+
+	if err != nil {
+		return b.handleBatchResponse(constants.ErrInternalServerError, request.BatchReference, nil)
+	}
+
+	// persist batch and rows before processing
+	log.Info("Persist batch and rows to database")
+	if err := b.batchRepo.Save(ctx, batch); err != nil {
+		log.WithError(err).Error("Failed to persist batch, release reserved balance in redis")
+		if err := b.redisService.RefundBalance(ctx, merchantCode, batch.ReservedAmount); err != nil {
+			log.WithError(err).Error("Failed to release reserved balance in redis")
+		}
+		return b.handleBatchResponse(constants.ErrInternalServerError, request.BatchReference, nil)
+	}
+
+	// process rows in background, request context is cancelled once response is written
+	reserved := make(map[int]bool, len(batch.Items))
+	for _, item := range batch.Items {
+		reserved[item.RowNumber] = true
+	}
+	go b.processBatch(context.WithoutCancel(ctx), batch, reserved)
+
+	return b.handleBatchResponse(constants.PendingTransfer, request.BatchReference, batch)
+}
+
+func (b *batchService) GetBatch(ctx context.Context, merchantCode, batchReference string) dto.BatchTransferResponse {
+	log := loghelper.FromContext(ctx)
+	log.Info("Fetch batch status from database")
+	batch, err := b.batchRepo.FindByReference(ctx, merchantCode, batchReference)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch batch")
+		return b.handleBatchResponse(constants.ErrInternalServerError, batchReference, nil)
+	}
+
+	if batch == nil {
+		return b.handleBatchResponse(constants.ErrDataNotFound, batchReference, nil)
+	}
+
+	response := b.handleBatchResponse(constants.TransferSuccess, batchReference, batch)
+	for _, item := range batch.Items {
+		response.Rows = append(response.Rows, dto.BatchRowResult{
+			RowNumber:          item.RowNumber,
+			PartnerReferenceNo: item.PartnerReferenceNo,
+			Status:             item.Status,
+			ResponseCode:       item.ResponseCode,
+			ResponseMessage:    item.ResponseMessage,
+			ReferenceNumber:    item.ReferenceNumber,
+		})
+	}
+	return response
+}
+
+func (b *batchService) ResultFile(ctx context.Context, merchantCode, batchReference string) ([]byte, error) {
+	log := loghelper.FromContext(ctx)
+	log.Info("Fetch batch result from database")
+	batch, err := b.batchRepo.FindByReference(ctx, merchantCode, batchReference)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch == nil {
+		return nil, fmt.Errorf("batch %s: %w", batchReference, repository.ErrRecordNotFound)
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(batchCSVHeader); err != nil {
+		return nil, err
+	}
+
+	for _, item := range batch.Items {
+		record := []string{
+			strconv.Itoa(item.RowNumber), item.PartnerReferenceNo, item.Status,
+			item.ResponseCode, item.ResponseMessage, item.ReferenceNumber,
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// ResumeBatches continue batches interrupted by restart. Merchant balance was reloaded from database, which never held
+// reservation of unfinished rows, so it is reserved again before rows run
+func (b *batchService) ResumeBatches(ctx context.Context) error {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "batch_service",
+		"operation": "resume_batch",
+	})
+
+	batches, err := b.batchRepo.FindByStatus(ctx, constants.BatchProcessing)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch unfinished batch")
+		return err
+	}
+
+	log.Infof("Resuming %d unfinished batch", len(batches))
+	for i := range batches {
+		batch := &batches[i]
+		// resumed batch must outlive startup context, shutdown stops it through Drain
+		batchLog := log.WithField("batch_reference", batch.BatchReference)
+		batchCtx := loghelper.NewContext(context.WithoutCancel(ctx), batchLog)
+
+		if err := b.resolveSubmitted(batchCtx, batch); err != nil {
+			batchLog.WithError(err).Error("Failed to check rows submitted before restart, batch is left for next start")
+			continue
+		}
+
+		reserved := map[int]bool{}
+		var amount float64
+		for _, item := range batch.Items {
+			if item.Status == constants.BatchItemQueued {
+				reserved[item.RowNumber] = true
+				amount = amounthelper.RoundCent(amount + item.ReservedAmount)
+			}
+		}
+
+		if amount > 0 {
+			batchLog.Infof("Reserve %.2f again for %d unfinished rows", amount, len(reserved))
+			_, err := b.redisService.DebitBalance(batchCtx, batch.MerchantCode, amount)
+			if errors.Is(err, ErrInsufficientBalance) || errors.Is(err, ErrBalanceNotFound) {
+				batchLog.WithError(err).Warn("Balance no longer covers unfinished rows, rows are failed")
+				for j := range batch.Items {
+					if item := &batch.Items[j]; item.Status == constants.BatchItemQueued {
+						b.updateItem(batchCtx, item, constants.BatchItemFailed, constants.ErrInsufficientFunds, "")
+					}
+				}
+				b.completeBatch(batchCtx, batch, nil)
+				continue
+			}
+
+			if err != nil {
+				batchLog.WithError(err).Error("Failed to reserve balance of unfinished rows, batch is left for next start")
+				continue
+			}
+		}
+
+		go b.processBatch(batchCtx, batch, reserved)
+	}
+	return nil
+}
+
+// resolveSubmitted settle rows handed to transfer pipeline before restart, row whose transfer exists has executed and its
+// amount is already in database balance, any other row is queued again
+func (b *batchService) resolveSubmitted(ctx context.Context, batch *entity.TransferBatch) error {
+	var references []string
+	for _, item := range batch.Items {
+		if item.Status == constants.BatchItemSubmitting {
+			references = append(references, item.PartnerReferenceNo)
+		}
+	}
+	if len(references) == 0 {
+		return nil
+	}
+
+	existing, err := b.transferRepo.FindExistingReferences(ctx, batch.MerchantCode, references)
+	if err != nil {
+		return err
+	}
+	executed := make(map[string]bool, len(existing))
+	for _, reference := range existing {
+		executed[reference] = true
+	}
+
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		if item.Status != constants.BatchItemSubmitting {
+			continue
+		}
+		if executed[item.PartnerReferenceNo] {
+			loghelper.FromContext(ctx).WithField("row", item.RowNumber).Warn("Batch row executed before restart, marked accepted")
+			b.updateItem(ctx, item, constants.BatchItemAccepted, constants.PendingTransfer, "")
+			continue
+		}
+		item.Status = constants.BatchItemQueued
+	}
+	return nil
+}
+
+// Drain stop dispatching queued rows and wait for running rows, interrupted batch stays processing and is resumed on next start
+func (b *batchService) Drain(ctx context.Context) error {
+	return b.inflight.drain(ctx)
+}
+
+// processBatch run queued rows, reserved holds rows whose reservation this run holds in redis
+func (b *batchService) processBatch(ctx context.Context, batch *entity.TransferBatch, reserved map[int]bool) {
+	log := loghelper.FromContext(ctx)
+	if !b.inflight.acquire() {
+		log.Warn("Service is shutting down, batch is left for resume")
+		return
+	}
+	defer b.inflight.release()
+
+	log.Infof("Processing batch with %d rows and concurrency %d", len(batch.Items), b.concurrency)
+
+	fees := map[string]entity.FeeSettings{}
+	var feeMutex sync.Mutex
+	feeFor := func(channel string) (entity.FeeSettings, error) {
+		feeMutex.Lock()
+		defer feeMutex.Unlock()
+		if fee, ok := fees[channel]; ok {
+			return fee, nil
+		}
+		fee, err := b.transferService.GetFeeSetting(ctx, batch.MerchantCode, channel)
+		if err != nil {
+			return fee, err
+		}
+		fees[channel] = fee
+		return fee, nil
+	}
+
+	// merchant preference is read once, rows outside channel window are queued or rejected alike
+	queueOutsideWindow := b.calendar.OutOfWindowAction(ctx, batch.MerchantCode) == constants.OutOfWindowQueue
+
+	semaphore := make(chan struct{}, b.concurrency)
+	var waitGroup sync.WaitGroup
+	interrupted := false
+
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		if item.Status != constants.BatchItemQueued {
+			continue
+		}
+
+		semaphore <- struct{}{}
+		if b.inflight.isDraining() {
+			<-semaphore
+			interrupted = true
+			break
+		}
+
+		waitGroup.Go(func() {
+			defer func() { <-semaphore }()
+			b.processItem(loghelper.NewContext(ctx, log.WithField("row", item.RowNumber)), batch, item, feeFor, queueOutsideWindow)
+		})
+	}
+	waitGroup.Wait()
+
+	// queued rows are not failures, batch is completed once it is resumed on next start
+	if interrupted {
+		log.Warn("Batch interrupted by shutdown, remaining rows stay queued for resume")
+		return
+	}
+
+	b.completeBatch(ctx, batch, reserved)
+}
+
+// completeBatch release reservation of reserved rows that did not go through or were scheduled, rows failed before restart hold none
+func (b *batchService) completeBatch(ctx context.Context, batch *entity.TransferBatch, reserved map[int]bool) {
+	log := loghelper.FromContext(ctx)
+
+	var success, failed, releasedRows int
+	var release float64
+	for _, item := range batch.Items {
+		switch item.Status {
+		case constants.BatchItemAccepted:
+			success++
+			continue
+		case constants.BatchItemScheduled:
+			// scheduled transfer debits balance again when it runs
+			success++
+		default:
+			failed++
+		}
+		if reserved[item.RowNumber] {
+			releasedRows++
+			release = amounthelper.RoundCent(release + item.ReservedAmount)
+		}
+	}
+
+	if release > 0 {
+		log.Infof("Release %.2f reserved balance of %d failed rows", release, releasedRows)
+		if err := b.redisService.RefundBalance(ctx, batch.MerchantCode, release); err != nil {
+			log.WithError(err).Errorf("Failed to release reserved balance, redis balance for merchant %s is stale", batch.MerchantCode)
+		}
+	}
+
+	if err := b.batchRepo.Complete(ctx, batch.ID, success, failed); err != nil {
+		log.WithError(err).Error("Failed to complete batch")
+		return
+	}
+
+	log.Infof("Batch completed, %d accepted and %d failed", success, failed)
+}
+
+func (b *batchService) processItem(ctx context.Context, batch *entity.TransferBatch, item *entity.TransferBatchItem, feeFor func(channel string) (entity.FeeSettings, error),
+	queueOutsideWindow bool) {
+	log := loghelper.FromContext(ctx)
+	var request dto.TransferRequest
+	if err := json.Unmarshal([]byte(item.Payload), &request); err != nil {
+		log.WithError(err).Error("Invalid stored batch row")
+		b.updateItem(ctx, item, constants.BatchItemFailed, constants.ErrBadRequest, "")
+		return
+	}
+
+	if executeAt, open := b.calendar.ExecutionTime(request.AdditionalInfo.Channel, time.Now()); !open && queueOutsideWindow {
+		b.scheduleItem(ctx, item, request, batch.MerchantCode, executeAt)
+		return
+	}
+
+	fee, err := feeFor(request.AdditionalInfo.Channel)
+	if err != nil {
+		b.updateItem(ctx, item, constants.BatchItemFailed, constants.ErrDataNotFound, "")
+		return
+	}
+
+	// row is marked before it runs, so restart can tell which rows may have executed
+	if err := b.updateItem(ctx, item, constants.BatchItemSubmitting, "", ""); err != nil {
+		b.updateItem(ctx, item, constants.BatchItemFailed, constants.ErrInternalServerError, "")
+		return
+	}
+
+	externalId := fmt.Sprintf("%s-%d", batch.BatchReference, item.RowNumber)
+	response := b.transferService.TransferReserved(ctx, request, fee, batch.MerchantCode, externalId)
+
+	status := constants.BatchItemFailed
+	if response.ResponseCode == constants.PendingTransfer {
+		status = constants.BatchItemAccepted
+	}
+	b.updateItem(ctx, item, status, response.ResponseCode, response.ReferenceNumber)
+}
+
+// scheduleItem store row outside channel window as single scheduled transfer executed when window opens
+func (b *batchService) scheduleItem(ctx context.Context, item *entity.TransferBatchItem, request dto.TransferRequest, merchantCode string, executeAt time.Time) {
+	log := loghelper.FromContext(ctx)
+	log.Infof("Channel %s is outside operating window, scheduling row for execution at %s", request.AdditionalInfo.Channel, timehelper.FormatTimeToISO7(executeAt))
+
+	scheduled := b.scheduleService.Schedule(ctx, dto.ScheduleTransferRequest{
+		ScheduleReference: request.PartnerReferenceNo,
+		ExecuteAt:         timehelper.FormatTimeToISO7(executeAt),
+		Recurrence:        constants.RecurrenceOnce,
+		Transfer:          request,
+	}, merchantCode)
+
+	if scheduled.ResponseCode != constants.PendingTransfer {
+		log.Warnf("Failed to schedule row with code %s", scheduled.ResponseCode)
+		b.updateItem(ctx, item, constants.BatchItemFailed, scheduled.ResponseCode, "")
+		return
+	}
+	b.updateItem(ctx, item, constants.BatchItemScheduled, constants.PendingTransfer, "")
+}
+
+func (b *batchService) updateItem(ctx context.Context, item *entity.TransferBatchItem, status, responseCode, referenceNumber string) error {
+	item.Status = status
+	item.ResponseCode = responseCode
+	item.ResponseMessage = constants.ResponseMap[responseCode]
+	item.ReferenceNumber = referenceNumber
+	if err := b.batchRepo.UpdateItem(ctx, item); err != nil {
+		loghelper.FromContext(ctx).WithError(err).Error("Failed to update batch row result")
+		return err
+	}
+	return nil
+}
+
+func (b *batchService) checkExistingReferences(ctx context.Context, request dto.BatchTransferRequest, merchantCode string) ([]dto.BatchRowResult, error) {
+	references := make([]string, 0, len(request.Transfers))
+	for _, transfer := range request.Transfers {
+		references = append(references, transfer.PartnerReferenceNo)
+	}
+
+	existing, err := b.transferRepo.FindExistingReferences(ctx, merchantCode, references)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool, len(existing))
+	for _, reference := range existing {
+		used[reference] = true
+	}
+
+	var rowErrors []dto.BatchRowResult
+	for i, transfer := range request.Transfers {
+		if used[transfer.PartnerReferenceNo] {
+			rowErrors = append(rowErrors, dto.BatchRowResult{
+				RowNumber:          i + 1,
+				PartnerReferenceNo: transfer.PartnerReferenceNo,
+				Status:             constants.BatchItemFailed,
+				ResponseCode:       constants.ErrDuplicateReference,
+				ResponseMessage:    constants.ResponseMap[constants.ErrDuplicateReference],
+			})
+		}
+	}
+	return rowErrors, nil
+}
+
+func (b *batchService) validateBatch(request dto.BatchTransferRequest) []dto.BatchRowResult {
+	var rowErrors []dto.BatchRowResult
+	reject := func(row int, reference, reason string) {
+		rowErrors = append(rowErrors, dto.BatchRowResult{
+			RowNumber:          row,
+			PartnerReferenceNo: reference,
+			Status:             constants.BatchItemFailed,
+			ResponseCode:       constants.ErrBadRequest,
+			ResponseMessage:    reason,
+		})
+	}
+
+	if request.BatchReference == "" {
+		reject(0, "", "batch reference is mandatory")
+	}
+
+	if len(request.Transfers) == 0 || len(request.Transfers) > b.maxRows {
+		reject(0, "", fmt.Sprintf("batch must contain between 1 and %d rows", b.maxRows))
+		return rowErrors
+	}
+
+	references := make(map[string]int, len(request.Transfers))
+	for i, transfer := range request.Transfers {
+		row := i + 1
+		if err := validateTransferRow(transfer); err != nil {
+			reject(row, transfer.PartnerReferenceNo, err.Error())
+			continue
+		}
+
+		if previous, ok := references[transfer.PartnerReferenceNo]; ok {
+			reject(row, transfer.PartnerReferenceNo, fmt.Sprintf("partner reference no duplicate with row %d", previous))
+			continue
+		}
+		references[transfer.PartnerReferenceNo] = row
+	}
+
+	return rowErrors
+}
+
+func (b *batchService) buildBatch(ctx context.Context, request dto.BatchTransferRequest, merchantCode string) (*entity.TransferBatch, error) {
+	fees := map[string]entity.FeeSettings{}
+	batch := &entity.TransferBatch{
+		BatchReference: request.BatchReference,
+		MerchantCode:   merchantCode,
+		TotalRows:      len(request.Transfers),
+		Status:         constants.BatchProcessing,
+		CreatedAt:      time.Now(),
+		Items:          make([]entity.TransferBatchItem, 0, len(request.Transfers)),
+	}
+
+	for i, transfer := range request.Transfers {
+		channel := transfer.AdditionalInfo.Channel
+		fee, ok := fees[channel]
+		if !ok {
+			var err error
+			if fee, err = b.transferService.GetFeeSetting(ctx, merchantCode, channel); err != nil {
+				return nil, err
+			}
+			fees[channel] = fee
+		}
+
+		payload, err := json.Marshal(transfer)
+		if err != nil {
+			return nil, err
+		}
+
+		amount, _ := parseAmount(transfer.Amount.Value)
+		reserved := amounthelper.RoundCent(amount + fee.TotalCharge)
+		batch.ReservedAmount = amounthelper.RoundCent(batch.ReservedAmount + reserved)
+		batch.Items = append(batch.Items, entity.TransferBatchItem{
+			RowNumber:          i + 1,
+			PartnerReferenceNo: transfer.PartnerReferenceNo,
+			Payload:            string(payload),
+			ReservedAmount:     reserved,
+			Status:             constants.BatchItemQueued,
+			LastUpdated:        time.Now(),
+		})
+	}
+
+	return batch, nil
+}
+
+func (b *batchService) handleBatchResponse(responseCode, batchReference string, batch *entity.TransferBatch) dto.BatchTransferResponse {
+	response := dto.BatchTransferResponse{
+		ResponseCode:    responseCode,
+		ResponseMessage: constants.ResponseMap[responseCode],
+		BatchReference:  batchReference,
+		TransactionDate: timehelper.FormatTimeToISO7(time.Now()),
+		Rows:            []dto.BatchRowResult{},
+		AdditionalInfo:  map[string]string{},
+	}
+
+	if batch != nil {
+		response.Status = batch.Status
+		response.TotalRows = batch.TotalRows
+		response.SuccessCount = batch.SuccessCount
+		response.FailedCount = batch.FailedCount
+		response.ReservedAmount = strconv.FormatFloat(batch.ReservedAmount, 'f', 2, 64)
+	}
+	return response
+}
+
+func validateTransferRow(request dto.TransferRequest) error {
+	var missing []string
+	if request.PartnerReferenceNo == "" {
+		missing = append(missing, "partnerReferenceNo")
+	}
+	if request.BeneficiaryAccountNumber == "" {
+		missing = append(missing, "beneficiaryAccountNumber")
+	}
+	if request.BeneficiaryBankCode == "" {
+		missing = append(missing, "beneficiaryBankCode")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("mandatory field missing: %s", strings.Join(missing, ", "))
+	}
+
+	if !constants.TransferChannels[request.AdditionalInfo.Channel] {
+		return fmt.Errorf("unsupported channel %s", request.AdditionalInfo.Channel)
+	}
+
+	if constants.DedicatedChannels[request.AdditionalInfo.Channel] {
+		return fmt.Errorf("channel %s is only accepted through its own endpoint", request.AdditionalInfo.Channel)
+	}
+
+	if err := validateClearingInfo(request); err != nil {
+		return err
+	}
+
+	amount, err := parseAmount(request.Amount.Value)
+	if err != nil {
+		return err
+	}
+
+	if amount <= 0 {
+		return fmt.Errorf("amount must be greater than zero")
+	}
+	return nil
+}
+
+// ParseBatchCSV read transfer rows from csv with header named after transfer request json field
+func ParseBatchCSV(reader io.Reader) ([]dto.TransferRequest, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	for _, required := range []string{"partnerReferenceNo", "beneficiaryAccountNumber", "beneficiaryBankCode", "amount", "channel"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv column %s is mandatory", required)
+		}
+	}
+
+	var transfers []dto.TransferRequest
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv row %d: %w", len(transfers)+1, err)
+		}
+
+		value := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		currency := value("currency")
+		if currency == "" {
+			currency = "IDR"
+		}
+
+		// clearing columns are only read for rows carrying them, address lines are separated by |
+		var clearingInfo *dto.TransferClearingInfo
+		if value("residentStatus") != "" || value("beneficiaryType") != "" || value("datiII") != "" {
+			clearingInfo = &dto.TransferClearingInfo{
+				ResidentStatus:            value("residentStatus"),
+				BeneficiaryType:           value("beneficiaryType"),
+				BeneficiaryBankBranchName: value("beneficiaryBankBranchName"),
+				BeneficiaryBankCity:       value("beneficiaryBankCity"),
+				BeneficiaryBankAddress:    value("beneficiaryBankAddress"),
+				DatiII:                    value("datiII"),
+			}
+			if address := value("beneficiaryAddress"); address != "" {
+				clearingInfo.BeneficiaryAddress = strings.Split(address, "|")
+			}
+		}
+
+		transfers = append(transfers, dto.TransferRequest{
+			PartnerReferenceNo:       value("partnerReferenceNo"),
+			CustomerNumber:           value("customerNumber"),
+			AccountType:              value("accountType"),
+			BeneficiaryAccountNumber: value("beneficiaryAccountNumber"),
+			BeneficiaryBankCode:      value("beneficiaryBankCode"),
+			Amount:                   dto.TransferAmountData{Value: value("amount"), Currency: currency},
+			AdditionalInfo: dto.TransferRequestInfo{
+				TransactionDate:   value("transactionDate"),
+				CustomerReference: value("customerReference"),
+				Channel:           value("channel"),
+				Remarks:           value("remarks"),
+				Email:             value("email"),
+				Address:           value("address"),
+				Citizenship:       value("citizenship"),
+				TransferPurpose:   value("transferPurpose"),
+				TransferActivity:  value("transferActivity"),
+				CustomerType:      value("customerType"),
+			},
+			ClearingInfo: clearingInfo,
+		})
+	}
+
+	return transfers, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"testing"
+)
+
+func testTransferRow(channel string) dto.TransferRequest {
+	return dto.TransferRequest{
+		PartnerReferenceNo:       "MRC-20250101-0001",
+		BeneficiaryAccountNumber: "1234567890",
+		BeneficiaryBankCode:      constants.BankCodeBCA,
+		Amount:                   dto.TransferAmountData{Value: "150000.00", Currency: "IDR"},
+		AdditionalInfo:           dto.TransferRequestInfo{Channel: channel},
+	}
+}
+
+func TestValidateTransferRowChannel(t *testing.T) {
+	tests := []struct {
+		channel string
+		valid   bool
+	}{
+		{constants.ChannelOnline, true},
+		{constants.ChannelBifast, true},
+		{constants.ChannelDeposit, false},
+		{constants.ChannelVA, false},
+		{constants.ChannelWallet, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.channel, func(t *testing.T) {
+			if err := validateTransferRow(testTransferRow(tt.channel)); (err == nil) != tt.valid {
+				t.Errorf("validateTransferRow(%s) error = %v, want valid %t", tt.channel, err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	cutoffLayout       = "15:04"
+	calendarLookAhead  = 31
+	holidayLoadBackDay = -1
+)
+
+// defaultCutoffs apply when channel has no row in channel_cutoff, channel absent here is open all the time
+var defaultCutoffs = map[string]entity.ChannelCutoff{
+	constants.ChannelSknbi: {Channel: constants.ChannelSknbi, OpenTime: "08:00", CutoffTime: "15:00", BusinessDaysOnly: true},
+	constants.ChannelRtgs:  {Channel: constants.ChannelRtgs, OpenTime: "08:00", CutoffTime: "16:00", BusinessDaysOnly: true},
+}
+
+type CalendarService interface {
+	LoadCalendar(ctx context.Context) error
+	ExecutionTime(channel string, at time.Time) (time.Time, bool)
+	OutOfWindowAction(ctx context.Context, merchantCode string) string
+	AddHoliday(ctx context.Context, request dto.HolidayRequest) dto.CalendarResponse
+	ListHolidays(ctx context.Context, year int) dto.CalendarResponse
+}
+
+type calendarService struct {
+	rwMutex      sync.RWMutex
+	calendarRepo repository.CalendarRepository
+	holidays     map[string]string
+	cutoffs      map[string]entity.ChannelCutoff
+}
+
+func NewCalendarService(calendarRepo repository.CalendarRepository) CalendarService {
+	return &calendarService{
+		calendarRepo: calendarRepo,
+	}
+}
+
+// LoadCalendar cache upcoming holidays and channel cutoff to memory
+func (c *calendarService) LoadCalendar(ctx context.Context) error {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "calendar_service",
+		"operation": "load_calendar",
+	})
+
+	log.Info("Collect holidays and channel cutoff from database")
+	today := c.dateOf(time.Now())
+	holidays, err := c.calendarRepo.FindHolidays(ctx, today.AddDate(0, 0, holidayLoadBackDay), today.AddDate(2, 0, 0))
+	if err != nil {
+		log.WithError(err).Error("Failed to collect holidays from database")
+		return err
+	}
+
+	cutoffs, err := c.calendarRepo.FindCutoffs(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to collect channel cutoff from database")
+		return err
+	}
+
+	cutoffCache := make(map[string]entity.ChannelCutoff)
+	for channel, cutoff := range defaultCutoffs {
+		cutoffCache[channel] = cutoff
+	}
+	for _, cutoff := range cutoffs {
+		if _, err := time.Parse(cutoffLayout, cutoff.OpenTime); err != nil {
+			return fmt.Errorf("invalid open time of channel %s: %w", cutoff.Channel, err)
+		}
+		if _, err := time.Parse(cutoffLayout, cutoff.CutoffTime); err != nil {
+			return fmt.Errorf("invalid cutoff time of channel %s: %w", cutoff.Channel, err)
+		}
+		cutoffCache[cutoff.Channel] = cutoff
+	}
+
+	holidayCache := make(map[string]string)
+	for _, holiday := range holidays {
+		holidayCache[holiday.HolidayDate.Format(time.DateOnly)] = holiday.Description
+	}
+
+	log.Infof("Cache %d holidays and %d channel cutoff to memory", len(holidayCache), len(cutoffCache))
+	c.rwMutex.Lock()
+	c.holidays = holidayCache
+	c.cutoffs = cutoffCache
+	c.rwMutex.Unlock()
+	return nil
+}
+
+// ExecutionTime returns at when channel window is open, otherwise the opening of next business window
+func (c *calendarService) ExecutionTime(channel string, at time.Time) (time.Time, bool) {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	cutoff, ok := c.cutoffs[channel]
+	if !ok {
+		return at, true
+	}
+
+	open, _ := time.Parse(cutoffLayout, cutoff.OpenTime)
+	closed, _ := time.Parse(cutoffLayout, cutoff.CutoffTime)
+	local := at.In(timehelper.WIB)
+	day := c.dateOf(local)
+
+	for i := 0; i < calendarLookAhead; i++ {
+		if !cutoff.BusinessDaysOnly || c.isBusinessDay(day) {
+			windowOpen := day.Add(time.Duration(open.Hour())*time.Hour + time.Duration(open.Minute())*time.Minute)
+			windowClose := day.Add(time.Duration(closed.Hour())*time.Hour + time.Duration(closed.Minute())*time.Minute)
+
+			if local.Before(windowOpen) {
+				return windowOpen, false
+			}
+			if local.Before(windowClose) {
+				return at, true
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	// calendar misconfigured, do not block transfer forever
+	return at, true
+}
+
+// OutOfWindowAction return merchant preference for transfer outside window, reject by default
+func (c *calendarService) OutOfWindowAction(ctx context.Context, merchantCode string) string {
+	log := loghelper.FromContext(ctx)
+	preference, err := c.calendarRepo.FindPreference(ctx, merchantCode)
+	if err != nil {
+		log.WithError(err).Error("Failed to get merchant transfer preference, fallback to reject")
+		return constants.OutOfWindowReject
+	}
+
+	if preference == nil || preference.OutOfWindowAction != constants.OutOfWindowQueue {
+		return constants.OutOfWindowReject
+	}
+	return constants.OutOfWindowQueue
+}
+
+func (c *calendarService) AddHoliday(ctx context.Context, request dto.HolidayRequest) dto.CalendarResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "calendar_service",
+		"operation": "add_holiday",
+		"date":      request.Date,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	date, err := time.ParseInLocation(time.DateOnly, request.Date, timehelper.WIB)
+	if err != nil || request.Description == "" {
+		log.Warn("Valid date and description are mandatory")
+		return c.handleCalendarResponse(constants.ErrBadRequest, nil)
+	}
+
+	log.Info("Persist holiday")
+	if err := c.calendarRepo.SaveHoliday(ctx, &entity.Holiday{
+		HolidayDate: date,
+		Description: request.Description,
+		CreatedAt:   time.Now(),
+	}); err != nil {
+		log.WithError(err).Error("Failed to persist holiday")
+		return c.handleCalendarResponse(constants.ErrInternalServerError, nil)
+	}
+
+	// refresh cache so new holiday applies immediately
+	if err := c.LoadCalendar(ctx); err != nil {
+		log.WithError(err).Error("Failed to reload calendar")
+		return c.handleCalendarResponse(constants.ErrInternalServerError, nil)
+	}
+
+	return c.handleCalendarResponse(constants.TransferSuccess, []entity.Holiday{{HolidayDate: date, Description: request.Description}})
+}
+
+func (c *calendarService) ListHolidays(ctx context.Context, year int) dto.CalendarResponse {
+	log := loghelper.FromContext(ctx)
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, timehelper.WIB)
+	holidays, err := c.calendarRepo.FindHolidays(ctx, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch holidays")
+		return c.handleCalendarResponse(constants.ErrInternalServerError, nil)
+	}
+	return c.handleCalendarResponse(constants.TransferSuccess, holidays)
+}
+
+func (c *calendarService) isBusinessDay(day time.Time) bool {
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := c.holidays[day.Format(time.DateOnly)]
+	return !holiday
+}
+
+func (c *calendarService) dateOf(t time.Time) time.Time {
+	local := t.In(timehelper.WIB)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, timehelper.WIB)
+}
+
+func (c *calendarService) handleCalendarResponse(responseCode string, holidays []entity.Holiday) dto.CalendarResponse {
+	response := dto.CalendarResponse{
+		ResponseCode:    responseCode,
+		ResponseMessage: constants.ResponseMap[responseCode],
+		Holidays:        []dto.HolidayInfo{},
+		Cutoffs:         []dto.ChannelWindow{},
+	}
+
+	for _, holiday := range holidays {
+		response.Holidays = append(response.Holidays, dto.HolidayInfo{
+			Date:        holiday.HolidayDate.In(timehelper.WIB).Format(time.DateOnly),
+			Description: holiday.Description,
+		})
+	}
+
+	c.rwMutex.RLock()
+	for _, cutoff := range c.cutoffs {
+		response.Cutoffs = append(response.Cutoffs, dto.ChannelWindow{
+			Channel:          cutoff.Channel,
+			OpenTime:         cutoff.OpenTime,
+			CutoffTime:       cutoff.CutoffTime,
+			BusinessDaysOnly: cutoff.BusinessDaysOnly,
+		})
+	}
+	c.rwMutex.RUnlock()
+
+	sort.Slice(response.Cutoffs, func(i, j int) bool { return response.Cutoffs[i].Channel < response.Cutoffs[j].Channel })
+	return response
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/timehelper"
+	"testing"
+	"time"
+)
+
+// newTestCalendar cache default cutoff, an all week bifast window and new year holiday of 2025 without database
+func newTestCalendar() *calendarService {
+	cutoffs := map[string]entity.ChannelCutoff{
+		constants.ChannelBifast: {Channel: constants.ChannelBifast, OpenTime: "06:00", CutoffTime: "22:00", BusinessDaysOnly: false},
+	}
+	for channel, cutoff := range defaultCutoffs {
+		cutoffs[channel] = cutoff
+	}
+
+	return &calendarService{
+		holidays: map[string]string{"2025-01-01": "Tahun Baru Masehi"},
+		cutoffs:  cutoffs,
+	}
+}
+
+func wib(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, timehelper.WIB)
+}
+
+func TestExecutionTime(t *testing.T) {
+	calendar := newTestCalendar()
+	tests := []struct {
+		name      string
+		channel   string
+		at        time.Time
+		executeAt time.Time
+		open      bool
+	}{
+		{"channel without window is always open", constants.ChannelOnline, wib(2025, time.January, 4, 23, 0), wib(2025, time.January, 4, 23, 0), true},
+		{"within skn window", constants.ChannelSknbi, wib(2025, time.January, 2, 10, 0), wib(2025, time.January, 2, 10, 0), true},
+		{"before skn opens", constants.ChannelSknbi, wib(2025, time.January, 2, 7, 30), wib(2025, time.January, 2, 8, 0), false},
+		{"skn cutoff is exclusive", constants.ChannelSknbi, wib(2025, time.January, 2, 15, 0), wib(2025, time.January, 3, 8, 0), false},
+		{"rtgs open after skn cutoff", constants.ChannelRtgs, wib(2025, time.January, 2, 15, 30), wib(2025, time.January, 2, 15, 30), true},
+		{"friday after cutoff moves to monday", constants.ChannelSknbi, wib(2025, time.January, 3, 16, 0), wib(2025, time.January, 6, 8, 0), false},
+		{"saturday moves to monday", constants.ChannelRtgs, wib(2025, time.January, 4, 10, 0), wib(2025, time.January, 6, 8, 0), false},
+		{"holiday is skipped", constants.ChannelSknbi, wib(2025, time.January, 1, 10, 0), wib(2025, time.January, 2, 8, 0), false},
+		{"after cutoff before holiday", constants.ChannelSknbi, wib(2024, time.December, 31, 15, 30), wib(2025, time.January, 2, 8, 0), false},
+		{"window applies on weekend when not business days only", constants.ChannelBifast, wib(2025, time.January, 4, 23, 0), wib(2025, time.January, 5, 6, 0), false},
+		{"utc time is evaluated in wib", constants.ChannelSknbi, time.Date(2025, time.January, 2, 2, 0, 0, 0, time.UTC), time.Date(2025, time.January, 2, 2, 0, 0, 0, time.UTC), true},
+		{"utc time before window opens in wib", constants.ChannelSknbi, time.Date(2025, time.January, 2, 0, 30, 0, 0, time.UTC), wib(2025, time.January, 2, 8, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executeAt, open := calendar.ExecutionTime(tt.channel, tt.at)
+			if open != tt.open || !executeAt.Equal(tt.executeAt) {
+				t.Errorf("ExecutionTime(%s, %s) = %s, %t, want %s, %t", tt.channel, timehelper.FormatTimeToISO7(tt.at),
+					timehelper.FormatTimeToISO7(executeAt), open, timehelper.FormatTimeToISO7(tt.executeAt), tt.open)
+			}
+		})
+	}
+}
+
+func TestExecutionTimeSkipsHolidayAfterWeekend(t *testing.T) {
+	calendar := newTestCalendar()
+	calendar.holidays["2025-01-06"] = "Cuti Bersama"
+
+	executeAt, open := calendar.ExecutionTime(constants.ChannelSknbi, wib(2025, time.January, 3, 16, 0))
+	if open || !executeAt.Equal(wib(2025, time.January, 7, 8, 0)) {
+		t.Errorf("got %s, %t, want next window on tuesday", timehelper.FormatTimeToISO7(executeAt), open)
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"fmt"
+	"strings"
+)
+
+const (
+	clearingAddressLines  = 3
+	clearingAddressLength = 35
+	clearingDatiIILength  = 4
+)
+
+var (
+	clearingResidentStatus  = map[string]bool{"01": true, "02": true}
+	clearingBeneficiaryType = map[string]bool{"1": true, "2": true, "3": true}
+)
+
+// validateClearingInfo check beneficiary data SKN and RTGS clearing rejects without, other channels need none
+func validateClearingInfo(request dto.TransferRequest) error {
+	channel := request.AdditionalInfo.Channel
+	if channel != constants.ChannelSknbi && channel != constants.ChannelRtgs {
+		return nil
+	}
+
+	clearing := request.ClearingInfo
+	if clearing == nil {
+		return fmt.Errorf("clearingInfo is mandatory for %s", channel)
+	}
+
+	var missing []string
+	mandatory := map[string]string{
+		"residentStatus":            clearing.ResidentStatus,
+		"beneficiaryType":           clearing.BeneficiaryType,
+		"beneficiaryBankBranchName": clearing.BeneficiaryBankBranchName,
+		"beneficiaryBankCity":       clearing.BeneficiaryBankCity,
+		"datiII":                    clearing.DatiII,
+	}
+	if channel == constants.ChannelRtgs {
+		mandatory["beneficiaryBankAddress"] = clearing.BeneficiaryBankAddress
+	}
+	for _, field := range []string{"residentStatus", "beneficiaryType", "beneficiaryBankBranchName", "beneficiaryBankCity", "beneficiaryBankAddress", "datiII"} {
+		if value, ok := mandatory[field]; ok && strings.TrimSpace(value) == "" {
+			missing = append(missing, "clearingInfo."+field)
+		}
+	}
+	if len(clearing.BeneficiaryAddress) == 0 || strings.TrimSpace(clearing.BeneficiaryAddress[0]) == "" {
+		missing = append(missing, "clearingInfo.beneficiaryAddress")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("mandatory field missing for %s: %s", channel, strings.Join(missing, ", "))
+	}
+
+	if !clearingResidentStatus[clearing.ResidentStatus] {
+		return fmt.Errorf("invalid clearingInfo.residentStatus %s, must be 01 or 02", clearing.ResidentStatus)
+	}
+	if !clearingBeneficiaryType[clearing.BeneficiaryType] {
+		return fmt.Errorf("invalid clearingInfo.beneficiaryType %s, must be 1, 2 or 3", clearing.BeneficiaryType)
+	}
+	if len(clearing.BeneficiaryAddress) > clearingAddressLines {
+		return fmt.Errorf("clearingInfo.beneficiaryAddress allows at most %d lines", clearingAddressLines)
+	}
+	for i, line := range clearing.BeneficiaryAddress {
+		if len(line) > clearingAddressLength {
+			return fmt.Errorf("clearingInfo.beneficiaryAddress line %d exceeds %d characters", i+1, clearingAddressLength)
+		}
+	}
+	if len(clearing.DatiII) != clearingDatiIILength || strings.Trim(clearing.DatiII, "0123456789") != "" {
+		return fmt.Errorf("invalid clearingInfo.datiII %s, must be %d digits", clearing.DatiII, clearingDatiIILength)
+	}
+	return nil
+}
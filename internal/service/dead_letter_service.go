@@ -0,0 +1,142 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/kafkahelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/manager"
+	"briefcash-transfer/internal/protobuf"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrReplayNotEligible = errors.New("dead letter is not eligible for replay")
+
+type DeadLetterService interface {
+	CheckReplay(ctx context.Context, letter kafkahelper.DeadLetter) error
+	Replay(ctx context.Context, letter kafkahelper.DeadLetter) error
+}
+
+type deadLetterService struct {
+	transferRepo  repository.TransferRepository
+	db            *gorm.DB
+	kafkaProducer *kafkahelper.KafkaProducer
+}
+
+func NewDeadLetterService(transferRepo repository.TransferRepository, db *gorm.DB, kafkaProducer *kafkahelper.KafkaProducer) DeadLetterService {
+	return &deadLetterService{transferRepo, db, kafkaProducer}
+}
+
+// CheckReplay return reason dead letter may not be replayed, nil when it may
+func (d *deadLetterService) CheckReplay(ctx context.Context, letter kafkahelper.DeadLetter) error {
+	_, err := d.eligibleTransfer(ctx, d.transferRepo, letter)
+	return err
+}
+
+// Replay publish dead letter to its original topic. Transfer of instruction is claimed first, moved to pending under row
+// lock and committed before anything is sent, so a second replay of the same dead letter is refused instead of paying twice.
+// Claim is released back to created when send fails. Only created transfer is replayable, see eligibleTransfer
+func (d *deadLetterService) Replay(ctx context.Context, letter kafkahelper.DeadLetter) error {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":          "dead_letter_service",
+		"operation":        "replay",
+		"topic":            letter.Topic,
+		"dead_letter":      fmt.Sprintf("%d/%d", letter.DeadLetterPartition, letter.DeadLetterOffset),
+		"dead_letter_from": letter.Source,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	transfer, err := d.claimTransfer(ctx, letter)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Replay dead letter to original topic")
+	if err := d.kafkaProducer.Replay(ctx, letter); err != nil {
+		if transfer != nil {
+			if releaseErr := d.releaseTransfer(ctx, transfer); releaseErr != nil {
+				log.WithError(releaseErr).Errorf("Failed to release replay claim, transfer %s was claimed but not sent and must be checked manually", transfer.PartnerReferenceNo)
+			}
+		}
+		return fmt.Errorf("failed to replay dead letter, with error: %w", err)
+	}
+	return nil
+}
+
+// claimTransfer move transfer of dead lettered instruction to pending and commit, consumed dead letter has no transfer to claim
+func (d *deadLetterService) claimTransfer(ctx context.Context, letter kafkahelper.DeadLetter) (*entity.Transaction, error) {
+	var transfer *entity.Transaction
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		transferTx := d.transferRepo.WithTransaction(tx)
+		eligible, err := d.eligibleTransfer(ctx, transferTx, letter)
+		if err != nil || eligible == nil {
+			return err
+		}
+
+		sm := manager.NewTransferStateManager(transferTx)
+		if err := sm.Transition(ctx, eligible, constants.StatusPending, "instruction replay claimed from dead letter topic", constants.ActorDeadLetterReplay); err != nil {
+			return err
+		}
+		transfer = eligible
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// releaseTransfer move claimed transfer back to created after failed send, so replay may be tried again.
+// Transfer moved on since the claim, e.g. by partner status, is stale and left as it is
+func (d *deadLetterService) releaseTransfer(ctx context.Context, transfer *entity.Transaction) error {
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		sm := manager.NewTransferStateManager(d.transferRepo.WithTransaction(tx))
+		return sm.Transition(ctx, transfer, constants.StatusCreated, "replay of dead letter failed, claim released", constants.ActorDeadLetterReplay)
+	})
+}
+
+// eligibleTransfer lock transfer of dead lettered instruction, consumed dead letter has no transfer and is left to idempotency of its consumer.
+// Only created transfer is eligible: transfer of instruction that failed to publish is failed publish and already refunded,
+// it is not debited again on replay and merchant has to submit a new transfer instead
+func (d *deadLetterService) eligibleTransfer(ctx context.Context, transferRepo repository.TransferRepository, letter kafkahelper.DeadLetter) (*entity.Transaction, error) {
+	if letter.Topic == "" {
+		return nil, fmt.Errorf("%w: original topic is unknown", ErrReplayNotEligible)
+	}
+
+	switch letter.Source {
+	case kafkahelper.DeadLetterSourceConsume:
+		return nil, nil
+	case kafkahelper.DeadLetterSourceProduce:
+	default:
+		return nil, fmt.Errorf("%w: unknown source %q", ErrReplayNotEligible, letter.Source)
+	}
+
+	message, err := protobuf.DecodeMessage(letter.Value)
+	instruction, ok := message.(*protobuf.TransferRequest)
+	if err != nil || !ok || instruction.GetPartnerRefNo() == "" || instruction.GetMerchantCode() == "" {
+		return nil, fmt.Errorf("%w: payload is not transfer instruction", ErrReplayNotEligible)
+	}
+
+	transfer, err := transferRepo.FindForUpdate(ctx, instruction.GetMerchantCode(), instruction.GetPartnerRefNo())
+	if err != nil {
+		return nil, err
+	}
+	if transfer == nil {
+		return nil, fmt.Errorf("%w: transfer %s of merchant %s not found", ErrReplayNotEligible, instruction.GetPartnerRefNo(), instruction.GetMerchantCode())
+	}
+	if reference := instruction.GetReferenceNumber(); reference != "" && (transfer.SystemReferenceNo == nil || *transfer.SystemReferenceNo != reference) {
+		return nil, fmt.Errorf("%w: instruction %s is not of transfer %s", ErrReplayNotEligible, reference, transfer.PartnerReferenceNo)
+	}
+
+	// only created transfer is still debited and never reached partner, refunded or sent one must not be paid again
+	if transfer.Status != constants.StatusCreated {
+		return nil, fmt.Errorf("%w: transfer %s is %s, want %s", ErrReplayNotEligible, transfer.PartnerReferenceNo, transfer.Status, constants.StatusCreated)
+	}
+	return transfer, nil
+}
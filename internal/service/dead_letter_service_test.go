@@ -0,0 +1,96 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/kafkahelper"
+	"briefcash-transfer/internal/protobuf"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// stubTransferRepository return transfer of merchant reference, methods not used by dead letter replay are left unimplemented
+type stubTransferRepository struct {
+	repository.TransferRepository
+	transfer *entity.Transaction
+	err      error
+}
+
+func (s *stubTransferRepository) FindForUpdate(ctx context.Context, merchantCode, partnerReferenceNo string) (*entity.Transaction, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.transfer == nil || s.transfer.MerchantCode != merchantCode || s.transfer.PartnerReferenceNo != partnerReferenceNo {
+		return nil, nil
+	}
+	return s.transfer, nil
+}
+
+func instructionLetter(t *testing.T, message proto.Message) kafkahelper.DeadLetter {
+	t.Helper()
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatalf("failed to marshal dead letter payload: %v", err)
+	}
+	return kafkahelper.DeadLetter{Source: kafkahelper.DeadLetterSourceProduce, Topic: "transfer.instruction", Value: payload}
+}
+
+func TestCheckReplay(t *testing.T) {
+	reference := "TRF-0001"
+	instruction := &protobuf.TransferRequest{PartnerRefNo: "MRC-0001", MerchantCode: "MRC001", ReferenceNumber: reference}
+	transferIn := func(status string) *stubTransferRepository {
+		return &stubTransferRepository{transfer: &entity.Transaction{MerchantCode: "MRC001", PartnerReferenceNo: "MRC-0001", SystemReferenceNo: &reference, Status: status}}
+	}
+	errDatabase := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		repo    *stubTransferRepository
+		letter  func(t *testing.T) kafkahelper.DeadLetter
+		wantErr error
+	}{
+		{"created transfer", transferIn(constants.StatusCreated), func(t *testing.T) kafkahelper.DeadLetter { return instructionLetter(t, instruction) }, nil},
+		{"failed publish transfer is already refunded", transferIn(constants.StatusFailedPublish), func(t *testing.T) kafkahelper.DeadLetter { return instructionLetter(t, instruction) }, ErrReplayNotEligible},
+		{"pending transfer is claimed or sent", transferIn(constants.StatusPending), func(t *testing.T) kafkahelper.DeadLetter { return instructionLetter(t, instruction) }, ErrReplayNotEligible},
+		{"refunded transfer", transferIn(constants.StatusRefunded), func(t *testing.T) kafkahelper.DeadLetter { return instructionLetter(t, instruction) }, ErrReplayNotEligible},
+		{"transfer not found", &stubTransferRepository{}, func(t *testing.T) kafkahelper.DeadLetter { return instructionLetter(t, instruction) }, ErrReplayNotEligible},
+		{"instruction of other transfer", transferIn(constants.StatusCreated), func(t *testing.T) kafkahelper.DeadLetter {
+			return instructionLetter(t, &protobuf.TransferRequest{PartnerRefNo: "MRC-0001", MerchantCode: "MRC001", ReferenceNumber: "TRF-0002"})
+		}, ErrReplayNotEligible},
+		{"instruction without merchant", transferIn(constants.StatusCreated), func(t *testing.T) kafkahelper.DeadLetter {
+			return instructionLetter(t, &protobuf.TransferRequest{PartnerRefNo: "MRC-0001"})
+		}, ErrReplayNotEligible},
+		{"produced result is not instruction", transferIn(constants.StatusCreated), func(t *testing.T) kafkahelper.DeadLetter {
+			return instructionLetter(t, &protobuf.TransferResult{Envelope: &protobuf.Envelope{MessageType: protobuf.MessageTypeTransferResult}})
+		}, ErrReplayNotEligible},
+		{"original topic unknown", transferIn(constants.StatusCreated), func(t *testing.T) kafkahelper.DeadLetter {
+			letter := instructionLetter(t, instruction)
+			letter.Topic = ""
+			return letter
+		}, ErrReplayNotEligible},
+		{"unknown source", transferIn(constants.StatusCreated), func(t *testing.T) kafkahelper.DeadLetter {
+			letter := instructionLetter(t, instruction)
+			letter.Source = "unknown"
+			return letter
+		}, ErrReplayNotEligible},
+		{"consumed dead letter has no transfer", &stubTransferRepository{err: errDatabase}, func(t *testing.T) kafkahelper.DeadLetter {
+			letter := instructionLetter(t, instruction)
+			letter.Source = kafkahelper.DeadLetterSourceConsume
+			return letter
+		}, nil},
+		{"database error", &stubTransferRepository{err: errDatabase}, func(t *testing.T) kafkahelper.DeadLetter { return instructionLetter(t, instruction) }, errDatabase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewDeadLetterService(tt.repo, nil, nil)
+			if err := svc.CheckReplay(testContext(), tt.letter(t)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckReplay() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
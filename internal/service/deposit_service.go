@@ -0,0 +1,209 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/amounthelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/manager"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var errDuplicateDeposit = errors.New("deposit reference already credited")
+
+type DepositService interface {
+	Deposit(ctx context.Context, request dto.DepositRequest) dto.DepositResponse
+}
+
+type depositService struct {
+	depositRepo  repository.DepositRepository
+	ledgerRepo   repository.LedgerRepository
+	journalRepo  repository.JournalRepository
+	merchantRepo repository.BalanceRepository
+	redisService TransferRedisService
+	db           *gorm.DB
+}
+
+func NewDepositService(depositRepo repository.DepositRepository, ledgerRepo repository.LedgerRepository, journalRepo repository.JournalRepository,
+	merchantRepo repository.BalanceRepository, redisService TransferRedisService, db *gorm.DB) DepositService {
+	return &depositService{depositRepo, ledgerRepo, journalRepo, merchantRepo, redisService, db}
+}
+
+func (d *depositService) Deposit(ctx context.Context, request dto.DepositRequest) dto.DepositResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":           "deposit_service",
+		"operation":         "credit_deposit",
+		"deposit_reference": request.DepositReference,
+		"merchant":          request.MerchantCode,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	// validate deposit instruction
+	log.Info("Validating deposit request")
+	deposit, err := d.buildDeposit(request)
+	if err != nil {
+		log.WithError(err).Warn("Invalid deposit request")
+		return d.handleDepositResponse(constants.ErrBadRequest, request, "")
+	}
+
+	// credit balance, write account statement and record deposit reference in single transaction
+	log.Info("Persist deposit, ledger, and credited balance to database")
+	balance, err := d.PersistDeposit(ctx, deposit)
+	if errors.Is(err, errDuplicateDeposit) {
+		return d.handleDepositResponse(d.duplicateResponseCode(ctx, deposit), request, "")
+	}
+
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		log.WithError(err).Warn("Merchant account not found")
+		return d.handleDepositResponse(constants.ErrBalanceNotAvailable, request, "")
+	}
+
+	if err != nil {
+		log.WithError(err).Error("Failed to persist deposit into database")
+		return d.handleDepositResponse(constants.ErrInternalServerError, request, "")
+	}
+
+	// follow committed balance in redis, database remain the source of truth when it still fails after retry
+	log.Info("Credit merchant balance in redis")
+	if err := d.redisService.CreditBalance(ctx, deposit.MerchantCode, deposit.Amount, balance); err != nil {
+		log.WithError(err).Errorf("Redis balance for merchant %s is stale, database balance is %.2f, reload merchant balance", deposit.MerchantCode, balance)
+	}
+
+	return d.handleDepositResponse(constants.TransferSuccess, request, strconv.FormatFloat(balance, 'f', 2, 64))
+}
+
+func (d *depositService) PersistDeposit(ctx context.Context, deposit *entity.Deposit) (float64, error) {
+	var balance float64
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		depositTx := d.depositRepo.WithTransaction(tx)
+		ledgerTx := d.ledgerRepo.WithTransaction(tx)
+		accountTx := d.merchantRepo.WithTransaction(tx)
+		journalTx := d.journalRepo.WithTransaction(tx)
+
+		pm := manager.NewTransferPersistenceManager(ledgerTx, nil, nil, accountTx)
+		jm := manager.NewJournalManager(journalTx)
+
+		// guard idempotency on deposit reference
+		created, err := depositTx.Save(ctx, deposit)
+		if err != nil {
+			return err
+		}
+
+		if !created {
+			return errDuplicateDeposit
+		}
+
+		// credit merchant balance
+		newBalance, err := pm.CreditMerchant(ctx, deposit.MerchantCode, deposit.Amount)
+		if err != nil {
+			return err
+		}
+
+		// save history top up
+		if err := pm.CreateDepositLedger(ctx, deposit, newBalance); err != nil {
+			return err
+		}
+
+		// book double entry journal
+		if err := jm.PostDepositJournal(ctx, deposit); err != nil {
+			return err
+		}
+
+		balance = newBalance
+		return nil
+	})
+	return balance, err
+}
+
+// duplicateResponseCode report success only when deposit replays stored one exactly, same reference for other merchant or amount is a conflict
+func (d *depositService) duplicateResponseCode(ctx context.Context, deposit *entity.Deposit) string {
+	log := loghelper.FromContext(ctx)
+	stored, err := d.depositRepo.FindByReference(ctx, deposit.DepositReference)
+	if err != nil {
+		log.WithError(err).Error("Failed to get credited deposit")
+		return constants.ErrInternalServerError
+	}
+
+	if stored == nil {
+		log.Error("Credited deposit reference is not found")
+		return constants.ErrInternalServerError
+	}
+
+	if stored.MerchantCode != deposit.MerchantCode || amounthelper.ToCent(stored.Amount) != amounthelper.ToCent(deposit.Amount) || stored.Currency != deposit.Currency {
+		log.Warnf("Deposit reference already credited %.2f %s to merchant %s, rejecting %.2f %s", stored.Amount, stored.Currency, stored.MerchantCode,
+			deposit.Amount, deposit.Currency)
+		return constants.ErrDuplicateReference
+	}
+
+	log.Info("Deposit reference already credited, skipping")
+	return constants.TransferSuccess
+}
+
+func (d *depositService) buildDeposit(request dto.DepositRequest) (*entity.Deposit, error) {
+	if request.DepositReference == "" || request.MerchantCode == "" {
+		return nil, fmt.Errorf("deposit reference and merchant code are mandatory")
+	}
+
+	if request.Source != constants.DepositSourceVA && request.Source != constants.DepositSourceManual {
+		return nil, fmt.Errorf("unsupported deposit source %s", request.Source)
+	}
+
+	amount, err := parseAmount(request.Amount.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount <= 0 {
+		return nil, fmt.Errorf("deposit amount must be greater than zero")
+	}
+
+	paidAt := time.Now()
+	if request.PaidAt != "" {
+		if paidAt, err = timehelper.FormatISO7ToTime(request.PaidAt); err != nil {
+			return nil, fmt.Errorf("invalid paid at: %w", err)
+		}
+	}
+
+	currency := request.Amount.Currency
+	if currency == "" {
+		currency = "IDR"
+	}
+
+	return &entity.Deposit{
+		DepositReference: request.DepositReference,
+		MerchantCode:     request.MerchantCode,
+		Source:           request.Source,
+		SourceReference:  request.SourceReference,
+		Amount:           amount,
+		Currency:         currency,
+		Description:      request.Description,
+		Status:           constants.StatusCredited,
+		PaidAt:           paidAt,
+		CreatedAt:        time.Now(),
+	}, nil
+}
+
+func (d *depositService) handleDepositResponse(responseCode string, request dto.DepositRequest, balanceAfter string) dto.DepositResponse {
+	additionalInfo := map[string]string{}
+	if balanceAfter != "" {
+		additionalInfo["balance_after"] = balanceAfter
+	}
+	return dto.DepositResponse{
+		ResponseCode:     responseCode,
+		ResponseMessage:  constants.ResponseMap[responseCode],
+		DepositReference: request.DepositReference,
+		MerchantCode:     request.MerchantCode,
+		TransactionDate:  timehelper.FormatTimeToISO7(time.Now()),
+		AdditionalInfo:   additionalInfo,
+	}
+}
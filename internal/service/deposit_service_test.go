@@ -0,0 +1,46 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+// stubDepositRepository return stored deposit by reference, methods not used by duplicate check are left unimplemented
+type stubDepositRepository struct {
+	repository.DepositRepository
+	stored  *entity.Deposit
+	findErr error
+}
+
+func (s *stubDepositRepository) FindByReference(ctx context.Context, depositReference string) (*entity.Deposit, error) {
+	return s.stored, s.findErr
+}
+
+func TestDuplicateDepositResponseCode(t *testing.T) {
+	stored := &entity.Deposit{DepositReference: "DEP-0001", MerchantCode: "MRC001", Amount: 150000, Currency: "IDR"}
+	tests := []struct {
+		name         string
+		repo         *stubDepositRepository
+		deposit      entity.Deposit
+		responseCode string
+	}{
+		{"exact replay", &stubDepositRepository{stored: stored}, entity.Deposit{DepositReference: "DEP-0001", MerchantCode: "MRC001", Amount: 150000.001, Currency: "IDR"}, constants.TransferSuccess},
+		{"other merchant", &stubDepositRepository{stored: stored}, entity.Deposit{DepositReference: "DEP-0001", MerchantCode: "MRC002", Amount: 150000, Currency: "IDR"}, constants.ErrDuplicateReference},
+		{"other amount", &stubDepositRepository{stored: stored}, entity.Deposit{DepositReference: "DEP-0001", MerchantCode: "MRC001", Amount: 150000.01, Currency: "IDR"}, constants.ErrDuplicateReference},
+		{"other currency", &stubDepositRepository{stored: stored}, entity.Deposit{DepositReference: "DEP-0001", MerchantCode: "MRC001", Amount: 150000, Currency: "USD"}, constants.ErrDuplicateReference},
+		{"stored deposit unreadable", &stubDepositRepository{findErr: errors.New("connection refused")}, entity.Deposit{DepositReference: "DEP-0001", MerchantCode: "MRC001", Amount: 150000, Currency: "IDR"}, constants.ErrInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &depositService{depositRepo: tt.repo}
+			if got := svc.duplicateResponseCode(testContext(), &tt.deposit); got != tt.responseCode {
+				t.Errorf("duplicateResponseCode() = %s, want %s", got, tt.responseCode)
+			}
+		})
+	}
+}
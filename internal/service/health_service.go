@@ -0,0 +1,159 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/kafkahelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type dependencyCheck func(ctx context.Context) error
+
+type HealthService interface {
+	Liveness() dto.HealthResponse
+	Readiness(ctx context.Context) dto.HealthResponse
+	Drain()
+}
+
+type healthService struct {
+	startedAt time.Time
+	draining  atomic.Bool
+	timeout   time.Duration
+	checks    map[string]dependencyCheck
+}
+
+func NewHealthService(db *gorm.DB, redisClient *redis.Client, kafkaProducer *kafkahelper.KafkaProducer, partnerService BankPartner, redisService TransferRedisService, timeout time.Duration) HealthService {
+	return &healthService{
+		startedAt: time.Now(),
+		timeout:   timeout,
+		checks: map[string]dependencyCheck{
+			constants.DependencyPostgres: func(ctx context.Context) error {
+				sqlDb, err := db.DB()
+				if err != nil {
+					return err
+				}
+				return sqlDb.PingContext(ctx)
+			},
+			constants.DependencyRedis: func(ctx context.Context) error {
+				return redisClient.Ping(ctx).Err()
+			},
+			constants.DependencyKafka: func(ctx context.Context) error {
+				return kafkaProducer.Ping()
+			},
+			constants.DependencyPartnerCache: func(ctx context.Context) error {
+				if !partnerService.IsLoaded() {
+					return errors.New("bank partner config is not loaded")
+				}
+				return nil
+			},
+			constants.DependencyFeeCache: func(ctx context.Context) error {
+				loaded, err := redisService.FeeCacheLoaded(ctx)
+				if err != nil {
+					return err
+				}
+				if !loaded {
+					return errors.New("fee setting is not cached in redis")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// Liveness only tells the process is serving, dependency outage must not restart the pod
+func (h *healthService) Liveness() dto.HealthResponse {
+	return dto.HealthResponse{
+		Status:    constants.HealthUp,
+		CheckedAt: time.Now().Format(time.RFC3339),
+		Uptime:    time.Since(h.startedAt).Round(time.Second).String(),
+	}
+}
+
+// Readiness run every dependency check concurrently, not ready when any of them fails or service is draining
+func (h *healthService) Readiness(ctx context.Context) dto.HealthResponse {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	results := make(map[string]dto.HealthCheck, len(h.checks))
+	status := constants.HealthUp
+
+	for name, check := range h.checks {
+		wg.Go(func() {
+			result := h.runCheck(ctx, check)
+
+			mu.Lock()
+			results[name] = result
+			if result.Status != constants.HealthUp {
+				status = constants.HealthDown
+			}
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	if h.draining.Load() {
+		status = constants.HealthDraining
+	}
+
+	if status != constants.HealthUp {
+		loghelper.FromContext(ctx).WithFields(logrus.Fields{
+			"service":   "health_service",
+			"operation": "readiness",
+			"checks":    results,
+		}).Warnf("Transfer service is not ready, status %s", status)
+	}
+
+	return dto.HealthResponse{
+		Status:    status,
+		CheckedAt: start.Format(time.RFC3339),
+		Uptime:    time.Since(h.startedAt).Round(time.Second).String(),
+		LatencyMs: h.millisecond(time.Since(start)),
+		Checks:    results,
+	}
+}
+
+// Drain mark service as draining so load balancer stop routing new request
+func (h *healthService) Drain() {
+	h.draining.Store(true)
+}
+
+func (h *healthService) runCheck(ctx context.Context, check dependencyCheck) dto.HealthCheck {
+	start := time.Now()
+	done := make(chan error, 1)
+
+	// some client, kafka metadata refresh included, do not honor context, bound them by timeout here
+	go func() { done <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", h.timeout)
+	}
+
+	result := dto.HealthCheck{Status: constants.HealthUp, LatencyMs: h.millisecond(time.Since(start))}
+	if err != nil {
+		result.Status = constants.HealthDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (h *healthService) millisecond(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// inflightTracker count running operations so shutdown can wait for them, once draining no new operation is admitted
+type inflightTracker struct {
+	mutex    sync.Mutex
+	count    int
+	draining bool
+	idle     chan struct{}
+}
+
+func newInflightTracker() *inflightTracker {
+	return &inflightTracker{idle: make(chan struct{})}
+}
+
+// acquire admit one operation, it returns false when draining has started
+func (t *inflightTracker) acquire() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.draining {
+		return false
+	}
+	t.count++
+	return true
+}
+
+func (t *inflightTracker) release() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.count--
+	if t.draining && t.count == 0 {
+		close(t.idle)
+	}
+}
+
+func (t *inflightTracker) isDraining() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.draining
+}
+
+// drain stop admitting operation and wait until running ones complete or context is done
+func (t *inflightTracker) drain(ctx context.Context) error {
+	t.mutex.Lock()
+	if !t.draining {
+		t.draining = true
+		if t.count == 0 {
+			close(t.idle)
+		}
+	}
+	t.mutex.Unlock()
+
+	select {
+	case <-t.idle:
+		return nil
+	case <-ctx.Done():
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		return fmt.Errorf("%d operation still running: %w", t.count, ctx.Err())
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type LedgerService interface {
+	TrialBalance(ctx context.Context, from, to time.Time) dto.TrialBalanceResponse
+}
+
+type ledgerService struct {
+	journalRepo repository.JournalRepository
+}
+
+func NewLedgerService(journalRepo repository.JournalRepository) LedgerService {
+	return &ledgerService{journalRepo}
+}
+
+func (l *ledgerService) TrialBalance(ctx context.Context, from, to time.Time) dto.TrialBalanceResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "ledger_service",
+		"operation": "trial_balance",
+	})
+
+	response := dto.TrialBalanceResponse{
+		From:       from.Format(time.DateOnly),
+		To:         to.Format(time.DateOnly),
+		Accounts:   []dto.TrialBalanceAccount{},
+		Unbalanced: []dto.UnbalancedJournal{},
+	}
+
+	// sum debit and credit per account
+	log.Info("Calculating trial balance from journal")
+	accounts, err := l.journalRepo.TrialBalance(ctx, from, to)
+	if err != nil {
+		log.WithError(err).Error("Failed to calculate trial balance")
+		response.ResponseCode = constants.ErrInternalServerError
+		response.ResponseMessage = constants.ResponseMap[constants.ErrInternalServerError]
+		return response
+	}
+
+	// check every journal sums to zero
+	log.Info("Checking unbalanced journal")
+	unbalanced, err := l.journalRepo.FindUnbalanced(ctx, from, to)
+	if err != nil {
+		log.WithError(err).Error("Failed to check unbalanced journal")
+		response.ResponseCode = constants.ErrInternalServerError
+		response.ResponseMessage = constants.ResponseMap[constants.ErrInternalServerError]
+		return response
+	}
+
+	var totalDebit, totalCredit float64
+	for _, account := range accounts {
+		totalDebit += account.TotalDebit
+		totalCredit += account.TotalCredit
+		response.Accounts = append(response.Accounts, dto.TrialBalanceAccount{
+			AccountCode: account.AccountCode,
+			Debit:       strconv.FormatFloat(account.TotalDebit, 'f', 2, 64),
+			Credit:      strconv.FormatFloat(account.TotalCredit, 'f', 2, 64),
+			Balance:     strconv.FormatFloat(account.TotalDebit-account.TotalCredit, 'f', 2, 64),
+		})
+	}
+
+	for _, journal := range unbalanced {
+		response.Unbalanced = append(response.Unbalanced, dto.UnbalancedJournal{
+			JournalId:   journal.JournalId,
+			Reference:   journal.Reference,
+			JournalType: journal.JournalType,
+			Difference:  strconv.FormatFloat(journal.Difference, 'f', 2, 64),
+		})
+	}
+
+	response.TotalDebit = strconv.FormatFloat(totalDebit, 'f', 2, 64)
+	response.TotalCredit = strconv.FormatFloat(totalCredit, 'f', 2, 64)
+	response.Balanced = len(unbalanced) == 0 && math.Round(totalDebit*100) == math.Round(totalCredit*100)
+	if !response.Balanced {
+		log.Errorf("Ledger is not balanced, debit %.2f credit %.2f with %d unbalanced journal", totalDebit, totalCredit, len(unbalanced))
+	}
+
+	response.ResponseCode = constants.TransferSuccess
+	response.ResponseMessage = constants.ResponseMap[constants.TransferSuccess]
+	return response
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const inquiryResponseLimit = 64 * 1024
+
+// postInquiry send inquiry to partner gateway, partner reports business error in body along with
+// non 2xx status so body is parsed regardless of status
+func postInquiry(ctx context.Context, client *http.Client, url, externalId string, payload, response any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inquiry request: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build inquiry request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-EXTERNAL-ID", externalId)
+
+	httpResponse, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to call partner inquiry: %w", err)
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err := io.ReadAll(io.LimitReader(httpResponse.Body, inquiryResponseLimit))
+	if err != nil {
+		return fmt.Errorf("failed to read partner inquiry response: %w", err)
+	}
+	if err := json.Unmarshal(responseBody, response); err != nil {
+		return fmt.Errorf("failed to parse partner inquiry response with http status %d: %w", httpResponse.StatusCode, err)
+	}
+	return nil
+}
+
+// inquiryResult classify inquiry into metric result and our response code, SNAP 404 category means account not found
+func inquiryResult(err error, partnerCode, notFoundCode string) (string, string) {
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout", constants.ErrTransferTimeout
+	case err != nil:
+		return "error", constants.ErrExternalServerError
+	case strings.HasPrefix(partnerCode, "404"):
+		return "not_found", notFoundCode
+	case !strings.HasPrefix(partnerCode, "200"):
+		return "rejected", constants.ErrExternalServerError
+	}
+	return "success", constants.TransferSuccess
+}
@@ -14,7 +14,8 @@ const DefaultBankConfig = "14"
 
 type BankPartner interface {
 	LoadAllBankPartner(ctx context.Context) error
-	GetBankConfig(bankCode string, log *logrus.Entry) entity.BankConfig
+	GetBankConfig(ctx context.Context, bankCode string) entity.BankConfig
+	IsLoaded() bool
 }
 
 type bankPartner struct {
@@ -30,7 +31,7 @@ func NewPartnerService(partnerRepo repository.PartnerRepository) BankPartner {
 }
 
 func (s *bankPartner) LoadAllBankPartner(ctx context.Context) error {
-	log := loghelper.Logger.WithFields(logrus.Fields{
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
 		"service":   "partner_service",
 		"operation": "load_config",
 	})
@@ -54,12 +55,13 @@ func (s *bankPartner) LoadAllBankPartner(ctx context.Context) error {
 	return nil
 }
 
-func (s *bankPartner) GetBankConfig(bankCode string, log *logrus.Entry) entity.BankConfig {
+func (s *bankPartner) GetBankConfig(ctx context.Context, bankCode string) entity.BankConfig {
+	log := loghelper.FromContext(ctx)
 	// get bank config from memory based on bank bank bankCode
 	log.Info("Get bank partner config from memory")
 	s.rwMutex.RLock()
 	bank, ok := s.bankCache[bankCode]
-	s.rwMutex.Unlock()
+	s.rwMutex.RUnlock()
 
 	// if data config not found, fallback to default bank
 	if !ok {
@@ -74,3 +76,10 @@ func (s *bankPartner) GetBankConfig(bankCode string, log *logrus.Entry) entity.B
 	log.Infof("Bank %s selected", bank.BankName)
 	return bank
 }
+
+// IsLoaded reports whether bank partner config has been cached to memory
+func (s *bankPartner) IsLoaded() bool {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	return len(s.bankCache) > 0
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/metrichelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ResponseCodeService interface {
+	LoadResponseCodes(ctx context.Context) error
+	Start(ctx context.Context, interval time.Duration)
+	Resolve(ctx context.Context, bankCode string, codes ...string) entity.PartnerResponseCode
+	List(ctx context.Context, bankCode string) dto.ResponseCodeResponse
+	Save(ctx context.Context, request dto.ResponseCodeRequest) dto.ResponseCodeResponse
+}
+
+type responseCodeService struct {
+	rwMutex          sync.RWMutex
+	responseCodeRepo repository.ResponseCodeRepository
+	mappings         map[string]map[string]entity.PartnerResponseCode
+}
+
+func NewResponseCodeService(responseCodeRepo repository.ResponseCodeRepository) ResponseCodeService {
+	return &responseCodeService{
+		responseCodeRepo: responseCodeRepo,
+	}
+}
+
+// LoadResponseCodes cache mapping of every partner to memory, row with unknown outcome is skipped so it resolves as suspect
+func (r *responseCodeService) LoadResponseCodes(ctx context.Context) error {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "response_code_service",
+		"operation": "load_response_code",
+	})
+
+	log.Info("Collect partner response code mapping from database")
+	rows, err := r.responseCodeRepo.FindAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to collect partner response code mapping from database")
+		return err
+	}
+
+	mappings := make(map[string]map[string]entity.PartnerResponseCode)
+	for _, row := range rows {
+		if !constants.PartnerOutcomes[row.Outcome] {
+			log.Warnf("Skip response code %s of bank %s with unknown outcome %s", row.PartnerCode, row.BankCode, row.Outcome)
+			continue
+		}
+		if mappings[row.BankCode] == nil {
+			mappings[row.BankCode] = make(map[string]entity.PartnerResponseCode)
+		}
+		mappings[row.BankCode][row.PartnerCode] = row
+	}
+
+	log.Infof("Cache %d partner response code mapping of %d banks to memory", len(rows), len(mappings))
+	r.rwMutex.Lock()
+	r.mappings = mappings
+	r.rwMutex.Unlock()
+	return nil
+}
+
+// Start reload mapping periodically so change made through other instance or directly in database is picked up
+func (r *responseCodeService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.LoadResponseCodes(ctx); err != nil {
+				loghelper.FromContext(ctx).WithError(err).Error("Failed to reload partner response code, keep previous mapping")
+			}
+		}
+	}
+}
+
+// Resolve try codes from most specific to least, code without mapping resolves to suspect and raises alert
+func (r *responseCodeService) Resolve(ctx context.Context, bankCode string, codes ...string) entity.PartnerResponseCode {
+	r.rwMutex.RLock()
+	bankMappings := r.mappings[bankCode]
+	for _, code := range codes {
+		if mapping, ok := bankMappings[code]; ok {
+			r.rwMutex.RUnlock()
+			return mapping
+		}
+	}
+	r.rwMutex.RUnlock()
+
+	partnerCode := ""
+	if len(codes) > 0 {
+		partnerCode = codes[0]
+	}
+
+	// partner code is logged rather than labelled, code returned by partner is unbounded
+	metrichelper.UnknownPartnerCodeTotal.WithLabelValues(bankCode).Inc()
+	loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"alert":        "unknown_partner_response_code",
+		"bank_code":    bankCode,
+		"partner_code": partnerCode,
+	}).Error("Partner response code has no mapping, transfer is marked suspect")
+
+	return entity.PartnerResponseCode{
+		BankCode:    bankCode,
+		PartnerCode: partnerCode,
+		Outcome:     constants.OutcomeSuspect,
+	}
+}
+
+func (r *responseCodeService) List(ctx context.Context, bankCode string) dto.ResponseCodeResponse {
+	log := loghelper.FromContext(ctx)
+	if bankCode == "" {
+		return r.handleResponseCodeResponse(constants.ErrBadRequest, nil)
+	}
+
+	mappings, err := r.responseCodeRepo.FindByBank(ctx, bankCode)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch partner response code mapping")
+		return r.handleResponseCodeResponse(constants.ErrInternalServerError, nil)
+	}
+	return r.handleResponseCodeResponse(constants.TransferSuccess, mappings)
+}
+
+func (r *responseCodeService) Save(ctx context.Context, request dto.ResponseCodeRequest) dto.ResponseCodeResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":      "response_code_service",
+		"operation":    "save_response_code",
+		"bank_code":    request.BankCode,
+		"partner_code": request.PartnerCode,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	_, knownResponseCode := constants.ResponseMap[request.ResponseCode]
+	if request.BankCode == "" || request.PartnerCode == "" || !constants.PartnerOutcomes[request.Outcome] || (request.ResponseCode != "" && !knownResponseCode) {
+		log.Warn("Bank code, partner code and valid outcome are mandatory, response code must be our own code")
+		return r.handleResponseCodeResponse(constants.ErrBadRequest, nil)
+	}
+
+	mapping := entity.PartnerResponseCode{
+		BankCode:     request.BankCode,
+		PartnerCode:  request.PartnerCode,
+		Outcome:      request.Outcome,
+		ResponseCode: request.ResponseCode,
+		Description:  request.Description,
+		UpdatedAt:    time.Now(),
+	}
+
+	log.Infof("Persist response code mapping with outcome %s", request.Outcome)
+	if err := r.responseCodeRepo.Save(ctx, &mapping); err != nil {
+		log.WithError(err).Error("Failed to persist response code mapping")
+		return r.handleResponseCodeResponse(constants.ErrInternalServerError, nil)
+	}
+
+	// refresh cache so new mapping applies immediately on this instance, others pick it up on next reload
+	if err := r.LoadResponseCodes(ctx); err != nil {
+		log.WithError(err).Error("Failed to reload partner response code mapping")
+		return r.handleResponseCodeResponse(constants.ErrInternalServerError, nil)
+	}
+
+	return r.handleResponseCodeResponse(constants.TransferSuccess, []entity.PartnerResponseCode{mapping})
+}
+
+func (r *responseCodeService) handleResponseCodeResponse(responseCode string, mappings []entity.PartnerResponseCode) dto.ResponseCodeResponse {
+	response := dto.ResponseCodeResponse{
+		ResponseCode:    responseCode,
+		ResponseMessage: constants.ResponseMap[responseCode],
+		Mappings:        []dto.ResponseCodeMapping{},
+	}
+
+	for _, mapping := range mappings {
+		response.Mappings = append(response.Mappings, dto.ResponseCodeMapping{
+			BankCode:     mapping.BankCode,
+			PartnerCode:  mapping.PartnerCode,
+			Outcome:      mapping.Outcome,
+			ResponseCode: mapping.ResponseCode,
+			Description:  mapping.Description,
+			UpdatedAt:    timehelper.FormatTimeToISO7(mapping.UpdatedAt),
+		})
+	}
+	return response
+}
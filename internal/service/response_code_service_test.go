@@ -0,0 +1,163 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/metrichelper"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+// stubResponseCodeRepository serve mapping rows from memory and count database reads
+type stubResponseCodeRepository struct {
+	rows    []entity.PartnerResponseCode
+	findErr error
+	reads   int
+}
+
+func (s *stubResponseCodeRepository) FindAll(ctx context.Context) ([]entity.PartnerResponseCode, error) {
+	s.reads++
+	return s.rows, s.findErr
+}
+
+func (s *stubResponseCodeRepository) FindByBank(ctx context.Context, bankCode string) ([]entity.PartnerResponseCode, error) {
+	var rows []entity.PartnerResponseCode
+	for _, row := range s.rows {
+		if row.BankCode == bankCode {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (s *stubResponseCodeRepository) Save(ctx context.Context, mapping *entity.PartnerResponseCode) error {
+	s.rows = append(s.rows, *mapping)
+	return nil
+}
+
+func newTestResponseCodeService(t *testing.T) (*responseCodeService, *stubResponseCodeRepository) {
+	t.Helper()
+	repo := &stubResponseCodeRepository{rows: []entity.PartnerResponseCode{
+		{BankCode: constants.BankCodeBCA, PartnerCode: "2001800", Outcome: constants.OutcomeSuccess},
+		{BankCode: constants.BankCodeBCA, PartnerCode: "404xx11", Outcome: constants.OutcomeFailedFinal, ResponseCode: constants.ErrDataNotFound},
+		{BankCode: constants.BankCodeBCA, PartnerCode: "500", Outcome: constants.OutcomeFailedRetryable},
+		{BankCode: constants.BankCodeBCA, PartnerCode: "4291800", Outcome: "UNDEFINED"},
+		{BankCode: constants.BankCodePermata, PartnerCode: "00", Outcome: constants.OutcomeSuccess},
+	}}
+
+	svc := NewResponseCodeService(repo).(*responseCodeService)
+	if err := svc.LoadResponseCodes(testContext()); err != nil {
+		t.Fatalf("LoadResponseCodes() error: %v", err)
+	}
+	return svc, repo
+}
+
+func TestResolveResponseCode(t *testing.T) {
+	svc, repo := newTestResponseCodeService(t)
+	tests := []struct {
+		name         string
+		bankCode     string
+		codes        []string
+		partnerCode  string
+		outcome      string
+		responseCode string
+	}{
+		{"exact code", constants.BankCodeBCA, []string{"2001800", "200xx00", "200"}, "2001800", constants.OutcomeSuccess, ""},
+		{"service code wildcard", constants.BankCodeBCA, []string{"4041811", "404xx11", "404"}, "404xx11", constants.OutcomeFailedFinal, constants.ErrDataNotFound},
+		{"http category", constants.BankCodeBCA, []string{"5001802", "500xx02", "500"}, "500", constants.OutcomeFailedRetryable, ""},
+		{"mapping is per bank", constants.BankCodePermata, []string{"2001800"}, "2001800", constants.OutcomeSuspect, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping := svc.Resolve(testContext(), tt.bankCode, tt.codes...)
+			if mapping.PartnerCode != tt.partnerCode || mapping.Outcome != tt.outcome || mapping.ResponseCode != tt.responseCode {
+				t.Errorf("Resolve(%s, %v) = %s/%s/%s, want %s/%s/%s", tt.bankCode, tt.codes, mapping.PartnerCode, mapping.Outcome,
+					mapping.ResponseCode, tt.partnerCode, tt.outcome, tt.responseCode)
+			}
+		})
+	}
+
+	if repo.reads != 1 {
+		t.Errorf("mapping read from database %d times, want once at load", repo.reads)
+	}
+}
+
+func TestResolveUnknownCodeDefaultsToSuspectAndAlerts(t *testing.T) {
+	svc, _ := newTestResponseCodeService(t)
+	logger, hook := logtest.NewNullLogger()
+	ctx := loghelper.NewContext(context.Background(), logrus.NewEntry(logger))
+
+	before := testutil.ToFloat64(metrichelper.UnknownPartnerCodeTotal.WithLabelValues(constants.BankCodeBCA))
+
+	// row with unknown outcome is skipped at load, so its code is unknown too
+	for _, code := range []string{"4091899", "4291800"} {
+		mapping := svc.Resolve(ctx, constants.BankCodeBCA, code)
+		if mapping.Outcome != constants.OutcomeSuspect || mapping.PartnerCode != code || mapping.BankCode != constants.BankCodeBCA {
+			t.Errorf("Resolve(%s) = %+v, want suspect", code, mapping)
+		}
+	}
+
+	if got := testutil.ToFloat64(metrichelper.UnknownPartnerCodeTotal.WithLabelValues(constants.BankCodeBCA)) - before; got != 2 {
+		t.Errorf("unknown partner code counter increased by %v, want 2", got)
+	}
+
+	entries := hook.AllEntries()
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2 alerts", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Level != logrus.ErrorLevel || entry.Data["alert"] != "unknown_partner_response_code" {
+			t.Errorf("entry %d = %s %v, want error alert", i, entry.Level, entry.Data)
+		}
+	}
+	if entries[0].Data["partner_code"] != "4091899" || entries[0].Data["bank_code"] != constants.BankCodeBCA {
+		t.Errorf("alert fields = %v, want bank and partner code", entries[0].Data)
+	}
+}
+
+func TestResolveKeepsCacheWhenReloadFails(t *testing.T) {
+	svc, repo := newTestResponseCodeService(t)
+	repo.findErr = errors.New("connection refused")
+
+	if err := svc.LoadResponseCodes(testContext()); err == nil {
+		t.Fatal("LoadResponseCodes() error = nil, want database error")
+	}
+	if mapping := svc.Resolve(testContext(), constants.BankCodePermata, "00"); mapping.Outcome != constants.OutcomeSuccess {
+		t.Errorf("Resolve after failed reload = %s, want cached success", mapping.Outcome)
+	}
+}
+
+func TestSaveResponseCodeRefreshesCache(t *testing.T) {
+	svc, _ := newTestResponseCodeService(t)
+	ctx := testContext()
+
+	if mapping := svc.Resolve(ctx, constants.BankCodePermata, "14"); mapping.Outcome != constants.OutcomeSuspect {
+		t.Fatalf("Resolve before save = %s, want suspect", mapping.Outcome)
+	}
+
+	response := svc.Save(ctx, dto.ResponseCodeRequest{
+		BankCode:     constants.BankCodePermata,
+		PartnerCode:  "14",
+		Outcome:      constants.OutcomeFailedFinal,
+		ResponseCode: constants.ErrDataNotFound,
+	})
+	if response.ResponseCode != constants.TransferSuccess {
+		t.Fatalf("Save() = %s, want success", response.ResponseCode)
+	}
+
+	if mapping := svc.Resolve(ctx, constants.BankCodePermata, "14"); mapping.Outcome != constants.OutcomeFailedFinal || mapping.ResponseCode != constants.ErrDataNotFound {
+		t.Errorf("Resolve after save = %s/%s, want failed final", mapping.Outcome, mapping.ResponseCode)
+	}
+
+	if response := svc.Save(ctx, dto.ResponseCodeRequest{BankCode: constants.BankCodePermata, PartnerCode: "05", Outcome: "UNDEFINED"}); response.ResponseCode != constants.ErrBadRequest {
+		t.Errorf("Save with unknown outcome = %s, want bad request", response.ResponseCode)
+	}
+}
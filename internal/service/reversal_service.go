@@ -0,0 +1,189 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/manager"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	errTransferNotFound = errors.New("original transfer not found")
+	errAlreadyReversed  = errors.New("transfer already reversed")
+	errNotReversible    = errors.New("transfer is not in reversible status")
+)
+
+type ReversalService interface {
+	Reverse(ctx context.Context, request dto.ReversalRequest, actor string) dto.ReversalResponse
+}
+
+type reversalService struct {
+	transferRepo repository.TransferRepository
+	ledgerRepo   repository.LedgerRepository
+	journalRepo  repository.JournalRepository
+	merchantRepo repository.BalanceRepository
+	redisService TransferRedisService
+	db           *gorm.DB
+}
+
+func NewReversalService(transferRepo repository.TransferRepository, ledgerRepo repository.LedgerRepository, journalRepo repository.JournalRepository,
+	merchantRepo repository.BalanceRepository, redisService TransferRedisService, db *gorm.DB) ReversalService {
+	return &reversalService{transferRepo, ledgerRepo, journalRepo, merchantRepo, redisService, db}
+}
+
+func (r *reversalService) Reverse(ctx context.Context, request dto.ReversalRequest, actor string) dto.ReversalResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":            "reversal_service",
+		"operation":          "reverse_transfer",
+		"merchant":           request.MerchantCode,
+		"original_reference": request.OriginalPartnerReferenceNo,
+		"reversal_reference": request.ReversalReferenceNo,
+		"actor":              actor,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	if request.MerchantCode == "" || request.OriginalPartnerReferenceNo == "" || request.ReversalReferenceNo == "" || request.Reason == "" {
+		log.Warn("Merchant, original reference, reversal reference and reason are mandatory")
+		return r.handleReversalResponse(constants.ErrBadRequest, request, nil, 0)
+	}
+
+	// create linked reversal, credit merchant and mark original as reversed in single transaction
+	log.Info("Persist reversal, ledger, and credited balance to database")
+	reversal, balance, err := r.PersistReversal(ctx, request, actor)
+	switch {
+	case errors.Is(err, errTransferNotFound):
+		log.Warn("Original transfer not found")
+		return r.handleReversalResponse(constants.ErrDataNotFound, request, nil, 0)
+	case errors.Is(err, errAlreadyReversed):
+		log.Warn("Original transfer already reversed")
+		return r.handleReversalResponse(constants.ErrAlreadyReversed, request, reversal, 0)
+	case errors.Is(err, errNotReversible):
+		log.WithError(err).Warn("Original transfer can not be reversed")
+		return r.handleReversalResponse(constants.ErrInvalidStatus, request, nil, 0)
+	case err != nil:
+		log.WithError(err).Error("Failed to persist reversal into database")
+		return r.handleReversalResponse(constants.ErrInternalServerError, request, nil, 0)
+	}
+
+	// follow committed balance in redis, database remain the source of truth when it still fails after retry
+	log.Info("Credit merchant balance in redis")
+	if err := r.redisService.CreditBalance(ctx, reversal.MerchantCode, reversal.Amount, balance); err != nil {
+		log.WithError(err).Errorf("Redis balance for merchant %s is stale, database balance is %.2f, reload merchant balance", reversal.MerchantCode, balance)
+	}
+
+	log.Infof("Transfer reversed by %s, %.2f credited to merchant %s", actor, reversal.Amount, reversal.MerchantCode)
+	return r.handleReversalResponse(constants.TransferSuccess, request, reversal, balance)
+}
+
+func (r *reversalService) PersistReversal(ctx context.Context, request dto.ReversalRequest, actor string) (*entity.Transaction, float64, error) {
+	var reversal *entity.Transaction
+	var balance float64
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		transferTx := r.transferRepo.WithTransaction(tx)
+		ledgerTx := r.ledgerRepo.WithTransaction(tx)
+		accountTx := r.merchantRepo.WithTransaction(tx)
+		journalTx := r.journalRepo.WithTransaction(tx)
+
+		pm := manager.NewTransferPersistenceManager(ledgerTx, transferTx, nil, accountTx)
+		jm := manager.NewJournalManager(journalTx)
+		sm := manager.NewTransferStateManager(transferTx)
+
+		// lock original transfer, partner reference is only unique per merchant
+		original, err := transferTx.FindForUpdate(ctx, request.MerchantCode, request.OriginalPartnerReferenceNo)
+		if err != nil {
+			return err
+		}
+
+		if original == nil {
+			return errTransferNotFound
+		}
+
+		// guard double reversal
+		existing, err := transferTx.FindReversal(ctx, original.ID)
+		if err != nil {
+			return err
+		}
+
+		if existing != nil || original.Status == constants.StatusReversed {
+			reversal = existing
+			return errAlreadyReversed
+		}
+
+		if err := sm.Transition(ctx, original, constants.StatusReversed, request.Reason, actor); err != nil {
+			if errors.Is(err, manager.ErrInvalidTransition) {
+				return fmt.Errorf("%w: %w", errNotReversible, err)
+			}
+			return err
+		}
+
+		// principal and optionally service fee returned to merchant, fee is what transfer journal charged
+		amount := original.Amount
+		if request.IncludeFee {
+			if amount, err = jm.ChargedAmount(ctx, original.ID); err != nil {
+				return err
+			}
+		}
+
+		// save linked reversal transaction
+		reversal, err = pm.CreateReversal(ctx, original, request.ReversalReferenceNo, request.Reason, amount)
+		if err != nil {
+			return err
+		}
+
+		if err := sm.Init(ctx, reversal, request.Reason, actor); err != nil {
+			return err
+		}
+
+		// restore balance
+		balance, err = pm.CreditMerchant(ctx, original.MerchantCode, amount)
+		if err != nil {
+			return err
+		}
+
+		// save history reversal
+		if err := pm.CreateReversalLedger(ctx, reversal, balance); err != nil {
+			return err
+		}
+
+		// book double entry journal
+		if request.IncludeFee {
+			return jm.PostReversalJournal(ctx, original.ID, reversal.ID, reversal.PartnerReferenceNo, constants.JournalReversal)
+		}
+		return jm.PostPrincipalReversalJournal(ctx, reversal)
+	})
+
+	return reversal, balance, err
+}
+
+func (r *reversalService) handleReversalResponse(responseCode string, request dto.ReversalRequest, reversal *entity.Transaction, balance float64) dto.ReversalResponse {
+	additionalInfo := map[string]string{}
+	if reversal != nil {
+		additionalInfo["reversal_reference"] = reversal.PartnerReferenceNo
+		additionalInfo["reversed_amount"] = strconv.FormatFloat(reversal.Amount, 'f', 2, 64)
+	}
+
+	if responseCode == constants.TransferSuccess {
+		additionalInfo["balance_after"] = strconv.FormatFloat(balance, 'f', 2, 64)
+	}
+
+	return dto.ReversalResponse{
+		ResponseCode:               responseCode,
+		ResponseMessage:            constants.ResponseMap[responseCode],
+		OriginalPartnerReferenceNo: request.OriginalPartnerReferenceNo,
+		ReversalReferenceNo:        request.ReversalReferenceNo,
+		TransactionDate:            timehelper.FormatTimeToISO7(time.Now()),
+		AdditionalInfo:             additionalInfo,
+	}
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"testing"
+)
+
+func TestReverseRejectsIncompleteRequest(t *testing.T) {
+	svc := &reversalService{}
+	complete := dto.ReversalRequest{MerchantCode: "MRC001", OriginalPartnerReferenceNo: "MRC-0001", ReversalReferenceNo: "MRC-0001-R", Reason: "duplicate payment"}
+	tests := []struct {
+		name   string
+		modify func(*dto.ReversalRequest)
+	}{
+		{"without merchant", func(r *dto.ReversalRequest) { r.MerchantCode = "" }},
+		{"without original reference", func(r *dto.ReversalRequest) { r.OriginalPartnerReferenceNo = "" }},
+		{"without reversal reference", func(r *dto.ReversalRequest) { r.ReversalReferenceNo = "" }},
+		{"without reason", func(r *dto.ReversalRequest) { r.Reason = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := complete
+			tt.modify(&request)
+			if response := svc.Reverse(testContext(), request, "admin-1"); response.ResponseCode != constants.ErrBadRequest {
+				t.Errorf("Reverse() = %s, want %s", response.ResponseCode, constants.ErrBadRequest)
+			}
+		})
+	}
+}
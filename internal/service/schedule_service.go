@@ -0,0 +1,473 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/kafkahelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const scheduleClaimLimit = 50
+
+type ScheduleService interface {
+	Schedule(ctx context.Context, request dto.ScheduleTransferRequest, merchantCode string) dto.ScheduleTransferResponse
+	List(ctx context.Context, merchantCode, status string) dto.ScheduleTransferResponse
+	Cancel(ctx context.Context, merchantCode, scheduleReference string) dto.ScheduleTransferResponse
+	Start(ctx context.Context, interval time.Duration)
+	RunDue(ctx context.Context) error
+}
+
+type scheduleService struct {
+	scheduleRepo      repository.ScheduleRepository
+	transferRepo      repository.TransferRepository
+	transferService   TransferService
+	calendar          CalendarService
+	kafkaProducer     *kafkahelper.KafkaProducer
+	db                *gorm.DB
+	notificationTopic string
+	claimTimeout      time.Duration
+}
+
+// NewScheduleService create scheduler, run still claimed after claimTimeout is taken as abandoned by crashed instance and run again
+func NewScheduleService(scheduleRepo repository.ScheduleRepository, transferRepo repository.TransferRepository, transferService TransferService,
+	calendar CalendarService, kafkaProducer *kafkahelper.KafkaProducer, db *gorm.DB, notificationTopic string, claimTimeout time.Duration) ScheduleService {
+	return &scheduleService{scheduleRepo, transferRepo, transferService, calendar, kafkaProducer, db, notificationTopic, claimTimeout}
+}
+
+func (s *scheduleService) Schedule(ctx context.Context, request dto.ScheduleTransferRequest, merchantCode string) dto.ScheduleTransferResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":            "schedule_service",
+		"operation":          "create_schedule",
+		"schedule_reference": request.ScheduleReference,
+	})
+
+	if request.ScheduleReference == "" {
+		request.ScheduleReference = request.Transfer.PartnerReferenceNo
+	}
+
+	if request.Recurrence == "" {
+		request.Recurrence = constants.RecurrenceOnce
+	}
+
+	// validate instruction, balance is checked at execution time
+	log.Info("Validating scheduled transfer")
+	schedule, err := s.buildSchedule(request, merchantCode)
+	if err != nil {
+		log.WithError(err).Warn("Invalid scheduled transfer")
+		return s.handleScheduleResponse(constants.ErrBadRequest)
+	}
+
+	existing, err := s.scheduleRepo.FindByReference(ctx, merchantCode, schedule.ScheduleReference)
+	if err != nil {
+		log.WithError(err).Error("Failed to check schedule reference")
+		return s.handleScheduleResponse(constants.ErrInternalServerError)
+	}
+
+	if existing != nil {
+		log.Warn("Schedule reference already registered")
+		return s.handleScheduleResponse(constants.ErrDuplicateReference, *existing)
+	}
+
+	log.Infof("Persist scheduled transfer, first execution at %s", timehelper.FormatTimeToISO7(schedule.NextRunAt))
+	if err := s.scheduleRepo.Save(ctx, schedule); err != nil {
+		log.WithError(err).Error("Failed to persist scheduled transfer")
+		return s.handleScheduleResponse(constants.ErrInternalServerError)
+	}
+
+	return s.handleScheduleResponse(constants.PendingTransfer, *schedule)
+}
+
+func (s *scheduleService) List(ctx context.Context, merchantCode, status string) dto.ScheduleTransferResponse {
+	log := loghelper.FromContext(ctx)
+	log.Info("Fetch scheduled transfer from database")
+	schedules, err := s.scheduleRepo.FindByMerchant(ctx, merchantCode, status)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch scheduled transfer")
+		return s.handleScheduleResponse(constants.ErrInternalServerError)
+	}
+	return s.handleScheduleResponse(constants.TransferSuccess, schedules...)
+}
+
+func (s *scheduleService) Cancel(ctx context.Context, merchantCode, scheduleReference string) dto.ScheduleTransferResponse {
+	log := loghelper.FromContext(ctx).WithField("schedule_reference", scheduleReference)
+
+	schedule, err := s.scheduleRepo.FindByReference(ctx, merchantCode, scheduleReference)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch scheduled transfer")
+		return s.handleScheduleResponse(constants.ErrInternalServerError)
+	}
+
+	if schedule == nil {
+		return s.handleScheduleResponse(constants.ErrDataNotFound)
+	}
+
+	// only active schedule can be cancelled, run already claimed is not affected
+	log.Info("Cancel scheduled transfer")
+	cancelled, err := s.scheduleRepo.Cancel(ctx, schedule.ID)
+	if err != nil {
+		log.WithError(err).Error("Failed to cancel scheduled transfer")
+		return s.handleScheduleResponse(constants.ErrInternalServerError)
+	}
+
+	if !cancelled {
+		log.Warnf("Scheduled transfer is %s, can not be cancelled", schedule.Status)
+		return s.handleScheduleResponse(constants.ErrInvalidStatus, *schedule)
+	}
+
+	schedule.Status = constants.ScheduleCancelled
+	return s.handleScheduleResponse(constants.TransferSuccess, *schedule)
+}
+
+// Start poll due schedule until context is cancelled
+func (s *scheduleService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// claimed runs must not be cut between debit and persist, shutdown waits for this tick
+			if err := s.RunDue(context.WithoutCancel(ctx)); err != nil {
+				loghelper.FromContext(ctx).WithError(err).Error("Failed to run due scheduled transfer")
+			}
+		}
+	}
+}
+
+// RunDue claim due schedules and execute them through normal transfer flow. Run abandoned past claim timeout is claimed
+// again, execution is skipped when transfer of run reference already exists so run is never executed twice
+func (s *scheduleService) RunDue(ctx context.Context) error {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "schedule_service",
+		"operation": "run_schedule",
+	})
+
+	var runs []*entity.ScheduledTransferRun
+	var claimed []entity.ScheduledTransfer
+	var firstReclaimed int
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		scheduleTx := s.scheduleRepo.WithTransaction(tx)
+
+		due, err := scheduleTx.FindDueForUpdate(ctx, time.Now(), scheduleClaimLimit)
+		if err != nil {
+			return err
+		}
+
+		for i := range due {
+			schedule := &due[i]
+
+			// merchant queueing transfer outside window keeps schedule due until channel opens, no run is made yet
+			if executeAt, deferred := s.windowDeferral(ctx, schedule); deferred {
+				log.Infof("Channel is outside operating window, defer scheduled transfer %s to %s", schedule.ScheduleReference, timehelper.FormatTimeToISO7(executeAt))
+				schedule.NextRunAt = executeAt
+				if err := scheduleTx.Update(ctx, schedule); err != nil {
+					return err
+				}
+				continue
+			}
+
+			run := &entity.ScheduledTransferRun{
+				ScheduleId:         schedule.ID,
+				RunNumber:          schedule.RunCount + 1,
+				PartnerReferenceNo: s.runReference(schedule, schedule.RunCount+1),
+				Status:             constants.ScheduleRunClaimed,
+				ScheduledAt:        schedule.NextRunAt,
+				ClaimedAt:          time.Now(),
+			}
+			if err := scheduleTx.SaveRun(ctx, run); err != nil {
+				return err
+			}
+
+			// move schedule forward before execution
+			now := time.Now()
+			schedule.RunCount = run.RunNumber
+			schedule.LastRunAt = &now
+			schedule.NextRunAt = nextRunAt(schedule)
+			if schedule.Recurrence == constants.RecurrenceOnce || (schedule.EndAt != nil && schedule.NextRunAt.After(*schedule.EndAt)) {
+				schedule.Status = constants.ScheduleCompleted
+			}
+
+			if err := scheduleTx.Update(ctx, schedule); err != nil {
+				return err
+			}
+			claimed = append(claimed, *schedule)
+			runs = append(runs, run)
+		}
+		firstReclaimed = len(claimed)
+
+		// run left claimed by crashed instance, its schedule already moved forward
+		stale, err := scheduleTx.FindStaleRunsForUpdate(ctx, time.Now().Add(-s.claimTimeout), scheduleClaimLimit)
+		if err != nil {
+			return err
+		}
+
+		for i := range stale {
+			run := &stale[i]
+			schedule, err := scheduleTx.FindById(ctx, run.ScheduleId)
+			if err != nil {
+				return err
+			}
+			if schedule == nil {
+				continue
+			}
+
+			log.Warnf("Reclaim run %d of scheduled transfer %s claimed at %s", run.RunNumber, schedule.ScheduleReference, timehelper.FormatTimeToISO7(run.ClaimedAt))
+			run.ClaimedAt = time.Now()
+			if err := scheduleTx.UpdateRun(ctx, run); err != nil {
+				return err
+			}
+			claimed = append(claimed, *schedule)
+			runs = append(runs, run)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(claimed) > 0 {
+		log.Infof("Executing %d due scheduled transfer", len(claimed))
+	}
+
+	for i := range claimed {
+		s.execute(loghelper.NewContext(ctx, log.WithField("schedule_reference", claimed[i].ScheduleReference)), &claimed[i], runs[i], i >= firstReclaimed)
+	}
+	return nil
+}
+
+func (s *scheduleService) execute(ctx context.Context, schedule *entity.ScheduledTransfer, run *entity.ScheduledTransferRun, reclaimed bool) {
+	log := loghelper.FromContext(ctx)
+	var request dto.TransferRequest
+	if err := json.Unmarshal([]byte(schedule.Payload), &request); err != nil {
+		log.WithError(err).Error("Invalid stored scheduled transfer")
+		return
+	}
+
+	request.PartnerReferenceNo = run.PartnerReferenceNo
+	request.AdditionalInfo.TransactionDate = timehelper.FormatTimeToISO7(time.Now())
+	externalId := fmt.Sprintf("%s-%d", schedule.ScheduleReference, run.RunNumber)
+
+	// reclaimed run may have executed before its instance crashed
+	var existing []string
+	if reclaimed {
+		var err error
+		if existing, err = s.transferRepo.FindExistingReferences(ctx, schedule.MerchantCode, []string{run.PartnerReferenceNo}); err != nil {
+			log.WithError(err).Error("Failed to check transfer of reclaimed run, run is left for next claim")
+			return
+		}
+	}
+
+	var response dto.TransferResponse
+	if len(existing) > 0 {
+		log.Warnf("Run %d of scheduled transfer already executed, skipping", run.RunNumber)
+		response = dto.TransferResponse{ResponseCode: constants.PendingTransfer, ResponseMessage: constants.ResponseMap[constants.PendingTransfer]}
+	} else {
+		log.Infof("Execute run %d of scheduled transfer", run.RunNumber)
+		response = s.transferService.TransferRequest(ctx, request, schedule.MerchantCode, externalId)
+	}
+
+	executedAt := time.Now()
+	run.ExecutedAt = &executedAt
+	run.ResponseCode = response.ResponseCode
+	run.ResponseMessage = response.ResponseMessage
+	run.ReferenceNumber = response.ReferenceNumber
+	run.Status = constants.ScheduleRunAccepted
+	if response.ResponseCode != constants.PendingTransfer {
+		run.Status = constants.ScheduleRunFailed
+	}
+
+	if err := s.scheduleRepo.UpdateRun(ctx, run); err != nil {
+		log.WithError(err).Error("Failed to update scheduled transfer run")
+	}
+
+	failedOnce := run.Status == constants.ScheduleRunFailed && schedule.Recurrence == constants.RecurrenceOnce
+	if err := s.scheduleRepo.UpdateResult(ctx, schedule.ID, response.ResponseCode, response.ResponseMessage, failedOnce); err != nil {
+		log.WithError(err).Error("Failed to update scheduled transfer result")
+	}
+
+	if run.Status == constants.ScheduleRunFailed {
+		log.Warnf("Scheduled transfer failed with code %s: %s", response.ResponseCode, response.ResponseMessage)
+		s.notifyFailure(ctx, schedule, run)
+	}
+}
+
+// windowDeferral return opening of next window when channel of due schedule is closed and merchant prefers queueing,
+// merchant rejecting transfer outside window has the run executed and failed as usual
+func (s *scheduleService) windowDeferral(ctx context.Context, schedule *entity.ScheduledTransfer) (time.Time, bool) {
+	var request dto.TransferRequest
+	if err := json.Unmarshal([]byte(schedule.Payload), &request); err != nil {
+		return time.Time{}, false
+	}
+
+	executeAt, open := s.calendar.ExecutionTime(request.AdditionalInfo.Channel, time.Now())
+	if open || s.calendar.OutOfWindowAction(ctx, schedule.MerchantCode) != constants.OutOfWindowQueue {
+		return executeAt, false
+	}
+	return executeAt, true
+}
+
+func (s *scheduleService) notifyFailure(ctx context.Context, schedule *entity.ScheduledTransfer, run *entity.ScheduledTransferRun) {
+	log := loghelper.FromContext(ctx)
+	if s.notificationTopic == "" {
+		return
+	}
+
+	notification := dto.ScheduleNotification{
+		Event:              constants.EventScheduledTransferFailed,
+		ScheduleReference:  schedule.ScheduleReference,
+		MerchantCode:       schedule.MerchantCode,
+		PartnerReferenceNo: run.PartnerReferenceNo,
+		ResponseCode:       run.ResponseCode,
+		ResponseMessage:    run.ResponseMessage,
+		ExecutedAt:         timehelper.FormatTimeToISO7(*run.ExecutedAt),
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		log.WithError(err).Error("Failed to marshal scheduled transfer notification")
+		return
+	}
+
+	key := kafkahelper.MessageKey(schedule.MerchantCode, run.PartnerReferenceNo)
+	if err := s.kafkaProducer.Publish(ctx, s.notificationTopic, key, payload, kafkahelper.MessageHeaders{
+		MerchantCode:   schedule.MerchantCode,
+		SchemaVersion:  "1",
+		ContentType:    kafkahelper.ContentTypeJson,
+		IdempotencyKey: key,
+	}); err != nil {
+		log.WithError(err).Error("Failed to publish scheduled transfer failure notification")
+	}
+}
+
+func (s *scheduleService) buildSchedule(request dto.ScheduleTransferRequest, merchantCode string) (*entity.ScheduledTransfer, error) {
+	if err := validateTransferRow(request.Transfer); err != nil {
+		return nil, err
+	}
+
+	switch request.Recurrence {
+	case constants.RecurrenceOnce, constants.RecurrenceDaily, constants.RecurrenceWeekly, constants.RecurrenceMonthly:
+	default:
+		return nil, fmt.Errorf("unsupported recurrence %s", request.Recurrence)
+	}
+
+	executeAt, err := timehelper.ParseDateTime(request.ExecuteAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid execute at: %w", err)
+	}
+
+	if !executeAt.After(time.Now()) {
+		return nil, fmt.Errorf("execute at must be in the future")
+	}
+
+	var endAt *time.Time
+	if request.EndAt != "" {
+		end, err := timehelper.ParseDateTime(request.EndAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end at: %w", err)
+		}
+		if end.Before(executeAt) {
+			return nil, fmt.Errorf("end at must be after execute at")
+		}
+		endAt = &end
+	}
+
+	payload, err := json.Marshal(request.Transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ScheduledTransfer{
+		ScheduleReference:  request.ScheduleReference,
+		MerchantCode:       merchantCode,
+		PartnerReferenceNo: request.Transfer.PartnerReferenceNo,
+		Payload:            string(payload),
+		Recurrence:         request.Recurrence,
+		StartAt:            executeAt,
+		NextRunAt:          executeAt,
+		EndAt:              endAt,
+		Status:             constants.ScheduleActive,
+		CreatedAt:          time.Now(),
+		LastUpdated:        time.Now(),
+	}, nil
+}
+
+// runReference keep merchant reference for single execution, recurring run is suffixed with run number
+func (s *scheduleService) runReference(schedule *entity.ScheduledTransfer, runNumber int) string {
+	if schedule.Recurrence == constants.RecurrenceOnce {
+		return schedule.PartnerReferenceNo
+	}
+	return fmt.Sprintf("%s-%03d", schedule.PartnerReferenceNo, runNumber)
+}
+
+func (s *scheduleService) handleScheduleResponse(responseCode string, schedules ...entity.ScheduledTransfer) dto.ScheduleTransferResponse {
+	response := dto.ScheduleTransferResponse{
+		ResponseCode:    responseCode,
+		ResponseMessage: constants.ResponseMap[responseCode],
+		TransactionDate: timehelper.FormatTimeToISO7(time.Now()),
+		Schedules:       []dto.ScheduleInfo{},
+	}
+
+	for _, schedule := range schedules {
+		info := dto.ScheduleInfo{
+			ScheduleReference:   schedule.ScheduleReference,
+			PartnerReferenceNo:  schedule.PartnerReferenceNo,
+			Recurrence:          schedule.Recurrence,
+			Status:              schedule.Status,
+			RunCount:            schedule.RunCount,
+			LastResponseCode:    schedule.LastResponseCode,
+			LastResponseMessage: schedule.LastResponseMessage,
+		}
+		if schedule.Status == constants.ScheduleActive {
+			info.NextExecution = timehelper.FormatTimeToISO7(schedule.NextRunAt)
+		}
+		if schedule.LastRunAt != nil {
+			info.LastExecution = timehelper.FormatTimeToISO7(*schedule.LastRunAt)
+		}
+		if schedule.EndAt != nil {
+			info.EndAt = timehelper.FormatTimeToISO7(*schedule.EndAt)
+		}
+		response.Schedules = append(response.Schedules, info)
+	}
+	return response
+}
+
+// nextRunAt skip periods missed while scheduler was down. Every run keeps time of day of start and weekly schedule keeps
+// its weekday, so run deferred to next channel window does not shift later runs. Monthly schedule keeps day of start,
+// clamped to month end
+func nextRunAt(schedule *entity.ScheduledTransfer) time.Time {
+	next := schedule.NextRunAt.In(timehelper.WIB)
+	start := schedule.StartAt.In(timehelper.WIB)
+	now := time.Now()
+
+	for !next.After(now) {
+		switch schedule.Recurrence {
+		case constants.RecurrenceDaily:
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, start.Hour(), start.Minute(), start.Second(), 0, timehelper.WIB)
+		case constants.RecurrenceWeekly:
+			days := (int(start.Weekday()) - int(next.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			next = time.Date(next.Year(), next.Month(), next.Day()+days, start.Hour(), start.Minute(), start.Second(), 0, timehelper.WIB)
+		case constants.RecurrenceMonthly:
+			firstOfMonth := time.Date(next.Year(), next.Month()+1, 1, start.Hour(), start.Minute(), start.Second(), 0, timehelper.WIB)
+			lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+			next = firstOfMonth.AddDate(0, 0, min(start.Day(), lastDay)-1)
+		default:
+			return next
+		}
+	}
+	return next
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/timehelper"
+	"testing"
+	"time"
+)
+
+func TestNextRunAtKeepsRecurrenceAfterDeferredRun(t *testing.T) {
+	now := time.Now().In(timehelper.WIB)
+	// saturday 10:00 at least one week ago, run of that day deferred to monday 08:00 window
+	daysSinceSaturday := (int(now.Weekday()) - int(time.Saturday) + 7) % 7
+	start := time.Date(now.Year(), now.Month(), now.Day()-daysSinceSaturday-14, 10, 0, 0, 0, timehelper.WIB)
+	deferred := time.Date(start.Year(), start.Month(), start.Day()+2, 8, 0, 0, 0, timehelper.WIB)
+
+	for _, recurrence := range []string{constants.RecurrenceDaily, constants.RecurrenceWeekly} {
+		next := nextRunAt(&entity.ScheduledTransfer{Recurrence: recurrence, StartAt: start, NextRunAt: deferred})
+
+		if !next.After(now) || next.Hour() != 10 || next.Minute() != 0 {
+			t.Errorf("%s: next run %s, want future run at 10:00", recurrence, timehelper.FormatTimeToISO7(next))
+		}
+		if recurrence == constants.RecurrenceWeekly && next.Weekday() != time.Saturday {
+			t.Errorf("weekly: next run on %s, want saturday", next.Weekday())
+		}
+		if recurrence == constants.RecurrenceDaily && next.Sub(now) > 24*time.Hour {
+			t.Errorf("daily: next run %s is more than a day ahead", timehelper.FormatTimeToISO7(next))
+		}
+	}
+}
@@ -3,9 +3,11 @@ package service
 import (
 	"briefcash-transfer/internal/entity"
 	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/metrichelper"
 	"briefcash-transfer/internal/repository"
 	repositoryredis "briefcash-transfer/internal/repository/repository-redis"
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -18,25 +20,41 @@ import (
 type TransferRedisService interface {
 	LoadFeeSetting(ctx context.Context) error
 	LoadBalance(ctx context.Context) error
-	GetFeeSetting(ctx context.Context, merchantCode, channel string, log *logrus.Entry) (entity.FeeSettings, error)
-	SetFeeSetting(ctx context.Context, feeSetting entity.FeeSettings, log *logrus.Entry) error
-	DebitBalance(ctx context.Context, merchantCode string, amount float64, log *logrus.Entry) (float64, error)
-	RefundBalance(ctx context.Context, merchantCode string, amount float64, log *logrus.Entry) error
+	GetFeeSetting(ctx context.Context, merchantCode, channel string) (entity.FeeSettings, error)
+	SetFeeSetting(ctx context.Context, feeSetting entity.FeeSettings) error
+	FeeCacheLoaded(ctx context.Context) (bool, error)
+	DebitBalance(ctx context.Context, merchantCode string, amount float64) (float64, error)
+	RefundBalance(ctx context.Context, merchantCode string, amount float64) error
+	CreditBalance(ctx context.Context, merchantCode string, amount, databaseBalance float64) error
 }
 
+var (
+	ErrBalanceNotFound     = errors.New("merchant balance not found in redis")
+	ErrInsufficientBalance = errors.New("insufficient merchant balance")
+)
+
+const (
+	// creditAttempts bound retry of credit already committed to database, redis is left stale only after last attempt fails
+	creditAttempts = 3
+	creditBackoff  = 100 * time.Millisecond
+)
+
 type transferRedisService struct {
 	feeRepository      repository.FeeSettingRepository
 	merchantRepository repository.MerchantBalanceRepository
 	redisRepository    repositoryredis.RedisRepository
 	locker             *redsync.Redsync
+	lockExpiry         time.Duration
+	lockTries          int
 }
 
-func NewRedisService(feeRepository repository.FeeSettingRepository, merchantBalanceRepository repository.MerchantBalanceRepository, transferRedisRepository repositoryredis.RedisRepository, locker *redsync.Redsync) TransferRedisService {
-	return &transferRedisService{feeRepository, merchantBalanceRepository, transferRedisRepository, locker}
+func NewRedisService(feeRepository repository.FeeSettingRepository, merchantBalanceRepository repository.MerchantBalanceRepository, transferRedisRepository repositoryredis.RedisRepository, locker *redsync.Redsync,
+	lockExpiry time.Duration, lockTries int) TransferRedisService {
+	return &transferRedisService{feeRepository, merchantBalanceRepository, transferRedisRepository, locker, lockExpiry, lockTries}
 }
 
 func (r *transferRedisService) LoadFeeSetting(ctx context.Context) error {
-	log := loghelper.Logger.WithFields(logrus.Fields{
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
 		"service":   "redis_service",
 		"operation": "cache_fee_setting",
 	})
@@ -61,7 +79,7 @@ func (r *transferRedisService) LoadFeeSetting(ctx context.Context) error {
 }
 
 func (r *transferRedisService) LoadBalance(ctx context.Context) error {
-	log := loghelper.Logger.WithFields(logrus.Fields{
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
 		"service":   "redis_service",
 		"operation": "cache_balance",
 	})
@@ -85,7 +103,8 @@ func (r *transferRedisService) LoadBalance(ctx context.Context) error {
 	return nil
 }
 
-func (r *transferRedisService) GetFeeSetting(ctx context.Context, merchantCode, channel string, log *logrus.Entry) (entity.FeeSettings, error) {
+func (r *transferRedisService) GetFeeSetting(ctx context.Context, merchantCode, channel string) (entity.FeeSettings, error) {
+	log := loghelper.FromContext(ctx)
 	// Get service fee based on merchant code and payment channel
 	log.Infof("Fetch fee setting for merchant: %s and channel: %s", merchantCode, channel)
 	fee, err := r.redisRepository.FindByCodeAndChannel(ctx, merchantCode, channel)
@@ -96,7 +115,8 @@ func (r *transferRedisService) GetFeeSetting(ctx context.Context, merchantCode,
 	return fee, nil
 }
 
-func (r *transferRedisService) SetFeeSetting(ctx context.Context, feeSetting entity.FeeSettings, log *logrus.Entry) error {
+func (r *transferRedisService) SetFeeSetting(ctx context.Context, feeSetting entity.FeeSettings) error {
+	log := loghelper.FromContext(ctx)
 	// Create new service fee to redis
 	log.Infof("Caching fee setting for merchant: %s and channel: %s", feeSetting.MerchantCode, feeSetting.Channel)
 	if err := r.redisRepository.SetFee(ctx, feeSetting); err != nil {
@@ -106,17 +126,17 @@ func (r *transferRedisService) SetFeeSetting(ctx context.Context, feeSetting ent
 	return nil
 }
 
-func (r *transferRedisService) DebitBalance(ctx context.Context, merchantCode string, amount float64, log *logrus.Entry) (float64, error) {
-	// Set redis lock
-	keyLock := "lock:balance:" + merchantCode
-	mutex := r.locker.NewMutex(
-		keyLock,
-		redsync.WithExpiry(300*time.Millisecond),
-		redsync.WithTries(2),
-	)
+// FeeCacheLoaded reports whether fee setting still cached, redis may be flushed after startup
+func (r *transferRedisService) FeeCacheLoaded(ctx context.Context) (bool, error) {
+	return r.redisRepository.HasFeeSettings(ctx)
+}
 
-	if err := mutex.Lock(); err != nil {
-		return 0, fmt.Errorf("failed to start lock balance in redis %v", err)
+func (r *transferRedisService) DebitBalance(ctx context.Context, merchantCode string, amount float64) (float64, error) {
+	log := loghelper.FromContext(ctx)
+	// Set redis lock
+	mutex, err := r.lockBalance(merchantCode, "debit")
+	if err != nil {
+		return 0, err
 	}
 	defer func() {
 		_, _ = mutex.Unlock()
@@ -125,9 +145,9 @@ func (r *transferRedisService) DebitBalance(ctx context.Context, merchantCode st
 	// get current merchant balance from redis
 	log.Info("Fetching merchant balance from redis")
 	currentBalance, err := r.redisRepository.FindByMerchantCode(ctx, merchantCode)
-	if err == redis.Nil {
+	if errors.Is(err, redis.Nil) {
 		log.WithError(err).Errorf("Merchant %s not found in redis", merchantCode)
-		return 0, err
+		return 0, fmt.Errorf("%w: %s", ErrBalanceNotFound, merchantCode)
 	}
 
 	if err != nil {
@@ -139,17 +159,12 @@ func (r *transferRedisService) DebitBalance(ctx context.Context, merchantCode st
 	log.Info("Checking sufficiency merchant balance")
 	if currentBalance < amount {
 		log.Warnf("Insufficient balance: merchant: %s, balance: %.2f, needed %.2f", merchantCode, currentBalance, amount)
-		return -1, fmt.Errorf("insufficient merchant balance, current balance is %f", currentBalance)
+		return currentBalance, fmt.Errorf("%w, current balance is %f", ErrInsufficientBalance, currentBalance)
 	}
 
 	// calculate balance
 	newBalance := currentBalance - amount
 
-	if newBalance < 0 {
-		log.Warnf("Insufficient balance: merchant: %s, balance: %.2f", merchantCode, currentBalance)
-		return -1, fmt.Errorf("insufficient merchant balance, current balance is %f", currentBalance)
-	}
-
 	// update merchant CalculateBalanceRedis
 	log.Infof("Updating new balance to redis for merchant: %s", merchantCode)
 	if err := r.redisRepository.UpdateBalance(ctx, merchantCode, strconv.FormatFloat(newBalance, 'f', 2, 64)); err != nil {
@@ -160,16 +175,11 @@ func (r *transferRedisService) DebitBalance(ctx context.Context, merchantCode st
 	return newBalance, nil
 }
 
-func (r *transferRedisService) RefundBalance(ctx context.Context, merchantCode string, amount float64, log *logrus.Entry) error {
-	keyLock := "lock:balance:" + merchantCode
-	mutex := r.locker.NewMutex(
-		keyLock,
-		redsync.WithExpiry(300*time.Millisecond),
-		redsync.WithTries(2),
-	)
-
-	if err := mutex.Lock(); err != nil {
-		return fmt.Errorf("failed to start lock balance in redis %v", err)
+func (r *transferRedisService) RefundBalance(ctx context.Context, merchantCode string, amount float64) error {
+	log := loghelper.FromContext(ctx)
+	mutex, err := r.lockBalance(merchantCode, "refund")
+	if err != nil {
+		return err
 	}
 	defer func() {
 		_, _ = mutex.Unlock()
@@ -180,6 +190,56 @@ func (r *transferRedisService) RefundBalance(ctx context.Context, merchantCode s
 		log.WithError(err).Error("Failed to refund merchant balance in redis")
 		return err
 	}
+	metrichelper.CompensationTotal.WithLabelValues("redis", "refund").Inc()
 	log.Info("Refund balance successfully executed")
 	return nil
 }
+
+// CreditBalance bring redis in line with credit committed to database, merchant missing from cache is set to databaseBalance.
+// It is retried because database is already credited, error means redis is stale until balance is reloaded
+func (r *transferRedisService) CreditBalance(ctx context.Context, merchantCode string, amount, databaseBalance float64) error {
+	log := loghelper.FromContext(ctx)
+
+	var err error
+	for attempt := 1; attempt <= creditAttempts; attempt++ {
+		if err = r.creditOnce(ctx, merchantCode, amount, databaseBalance); err == nil {
+			log.Info("Credit balance successfully executed")
+			return nil
+		}
+		log.WithError(err).Warnf("Failed to credit merchant balance in redis, attempt %d of %d", attempt, creditAttempts)
+		if attempt < creditAttempts {
+			time.Sleep(creditBackoff * time.Duration(attempt))
+		}
+	}
+
+	metrichelper.BalanceSyncFailureTotal.WithLabelValues("credit").Inc()
+	return err
+}
+
+func (r *transferRedisService) creditOnce(ctx context.Context, merchantCode string, amount, databaseBalance float64) error {
+	mutex, err := r.lockBalance(merchantCode, "credit")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = mutex.Unlock()
+	}()
+
+	loghelper.FromContext(ctx).Infof("Credit merchant %s balance in redis, with %f added to balance", merchantCode, amount)
+	_, err = r.redisRepository.CreditBalance(ctx, merchantCode, amount, databaseBalance)
+	return err
+}
+
+func (r *transferRedisService) lockBalance(merchantCode, operation string) (*redsync.Mutex, error) {
+	mutex := r.locker.NewMutex(
+		"lock:balance:"+merchantCode,
+		redsync.WithExpiry(r.lockExpiry),
+		redsync.WithTries(r.lockTries),
+	)
+
+	if err := mutex.Lock(); err != nil {
+		metrichelper.LockFailureTotal.WithLabelValues(operation).Inc()
+		return nil, fmt.Errorf("failed to start lock balance in redis %v", err)
+	}
+	return mutex, nil
+}
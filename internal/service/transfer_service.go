@@ -4,25 +4,35 @@ import (
 	"briefcash-transfer/internal/constants"
 	"briefcash-transfer/internal/dto"
 	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/correlationhelper"
 	"briefcash-transfer/internal/helper/kafkahelper"
 	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/metrichelper"
 	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/helper/tracehelper"
 	"briefcash-transfer/internal/manager"
 	"briefcash-transfer/internal/protobuf"
 	"briefcash-transfer/internal/repository"
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
 	"google.golang.org/protobuf/proto"
 	"gorm.io/gorm"
 )
 
 type TransferService interface {
 	TransferRequest(ctx context.Context, request dto.TransferRequest, merchantCode, externalId string) dto.TransferResponse
+	TransferReserved(ctx context.Context, request dto.TransferRequest, feeSetting entity.FeeSettings, merchantCode, externalId string) dto.TransferResponse
+	GetFeeSetting(ctx context.Context, merchantCode, channel string) (entity.FeeSettings, error)
+	Drain(ctx context.Context) error
 }
 
 type transferService struct {
@@ -30,38 +40,129 @@ type transferService struct {
 	transferRepo   repository.TransferRepository
 	feeSettingRepo repository.FeeSettingRepository
 	ledgerRepo     repository.LedgerRepository
+	journalRepo    repository.JournalRepository
 	merchantRepo   repository.BalanceRepository
 	redisService   TransferRedisService
 	partnerService BankPartner
+	walletProvider WalletProviderService
+	calendar       CalendarService
 	db             *gorm.DB
 	kafkaProducer  *kafkahelper.KafkaProducer
+	inflight       *inflightTracker
 }
 
 func NewTransferService(recipientRepo repository.RecipientRepository, transferRepo repository.TransferRepository, feeSettingRepo repository.FeeSettingRepository,
-	ledgerRepo repository.LedgerRepository, merchantRepo repository.BalanceRepository, redisService TransferRedisService,
-	partnerService BankPartner, db *gorm.DB, kafkaProducer *kafkahelper.KafkaProducer) TransferService {
-	return &transferService{recipientRepo, transferRepo, feeSettingRepo, ledgerRepo, merchantRepo, redisService, partnerService, db, kafkaProducer}
+	ledgerRepo repository.LedgerRepository, journalRepo repository.JournalRepository, merchantRepo repository.BalanceRepository, redisService TransferRedisService,
+	partnerService BankPartner, walletProvider WalletProviderService, calendar CalendarService, db *gorm.DB, kafkaProducer *kafkahelper.KafkaProducer) TransferService {
+	return &transferService{recipientRepo, transferRepo, feeSettingRepo, ledgerRepo, journalRepo, merchantRepo, redisService, partnerService, walletProvider, calendar, db, kafkaProducer, newInflightTracker()}
 }
 
 func (t *transferService) TransferRequest(ctx context.Context, request dto.TransferRequest, merchantCode, externalId string) dto.TransferResponse {
-	log := loghelper.Logger.WithFields(logrus.Fields{
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
 		"service":   "transfer_service",
 		"operation": "initiate_request",
 		"bank_code": request.BeneficiaryBankCode,
 		"trace_id":  externalId,
 		"merchant":  merchantCode,
 	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	if !t.inflight.acquire() {
+		log.Warn("Transfer rejected, service is shutting down")
+		return t.handleTransferResponse(constants.ErrServiceUnavailable, constants.ResponseMap[constants.ErrServiceUnavailable], "", request.PartnerReferenceNo, "0", nil)
+	}
+	defer t.inflight.release()
+
+	if response, valid := t.checkClearingInfo(ctx, request); !valid {
+		return response
+	}
+
+	// channel must be within operating window, queueing is decided by caller
+	if response, open := t.checkWindow(ctx, request); !open {
+		return response
+	}
+
+	// get fee service charge from redis, fallback to database
+	feeSetting, err := t.GetFeeSetting(ctx, merchantCode, request.AdditionalInfo.Channel)
+	if err != nil {
+		return t.handleTransferResponse(constants.ErrDataNotFound, constants.ResponseMap[constants.ErrInternalServerError], "", request.PartnerReferenceNo, "0", &feeSetting)
+	}
+
+	// Total amount transfer and service fee
+	totalAmount := t.sumAmount(feeSetting, request.Amount.Value)
+
+	// subtract balance in redis
+	log.Info("Debit merchant balance in redis")
+	debitCtx, span := tracehelper.StartSpan(ctx, "transfer.debit_balance", attribute.String("merchant.code", merchantCode))
+	balance, err := t.redisService.DebitBalance(debitCtx, merchantCode, totalAmount)
+	tracehelper.EndSpan(span, err)
+
+	if errors.Is(err, ErrBalanceNotFound) {
+		return t.handleTransferResponse(constants.ErrBalanceNotAvailable, constants.ResponseMap[constants.ErrBalanceNotAvailable], "", request.PartnerReferenceNo, "0", &feeSetting)
+	}
+
+	if errors.Is(err, ErrInsufficientBalance) {
+		return t.handleTransferResponse(constants.ErrInsufficientFunds, constants.ResponseMap[constants.ErrInsufficientFunds], "", request.PartnerReferenceNo, "0", &feeSetting)
+	}
+
+	if err != nil {
+		return t.handleTransferResponse(constants.ErrInternalServerError, constants.ResponseMap[constants.ErrInternalServerError], "", request.PartnerReferenceNo, "0", &feeSetting)
+	}
+
+	return t.executeTransfer(ctx, request, feeSetting, merchantCode, externalId, balance, false)
+}
+
+// TransferReserved run transfer pipeline on balance already reserved in redis by caller, redis is not debited or refunded
+func (t *transferService) TransferReserved(ctx context.Context, request dto.TransferRequest, feeSetting entity.FeeSettings, merchantCode, externalId string) dto.TransferResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "transfer_service",
+		"operation": "initiate_reserved_request",
+		"bank_code": request.BeneficiaryBankCode,
+		"trace_id":  externalId,
+		"merchant":  merchantCode,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	if !t.inflight.acquire() {
+		log.Warn("Reserved transfer rejected, service is shutting down")
+		return t.handleTransferResponse(constants.ErrServiceUnavailable, constants.ResponseMap[constants.ErrServiceUnavailable], "", request.PartnerReferenceNo, "0", nil)
+	}
+	defer t.inflight.release()
+
+	if response, valid := t.checkClearingInfo(ctx, request); !valid {
+		return response
+	}
+
+	if response, open := t.checkWindow(ctx, request); !open {
+		return response
+	}
+
+	return t.executeTransfer(ctx, request, feeSetting, merchantCode, externalId, 0, true)
+}
+
+// Drain reject new transfer and wait for running ones, each of them either completes or compensates debited balance
+func (t *transferService) Drain(ctx context.Context) error {
+	return t.inflight.drain(ctx)
+}
+
+func (t *transferService) GetFeeSetting(ctx context.Context, merchantCode, channel string) (feeSetting entity.FeeSettings, err error) {
+	log := loghelper.FromContext(ctx)
+	ctx, span := tracehelper.StartSpan(ctx, "transfer.fee_lookup", attribute.String("merchant.code", merchantCode), attribute.String("transfer.channel", channel))
+	defer func() { tracehelper.EndSpan(span, err) }()
 
 	// get fee service charge from redis
 	log.Info("Get fee setting configuration from redis")
-	feeSetting, err := t.redisService.GetFeeSetting(ctx, merchantCode, request.AdditionalInfo.Channel, log)
-	if err != nil {
+	feeSetting, err = t.redisService.GetFeeSetting(ctx, merchantCode, channel)
+	if err == nil {
+		metrichelper.FeeCacheTotal.WithLabelValues("hit").Inc()
+	} else {
+		metrichelper.FeeCacheTotal.WithLabelValues("miss").Inc()
 		// fallback to databse if fee service charge not found in redis
 		log.Warn("Fee not found in redis, fallback to DB")
-		feeSettingDb, err := t.feeSettingRepo.FindByCodeAndChannel(ctx, merchantCode, request.AdditionalInfo.Channel)
+		feeSettingDb, err := t.feeSettingRepo.FindByCodeAndChannel(ctx, merchantCode, channel)
 		if err != nil {
 			log.WithError(err).Error("Fee not found in redis and DB")
-			return t.handleTransferResponse(constants.ErrDataNotFound, constants.ResponseMap[constants.ErrInternalServerError], "", request.PartnerReferenceNo, "0", &feeSettingDb)
+			return feeSettingDb, err
 		}
 
 		// if fee service charge found in database, create goroutine to save data back into redis
@@ -69,7 +170,7 @@ func (t *transferService) TransferRequest(ctx context.Context, request dto.Trans
 		errorChannel := make(chan error, 1)
 		var waitGroup sync.WaitGroup
 		waitGroup.Go(func() {
-			if err := t.redisService.SetFeeSetting(ctx, feeSettingDb, log); err != nil {
+			if err := t.redisService.SetFeeSetting(ctx, feeSettingDb); err != nil {
 				errorChannel <- err
 			}
 		})
@@ -85,65 +186,95 @@ func (t *transferService) TransferRequest(ctx context.Context, request dto.Trans
 		feeSetting = feeSettingDb
 	}
 
-	// Total amount transfer and service fee
-	totalAmount := t.sumAmount(feeSetting, request.Amount.Value)
-
-	// subtract balance in redis
-	log.Info("Debit merchant balance in redis")
-	balance, err := t.redisService.DebitBalance(ctx, merchantCode, totalAmount, log)
-
-	if balance == 0 {
-		return t.handleTransferResponse(constants.ErrBalanceNotAvailable, constants.ResponseMap[constants.ErrBalanceNotAvailable], "", request.PartnerReferenceNo, "0", &feeSetting)
-	}
-
-	if balance == -1 {
-		return t.handleTransferResponse(constants.ErrInsufficientFunds, constants.ResponseMap[constants.ErrInsufficientFunds], "", request.PartnerReferenceNo, "0", &feeSetting)
-	}
+	return feeSetting, nil
+}
 
-	if err != nil {
-		return t.handleTransferResponse(constants.ErrInternalServerError, constants.ResponseMap[constants.ErrInternalServerError], "", request.PartnerReferenceNo, "0", &feeSetting)
-	}
+func (t *transferService) executeTransfer(ctx context.Context, request dto.TransferRequest, feeSetting entity.FeeSettings, merchantCode, externalId string, balance float64, reserved bool) dto.TransferResponse {
+	log := loghelper.FromContext(ctx)
+	totalAmount := t.sumAmount(feeSetting, request.Amount.Value)
 
 	// save transfer and account statement into database
 	log.Info("Persist transfer, ledger, and updated balance to database")
 	referenceNumber := t.generatedReferenceNumber(request)
-	if err := t.PersistTransfer(ctx, request, feeSetting, merchantCode, referenceNumber, balance, totalAmount); err != nil {
-		log.Warn("Persist failed, refund merchant balance in redis")
-		if err := t.redisService.RefundBalance(ctx, merchantCode, totalAmount, log); err != nil {
-			return t.handleTransferResponse(constants.ErrInternalServerError, constants.ResponseMap[constants.ErrInternalServerError], "", request.PartnerReferenceNo, "0", &feeSetting)
+	transfer, err := t.PersistTransfer(ctx, request, feeSetting, merchantCode, referenceNumber, balance, totalAmount)
+	if err != nil {
+		if !reserved {
+			log.Warn("Persist failed, refund merchant balance in redis")
+			if err := t.redisService.RefundBalance(ctx, merchantCode, totalAmount); err != nil {
+				return t.handleTransferResponse(constants.ErrInternalServerError, constants.ResponseMap[constants.ErrInternalServerError], "", request.PartnerReferenceNo, "0", &feeSetting)
+			}
 		}
 		log.WithError(err).Error("Failed to persist transfer into database")
 		return t.handleTransferResponse(constants.ErrInternalServerError, constants.ResponseMap[constants.ErrInternalServerError], "", request.PartnerReferenceNo, "0", &feeSetting)
 	}
 
-	// publish trigger transfer to kafka
+	// publish trigger transfer to kafka, broker result is reported to handleDelivery
 	log.Info("Publish trigger transfer to kafka")
-	if err := t.publishMessage(request, externalId, log); err != nil {
-		log.Warn("Trigger transfer failed, refund merchant balance in redis")
-		if err := t.redisService.RefundBalance(ctx, merchantCode, totalAmount, log); err != nil {
+	deliveryCtx := context.WithoutCancel(ctx)
+	err = t.publishMessage(ctx, request, merchantCode, referenceNumber, externalId, func(err error) {
+		t.handleDelivery(deliveryCtx, transfer, request, merchantCode, totalAmount, err)
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed publish trigger transfer to kafka")
+		if err := t.compensatePublish(ctx, request, merchantCode, totalAmount, !reserved); err != nil {
 			return t.handleTransferResponse(constants.ErrInternalServerError, constants.ResponseMap[constants.ErrInternalServerError], "", request.PartnerReferenceNo, "0", &feeSetting)
 		}
 
-		log.Warn("Trigger transfer failed, update status transfer and refund balance in database")
-		if err := t.handlePublishFailure(ctx, merchantCode, request, totalAmount); err != nil {
-			return t.handleTransferResponse(constants.ErrInternalServerError, constants.ResponseMap[constants.ErrInternalServerError], "", request.PartnerReferenceNo, "0", &feeSetting)
+		// full buffer is backpressure, merchant retries once producer catches up
+		responseCode := constants.ErrInternalServerError
+		switch {
+		case errors.Is(err, kafkahelper.ErrBufferFull):
+			responseCode = constants.ErrServiceBusy
+		case errors.Is(err, kafkahelper.ErrProducerClosed):
+			responseCode = constants.ErrServiceUnavailable
 		}
-
-		log.WithError(err).Error("Failed publish trigger transfer to kafka")
-		return t.handleTransferResponse(constants.ErrInternalServerError, constants.ResponseMap[constants.ErrInternalServerError], "", request.PartnerReferenceNo, "0", &feeSetting)
+		return t.handleTransferResponse(responseCode, constants.ResponseMap[responseCode], "", request.PartnerReferenceNo, "0", &feeSetting)
 	}
 
 	// return response to handler
 	remainingBalance := strconv.FormatFloat(balance, 'f', 2, 64)
-	return t.handleTransferResponse(constants.PendingTransfer, constants.ResponseMap[constants.PendingTransfer], referenceNumber, request.PartnerReferenceNo, remainingBalance, &feeSetting)
+	if reserved {
+		remainingBalance = ""
+	}
+	response := t.handleTransferResponse(constants.PendingTransfer, constants.ResponseMap[constants.PendingTransfer], referenceNumber, request.PartnerReferenceNo, remainingBalance, &feeSetting)
+	response.AdditionalInfo["expected_execution_date"] = timehelper.FormatTimeToISO7(time.Now())
+	return response
 }
 
-func (t *transferService) PersistTransfer(ctx context.Context, request dto.TransferRequest, feeCharge entity.FeeSettings, merchantCode, referenceNumber string, balance, totalAmount float64) error {
-	return t.db.Transaction(func(tx *gorm.DB) error {
+// checkWindow reject transfer when channel is outside operating window, response carries the next window opening
+func (t *transferService) checkWindow(ctx context.Context, request dto.TransferRequest) (dto.TransferResponse, bool) {
+	log := loghelper.FromContext(ctx)
+	executeAt, open := t.calendar.ExecutionTime(request.AdditionalInfo.Channel, time.Now())
+	if open {
+		return dto.TransferResponse{}, true
+	}
+
+	log.Warnf("Channel %s is outside operating window, next window at %s", request.AdditionalInfo.Channel, timehelper.FormatTimeToISO7(executeAt))
+	response := t.handleTransferResponse(constants.ErrOutsideWindow, constants.ResponseMap[constants.ErrOutsideWindow], "", request.PartnerReferenceNo, "0", nil)
+	response.AdditionalInfo["expected_execution_date"] = timehelper.FormatTimeToISO7(executeAt)
+	return response, false
+}
+
+// checkClearingInfo reject SKN and RTGS transfer missing clearing data before any balance is debited
+func (t *transferService) checkClearingInfo(ctx context.Context, request dto.TransferRequest) (dto.TransferResponse, bool) {
+	if err := validateClearingInfo(request); err != nil {
+		loghelper.FromContext(ctx).WithError(err).Warn("Transfer rejected, invalid clearing info")
+		return t.handleTransferResponse(constants.ErrBadRequest, constants.ResponseMap[constants.ErrBadRequest]+", "+err.Error(), "", request.PartnerReferenceNo, "0", nil), false
+	}
+	return dto.TransferResponse{}, true
+}
+
+func (t *transferService) PersistTransfer(ctx context.Context, request dto.TransferRequest, feeCharge entity.FeeSettings, merchantCode, referenceNumber string, balance, totalAmount float64) (*entity.Transaction, error) {
+	ctx, span := tracehelper.StartSpan(ctx, "transfer.persist", attribute.String("transfer.reference", request.PartnerReferenceNo))
+
+	var transfer *entity.Transaction
+	start := time.Now()
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		transferTx := t.transferRepo.WithTransaction(tx)
 		ledgerTx := t.ledgerRepo.WithTransaction(tx)
 		recipientTx := t.recipientRepo.WithTransaction(tx)
 		accountTx := t.merchantRepo.WithTransaction(tx)
+		journalTx := t.journalRepo.WithTransaction(tx)
 
 		amountTransfer, err := parseAmount(request.Amount.Value)
 		if err != nil {
@@ -151,6 +282,8 @@ func (t *transferService) PersistTransfer(ctx context.Context, request dto.Trans
 		}
 
 		pm := manager.NewTransferPersistenceManager(ledgerTx, transferTx, recipientTx, accountTx)
+		jm := manager.NewJournalManager(journalTx)
+		sm := manager.NewTransferStateManager(transferTx)
 
 		// save recipient
 		recipient, err := pm.CreateRecipient(ctx, request)
@@ -159,11 +292,20 @@ func (t *transferService) PersistTransfer(ctx context.Context, request dto.Trans
 		}
 
 		// save transfer
-		transfer, err := pm.CreateTransfer(ctx, recipient, request, feeCharge, merchantCode, referenceNumber, amountTransfer)
+		transfer, err = pm.CreateTransfer(ctx, recipient, request, feeCharge, merchantCode, referenceNumber, amountTransfer)
 		if err != nil {
 			return err
 		}
 
+		if err := sm.Init(ctx, transfer, "transfer accepted", constants.ActorSystem); err != nil {
+			return err
+		}
+
+		// keep clearing data submitted by merchant for audit
+		if err := pm.CreateClearingDetail(ctx, transfer.ID, request); err != nil {
+			return err
+		}
+
 		// deduct balance
 		newBalance, err := pm.DebitMerchant(ctx, merchantCode, totalAmount)
 		if err != nil {
@@ -180,8 +322,65 @@ func (t *transferService) PersistTransfer(ctx context.Context, request dto.Trans
 			return err
 		}
 
+		// book double entry journal
+		if err := jm.PostTransferJournal(ctx, transfer, feeCharge); err != nil {
+			return err
+		}
+
 		return nil
 	})
+
+	result := "success"
+	if err != nil {
+		result = "failed"
+	}
+	metrichelper.PersistTransferDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+	tracehelper.EndSpan(span, err)
+	return transfer, err
+}
+
+// handleDelivery move transfer to pending once instruction is acknowledged, undelivered instruction is compensated.
+// With async producer it runs after response is returned, caller no longer owns the debited balance so redis is always refunded.
+func (t *transferService) handleDelivery(ctx context.Context, transfer *entity.Transaction, request dto.TransferRequest, merchantCode string, totalAmount float64, err error) {
+	log := loghelper.FromContext(ctx)
+	if err != nil {
+		log.WithError(err).Error("Trigger transfer was not delivered to kafka")
+		if err := t.compensatePublish(ctx, request, merchantCode, totalAmount, true); err != nil {
+			log.WithError(err).Error("Failed to compensate undelivered trigger transfer")
+		}
+		return
+	}
+
+	// instruction is with partner, status event may already have moved transfer forward. Transfer left created is moved
+	// by its first status event
+	err = t.markPublished(ctx, transfer)
+	switch {
+	case errors.Is(err, manager.ErrStaleTransfer):
+		log.Info("Transfer already moved forward by partner status")
+	case err != nil:
+		log.WithError(err).Warn("Failed to move transfer to pending after publish, first status event moves it")
+	}
+}
+
+// compensatePublish refund transfer whose instruction never reached kafka, redis is left to caller holding a reservation
+func (t *transferService) compensatePublish(ctx context.Context, request dto.TransferRequest, merchantCode string, totalAmount float64, refundRedis bool) error {
+	log := loghelper.FromContext(ctx)
+	if refundRedis {
+		log.Warn("Trigger transfer failed, refund merchant balance in redis")
+		if err := t.redisService.RefundBalance(ctx, merchantCode, totalAmount); err != nil {
+			return err
+		}
+	}
+
+	log.Warn("Trigger transfer failed, update status transfer and refund balance in database")
+	return t.handlePublishFailure(ctx, merchantCode, request, totalAmount)
+}
+
+func (t *transferService) markPublished(ctx context.Context, transfer *entity.Transaction) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		sm := manager.NewTransferStateManager(t.transferRepo.WithTransaction(tx))
+		return sm.Transition(ctx, transfer, constants.StatusPending, "instruction published to partner", constants.ActorSystem)
+	})
 }
 
 func (t *transferService) handleTransferResponse(responseCode, responseMessage, referenceNumber, partnerReferenceNo string, balanceAfter string, fee *entity.FeeSettings) dto.TransferResponse {
@@ -209,7 +408,17 @@ func (t *transferService) sumAmount(fee entity.FeeSettings, amount string) float
 	return totalCharge
 }
 
-func (t *transferService) publishMessage(request dto.TransferRequest, externalId string, log *logrus.Entry) error {
+func (t *transferService) publishMessage(ctx context.Context, request dto.TransferRequest, merchantCode, referenceNumber, externalId string, onDelivery kafkahelper.DeliveryCallback) error {
+	topic, routingCode, err := t.instructionTopic(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	currency := request.Amount.Currency
+	if currency == "" {
+		currency = "IDR"
+	}
+
 	payload := &protobuf.TransferRequest{
 		ExternalId:           externalId,
 		PartnerRefNo:         request.PartnerReferenceNo,
@@ -228,6 +437,21 @@ func (t *transferService) publishMessage(request dto.TransferRequest, externalId
 		TransferPurpose:      request.AdditionalInfo.TransferPurpose,
 		TransferActivity:     request.AdditionalInfo.TransferActivity,
 		CustomerType:         request.AdditionalInfo.CustomerType,
+		Currency:             currency,
+		MerchantCode:         merchantCode,
+		ReferenceNumber:      referenceNumber,
+		RoutingCode:          routingCode,
+		Envelope:             newEnvelope(ctx, protobuf.MessageTypeTransferRequest, protobuf.TransferRequestSchemaVersion),
+	}
+
+	if clearing := request.ClearingInfo; clearing != nil {
+		payload.ResidentStatus = clearing.ResidentStatus
+		payload.BeneficiaryType = clearing.BeneficiaryType
+		payload.BeneficiaryBankBranchName = clearing.BeneficiaryBankBranchName
+		payload.BeneficiaryBankCity = clearing.BeneficiaryBankCity
+		payload.BeneficiaryBankAddress = clearing.BeneficiaryBankAddress
+		payload.BeneficiaryAddress = clearing.BeneficiaryAddress
+		payload.DatiIi = clearing.DatiII
 	}
 
 	protoBytes, err := proto.Marshal(payload)
@@ -235,9 +459,46 @@ func (t *transferService) publishMessage(request dto.TransferRequest, externalId
 		return fmt.Errorf("failed marshal protobuf: %w", err)
 	}
 
-	topic := t.partnerService.GetBankConfig(request.BeneficiaryBankCode, log)
-	key := request.PartnerReferenceNo
-	return t.kafkaProducer.Publish(topic.KafkaTopic, key, protoBytes)
+	key := kafkahelper.MessageKey(merchantCode, request.PartnerReferenceNo)
+	return t.kafkaProducer.PublishAsync(ctx, topic, key, protoBytes, kafkahelper.MessageHeaders{
+		ExternalId:     externalId,
+		MerchantCode:   merchantCode,
+		SchemaVersion:  strconv.FormatUint(uint64(protobuf.TransferRequestSchemaVersion), 10),
+		ContentType:    kafkahelper.ContentTypeProtobuf,
+		IdempotencyKey: key,
+	}, onDelivery)
+}
+
+// instructionTopic route wallet topup to topic of its provider, bank transfer to topic of beneficiary bank partner,
+// along with code of the partner or provider instruction is routed to
+func (t *transferService) instructionTopic(ctx context.Context, request dto.TransferRequest) (string, string, error) {
+	if request.AdditionalInfo.Channel != constants.ChannelWallet {
+		bank := t.partnerService.GetBankConfig(ctx, request.BeneficiaryBankCode)
+		return bank.KafkaTopic, bank.BankCode, nil
+	}
+
+	provider, ok := t.walletProvider.GetWalletProvider(ctx, request.BeneficiaryBankCode)
+	if !ok {
+		return "", "", fmt.Errorf("wallet provider %s: %w", request.BeneficiaryBankCode, repository.ErrRecordNotFound)
+	}
+	return provider.KafkaTopic, provider.WalletCode, nil
+}
+
+// newEnvelope describe outgoing message, trace headers let consumer continue trace even when kafka headers are dropped
+func newEnvelope(ctx context.Context, messageType string, schemaVersion uint32) *protobuf.Envelope {
+	traceHeaders := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, traceHeaders)
+	if correlationId := correlationhelper.FromContext(ctx); correlationId != "" {
+		traceHeaders[correlationhelper.HeaderCorrelationId] = correlationId
+	}
+
+	return &protobuf.Envelope{
+		MessageType:   messageType,
+		SchemaVersion: schemaVersion,
+		MessageId:     correlationhelper.New(),
+		TraceHeaders:  traceHeaders,
+		CreatedAt:     timehelper.FormatTimeToISO7(time.Now()),
+	}
 }
 
 func (t *transferService) generatedReferenceNumber(request dto.TransferRequest) string {
@@ -260,26 +521,29 @@ func (r *transferService) handlePublishFailure(ctx context.Context, merchantCode
 		ledgerTx := r.ledgerRepo.WithTransaction(tx)
 		accountTx := r.merchantRepo.WithTransaction(tx)
 		transferTx := r.transferRepo.WithTransaction(tx)
+		journalTx := r.journalRepo.WithTransaction(tx)
 
 		pm := manager.NewTransferPersistenceManager(ledgerTx, transferTx, nil, accountTx)
+		jm := manager.NewJournalManager(journalTx)
+		sm := manager.NewTransferStateManager(transferTx)
 
 		// get transfer Data
-		transfer, err := pm.FindTransferByPartnerReference(ctx, request.PartnerReferenceNo)
+		transfer, err := pm.FindTransferByPartnerReference(ctx, merchantCode, request.PartnerReferenceNo)
 		if err != nil {
 			return err
 		}
 
+		if transfer == nil {
+			return fmt.Errorf("transfer %s: %w", request.PartnerReferenceNo, repository.ErrRecordNotFound)
+		}
+
 		// guard idempotency
 		if transfer.Status == constants.StatusFailedPublish {
 			return nil
 		}
 
-		if transfer.Status == constants.ResponseMap[constants.PendingTransfer] {
-			return fmt.Errorf("invalid state transition from %s", transfer.Status)
-		}
-
 		// Update transfer status
-		if err := pm.UpdateTransferStatus(ctx, transfer.ID, constants.StatusFailedPublish); err != nil {
+		if err := sm.Transition(ctx, transfer, constants.StatusFailedPublish, "failed to publish instruction to partner", constants.ActorSystem); err != nil {
 			return err
 		}
 
@@ -294,6 +558,12 @@ func (r *transferService) handlePublishFailure(ctx context.Context, merchantCode
 			return err
 		}
 
+		// reverse double entry journal
+		if err := jm.PostReversalJournal(ctx, transfer.ID, transfer.ID, request.PartnerReferenceNo, constants.JournalRefund); err != nil {
+			return err
+		}
+
+		metrichelper.CompensationTotal.WithLabelValues("database", "publish_failed").Inc()
 		return nil
 	})
 }
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/manager"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var errStatusUnchanged = errors.New("transfer already in reported status")
+
+// partnerStatuses are the status partner bank may report, true when merchant is notified by webhook
+var partnerStatuses = map[string]bool{
+	constants.StatusInProgress: false,
+	constants.StatusDone:       true,
+	constants.StatusRejected:   true,
+	constants.StatusTimeout:    true,
+	constants.StatusRefunded:   true,
+}
+
+type TransferStatusService interface {
+	ApplyStatus(ctx context.Context, event dto.TransferStatusEvent) string
+}
+
+type transferStatusService struct {
+	transferRepo repository.TransferRepository
+	webhookRepo  repository.WebhookRepository
+	db           *gorm.DB
+}
+
+func NewTransferStatusService(transferRepo repository.TransferRepository, webhookRepo repository.WebhookRepository, db *gorm.DB) TransferStatusService {
+	return &transferStatusService{transferRepo, webhookRepo, db}
+}
+
+// ApplyStatus move transfer through state machine and enqueue merchant webhook in the same transaction
+func (t *transferStatusService) ApplyStatus(ctx context.Context, event dto.TransferStatusEvent) string {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":            "transfer_status_service",
+		"operation":          "apply_status",
+		"partner_reference":  event.PartnerReferenceNo,
+		"reference_number":   event.ReferenceNumber,
+		"transfer_status_to": event.Status,
+	})
+
+	notify, ok := partnerStatuses[event.Status]
+	if event.PartnerReferenceNo == "" || event.ReferenceNumber == "" || !ok {
+		log.Warn("Partner reference, reference number and valid status are mandatory")
+		return constants.ErrBadRequest
+	}
+
+	reason := event.Reason
+	if reason == "" {
+		reason = "reported by partner bank"
+	}
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		transferTx := t.transferRepo.WithTransaction(tx)
+		sm := manager.NewTransferStateManager(transferTx)
+		wm := manager.NewWebhookManager(t.webhookRepo.WithTransaction(tx))
+
+		// lock transfer by our reference, partner reference of other merchant must not match it
+		transfer, err := transferTx.FindForUpdateByReferenceNumber(ctx, event.ReferenceNumber)
+		if err != nil {
+			return err
+		}
+
+		if transfer == nil || transfer.PartnerReferenceNo != event.PartnerReferenceNo {
+			return errTransferNotFound
+		}
+
+		// guard redelivered status event
+		if transfer.Status == event.Status {
+			return errStatusUnchanged
+		}
+
+		// status event raced ahead of publish acknowledgement or marking it failed, partner report proves instruction was delivered
+		if transfer.Status == constants.StatusCreated {
+			if err := sm.Transition(ctx, transfer, constants.StatusPending, "instruction acknowledged by partner status", constants.ActorSystem); err != nil {
+				return err
+			}
+		}
+
+		previousStatus := transfer.Status
+		if err := sm.Transition(ctx, transfer, event.Status, reason, constants.ActorPartnerStatus); err != nil {
+			return err
+		}
+
+		if !notify {
+			return nil
+		}
+		return wm.EnqueueStatusChange(ctx, transfer, previousStatus, event)
+	})
+
+	switch {
+	case errors.Is(err, errTransferNotFound):
+		log.Warn("Transfer not found")
+		return constants.ErrDataNotFound
+	case errors.Is(err, errStatusUnchanged):
+		log.Warn("Transfer already in reported status, status event ignored")
+		return constants.ErrInvalidStatus
+	case errors.Is(err, manager.ErrInvalidTransition):
+		log.WithError(err).Warn("Status transition not allowed, status event ignored")
+		return constants.ErrInvalidStatus
+	case err != nil:
+		log.WithError(err).Error("Failed to apply transfer status")
+		return constants.ErrInternalServerError
+	}
+
+	log.Infof("Transfer status updated to %s", event.Status)
+	return constants.TransferSuccess
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"testing"
+)
+
+func TestApplyStatusRejectsIncompleteEvent(t *testing.T) {
+	svc := &transferStatusService{}
+	tests := []struct {
+		name  string
+		event dto.TransferStatusEvent
+	}{
+		{"without reference number", dto.TransferStatusEvent{PartnerReferenceNo: "MRC-0001", Status: constants.StatusDone}},
+		{"without partner reference", dto.TransferStatusEvent{ReferenceNumber: "bif-014-20250101101530000", Status: constants.StatusDone}},
+		{"unknown status", dto.TransferStatusEvent{PartnerReferenceNo: "MRC-0001", ReferenceNumber: "bif-014-20250101101530000", Status: "SETTLED"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := svc.ApplyStatus(testContext(), tt.event); got != constants.ErrBadRequest {
+				t.Errorf("ApplyStatus() = %s, want %s", got, constants.ErrBadRequest)
+			}
+		})
+	}
+}
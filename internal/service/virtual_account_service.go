@@ -0,0 +1,210 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/amounthelper"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/metrichelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	vaMinLength = 8
+	vaMaxLength = 28
+)
+
+// vaTrxType map SNAP virtualAccountTrxType to our VA type, anything else is validated as closed
+var vaTrxType = map[string]string{
+	"C": constants.VirtualAccountClosed,
+	"O": constants.VirtualAccountOpen,
+}
+
+type VirtualAccountService interface {
+	Inquiry(ctx context.Context, request dto.VAInquiryRequest, merchantCode, externalId string) dto.VAInquiryResponse
+	Payment(ctx context.Context, request dto.VAPaymentRequest, merchantCode, externalId string) dto.TransferResponse
+}
+
+type virtualAccountService struct {
+	partnerService  BankPartner
+	transferService TransferService
+	client          *http.Client
+}
+
+func NewVirtualAccountService(partnerService BankPartner, transferService TransferService, inquiryTimeout time.Duration) VirtualAccountService {
+	return &virtualAccountService{partnerService, transferService, &http.Client{Timeout: inquiryTimeout}}
+}
+
+// Inquiry return bill of virtual account along with service fee merchant is charged when paying it
+func (v *virtualAccountService) Inquiry(ctx context.Context, request dto.VAInquiryRequest, merchantCode, externalId string) dto.VAInquiryResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "virtual_account_service",
+		"operation": "inquiry",
+		"bank_code": request.BeneficiaryBankCode,
+		"trace_id":  externalId,
+		"merchant":  merchantCode,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	if !validVirtualAccount(request.VirtualAccountNo) || request.BeneficiaryBankCode == "" {
+		log.Warnf("Virtual account number must be %d to %d digits and bank code is mandatory", vaMinLength, vaMaxLength)
+		return v.handleInquiryResponse(constants.ErrBadRequest, request, nil)
+	}
+
+	// VA without dedicated fee setting cannot be paid, fail early instead of after inquiry
+	feeSetting, err := v.transferService.GetFeeSetting(ctx, merchantCode, constants.ChannelVA)
+	if err != nil {
+		return v.handleInquiryResponse(constants.ErrDataNotFound, request, nil)
+	}
+
+	bill, responseCode := v.inquire(ctx, request.BeneficiaryBankCode, request.VirtualAccountNo, externalId)
+	response := v.handleInquiryResponse(responseCode, request, bill)
+	if bill != nil {
+		response.AdditionalInfo["channel"] = feeSetting.Channel
+		response.AdditionalInfo["service_fee"] = strconv.FormatFloat(feeSetting.TotalCharge, 'f', 2, 64)
+	}
+	return response
+}
+
+// Payment inquire bill again so amount is validated against current bill, then run it through transfer pipeline on va channel
+func (v *virtualAccountService) Payment(ctx context.Context, request dto.VAPaymentRequest, merchantCode, externalId string) dto.TransferResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "virtual_account_service",
+		"operation": "payment",
+		"bank_code": request.BeneficiaryBankCode,
+		"trace_id":  externalId,
+		"merchant":  merchantCode,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	if !validVirtualAccount(request.VirtualAccountNo) || request.BeneficiaryBankCode == "" || request.PartnerReferenceNo == "" {
+		log.Warn("Virtual account number, bank code and partner reference are mandatory")
+		return v.handlePaymentResponse(constants.ErrBadRequest, request)
+	}
+
+	amount, err := parseAmount(request.Amount.Value)
+	if err != nil || amount <= 0 {
+		log.Warnf("Invalid payment amount %s", request.Amount.Value)
+		return v.handlePaymentResponse(constants.ErrInvalidAmount, request)
+	}
+
+	bill, responseCode := v.inquire(ctx, request.BeneficiaryBankCode, request.VirtualAccountNo, externalId)
+	if bill == nil {
+		return v.handlePaymentResponse(responseCode, request)
+	}
+
+	vaType, billAmount := v.billType(bill)
+	if vaType == constants.VirtualAccountClosed && amounthelper.ToCent(amount) != amounthelper.ToCent(billAmount) {
+		log.Warnf("Closed virtual account must be paid exactly %.2f, requested %.2f", billAmount, amount)
+		return v.handlePaymentResponse(constants.ErrInvalidAmount, request)
+	}
+
+	log.Infof("Pay %s virtual account through transfer pipeline", strings.ToLower(vaType))
+	response := v.transferService.TransferRequest(ctx, dto.TransferRequest{
+		PartnerReferenceNo:       request.PartnerReferenceNo,
+		CustomerNumber:           request.CustomerNumber,
+		BeneficiaryAccountNumber: request.VirtualAccountNo,
+		BeneficiaryBankCode:      request.BeneficiaryBankCode,
+		Amount:                   request.Amount,
+		AdditionalInfo: dto.TransferRequestInfo{
+			TransactionDate:   request.AdditionalInfo.TransactionDate,
+			CustomerReference: request.AdditionalInfo.CustomerReference,
+			Channel:           constants.ChannelVA,
+			Remarks:           request.AdditionalInfo.Remarks,
+			Email:             request.AdditionalInfo.Email,
+		},
+	}, merchantCode, externalId)
+
+	response.AdditionalInfo["virtual_account_name"] = bill.VirtualAccountName
+	response.AdditionalInfo["virtual_account_type"] = vaType
+	return response
+}
+
+// inquire call partner gateway of destination bank, bill is nil when virtual account cannot be paid
+func (v *virtualAccountService) inquire(ctx context.Context, bankCode, virtualAccountNo, externalId string) (*dto.VAInquiryPartnerData, string) {
+	log := loghelper.FromContext(ctx)
+	start := time.Now()
+
+	bank := v.partnerService.GetBankConfig(ctx, bankCode)
+	if bank.InquiryUrl == "" {
+		log.Errorf("Bank partner %s has no inquiry url configured", bank.BankCode)
+		return nil, constants.ErrExternalServerError
+	}
+
+	log.Infof("Inquire virtual account to %s", bank.BankName)
+	var response dto.VAInquiryPartnerResponse
+	err := postInquiry(ctx, v.client, bank.InquiryUrl, externalId, dto.VAInquiryPartnerRequest{
+		VirtualAccountNo:    virtualAccountNo,
+		BeneficiaryBankCode: bankCode,
+		InquiryRequestId:    externalId,
+		TrxDateInit:         timehelper.FormatTimeToISO7(time.Now()),
+	}, &response)
+
+	result, responseCode := inquiryResult(err, response.ResponseCode, constants.ErrInvalidBill)
+	metrichelper.VAInquiryDuration.WithLabelValues(bankCode, result).Observe(time.Since(start).Seconds())
+
+	if responseCode != constants.TransferSuccess {
+		log.WithError(err).Warnf("Virtual account inquiry %s, partner response %s %s", result, response.ResponseCode, response.ResponseMessage)
+		return nil, responseCode
+	}
+	return &response.VirtualAccountData, responseCode
+}
+
+func (v *virtualAccountService) billType(bill *dto.VAInquiryPartnerData) (string, float64) {
+	vaType, ok := vaTrxType[bill.VirtualAccountTrxType]
+	if !ok {
+		vaType = constants.VirtualAccountClosed
+	}
+	billAmount, _ := parseAmount(bill.TotalAmount.Value)
+	return vaType, billAmount
+}
+
+func (v *virtualAccountService) handleInquiryResponse(responseCode string, request dto.VAInquiryRequest, bill *dto.VAInquiryPartnerData) dto.VAInquiryResponse {
+	response := dto.VAInquiryResponse{
+		ResponseCode:       responseCode,
+		ResponseMessage:    constants.ResponseMap[responseCode],
+		PartnerReferenceNo: request.PartnerReferenceNo,
+		VirtualAccountNo:   request.VirtualAccountNo,
+		BillDetails:        []dto.BCAVABillDetails{},
+		AdditionalInfo:     map[string]string{},
+	}
+
+	if bill != nil {
+		response.VirtualAccountType, _ = v.billType(bill)
+		response.VirtualAccountName = bill.VirtualAccountName
+		response.BillAmount = bill.TotalAmount
+		if bill.BillDetails != nil {
+			response.BillDetails = bill.BillDetails
+		}
+	}
+	return response
+}
+
+func (v *virtualAccountService) handlePaymentResponse(responseCode string, request dto.VAPaymentRequest) dto.TransferResponse {
+	return dto.TransferResponse{
+		ResponseCode:       responseCode,
+		ResponseMessage:    constants.ResponseMap[responseCode],
+		PartnerReferenceNo: request.PartnerReferenceNo,
+		TransactionDate:    timehelper.FormatTimeToISO7(time.Now()),
+		AdditionalInfo:     map[string]string{},
+	}
+}
+
+func validVirtualAccount(virtualAccountNo string) bool {
+	if len(virtualAccountNo) < vaMinLength || len(virtualAccountNo) > vaMaxLength {
+		return false
+	}
+	for _, digit := range virtualAccountNo {
+		if digit < '0' || digit > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type WalletProviderService interface {
+	LoadWalletProviders(ctx context.Context) error
+	GetWalletProvider(ctx context.Context, walletCode string) (entity.WalletProvider, bool)
+	CheckLimit(ctx context.Context, walletCode string, amount float64) string
+}
+
+type walletProviderService struct {
+	rwMutex       sync.RWMutex
+	providerRepo  repository.WalletProviderRepository
+	providerCache map[string]entity.WalletProvider
+}
+
+func NewWalletProviderService(providerRepo repository.WalletProviderRepository) WalletProviderService {
+	return &walletProviderService{
+		providerRepo: providerRepo,
+	}
+}
+
+func (s *walletProviderService) LoadWalletProviders(ctx context.Context) error {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "wallet_provider_service",
+		"operation": "load_config",
+	})
+
+	log.Info("Collect wallet provider config from database")
+	providers, err := s.providerRepo.FindAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to collect wallet provider config from database")
+		return err
+	}
+
+	log.Infof("Cache wallet provider config to memory, with total data %d", len(providers))
+	s.rwMutex.Lock()
+	s.providerCache = make(map[string]entity.WalletProvider)
+	for _, provider := range providers {
+		s.providerCache[provider.WalletCode] = provider
+	}
+	s.rwMutex.Unlock()
+	return nil
+}
+
+// GetWalletProvider return active provider of wallet code, unlike bank partner there is no fallback provider
+func (s *walletProviderService) GetWalletProvider(ctx context.Context, walletCode string) (entity.WalletProvider, bool) {
+	s.rwMutex.RLock()
+	provider, ok := s.providerCache[walletCode]
+	s.rwMutex.RUnlock()
+
+	if !ok || !provider.IsActive {
+		loghelper.FromContext(ctx).Warnf("Wallet provider %s not found or inactive", walletCode)
+		return entity.WalletProvider{}, false
+	}
+	return provider, true
+}
+
+// CheckLimit validate amount against per transaction limit of provider, zero limit means unlimited
+func (s *walletProviderService) CheckLimit(ctx context.Context, walletCode string, amount float64) string {
+	provider, ok := s.GetWalletProvider(ctx, walletCode)
+	if !ok {
+		return constants.ErrDataNotFound
+	}
+
+	if amount < provider.MinAmount || (provider.MaxAmount > 0 && amount > provider.MaxAmount) {
+		loghelper.FromContext(ctx).Warnf("Amount %.2f outside %s limit %.2f - %.2f", amount, provider.ProviderName, provider.MinAmount, provider.MaxAmount)
+		return constants.ErrAmountLimit
+	}
+	return constants.TransferSuccess
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/metrichelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	walletMinPhoneLength = 10
+	walletMaxPhoneLength = 15
+)
+
+type WalletService interface {
+	Inquiry(ctx context.Context, request dto.WalletInquiryRequest, merchantCode, externalId string) dto.WalletInquiryResponse
+	Topup(ctx context.Context, request dto.WalletTopupRequest, merchantCode, externalId string) dto.TransferResponse
+}
+
+type walletService struct {
+	providerService WalletProviderService
+	transferService TransferService
+	client          *http.Client
+}
+
+func NewWalletService(providerService WalletProviderService, transferService TransferService, inquiryTimeout time.Duration) WalletService {
+	return &walletService{providerService, transferService, &http.Client{Timeout: inquiryTimeout}}
+}
+
+// Inquiry return name of wallet account along with service fee merchant is charged when topping it up
+func (w *walletService) Inquiry(ctx context.Context, request dto.WalletInquiryRequest, merchantCode, externalId string) dto.WalletInquiryResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":     "wallet_service",
+		"operation":   "inquiry",
+		"wallet_code": request.WalletCode,
+		"trace_id":    externalId,
+		"merchant":    merchantCode,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	customerNumber, ok := normalizePhone(request.CustomerNumber)
+	if !ok {
+		log.Warnf("Customer number must be phone number of %d to %d digits", walletMinPhoneLength, walletMaxPhoneLength)
+		return w.handleInquiryResponse(constants.ErrBadRequest, request, "")
+	}
+	request.CustomerNumber = customerNumber
+
+	feeSetting, err := w.transferService.GetFeeSetting(ctx, merchantCode, constants.ChannelWallet)
+	if err != nil {
+		return w.handleInquiryResponse(constants.ErrDataNotFound, request, "")
+	}
+
+	customerName, responseCode := w.inquire(ctx, request.WalletCode, customerNumber, request.PartnerReferenceNo, "0.00", externalId)
+	response := w.handleInquiryResponse(responseCode, request, customerName)
+	if responseCode == constants.TransferSuccess {
+		response.AdditionalInfo["channel"] = feeSetting.Channel
+		response.AdditionalInfo["service_fee"] = strconv.FormatFloat(feeSetting.TotalCharge, 'f', 2, 64)
+	}
+	return response
+}
+
+// Topup validate provider limit and wallet account, then run it through transfer pipeline on wallet channel
+func (w *walletService) Topup(ctx context.Context, request dto.WalletTopupRequest, merchantCode, externalId string) dto.TransferResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":     "wallet_service",
+		"operation":   "topup",
+		"wallet_code": request.WalletCode,
+		"trace_id":    externalId,
+		"merchant":    merchantCode,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	customerNumber, ok := normalizePhone(request.CustomerNumber)
+	if !ok || request.PartnerReferenceNo == "" {
+		log.Warn("Valid customer phone number and partner reference are mandatory")
+		return w.handleTopupResponse(constants.ErrBadRequest, request)
+	}
+
+	amount, err := parseAmount(request.Amount.Value)
+	if err != nil || amount <= 0 {
+		log.Warnf("Invalid topup amount %s", request.Amount.Value)
+		return w.handleTopupResponse(constants.ErrInvalidAmount, request)
+	}
+
+	if responseCode := w.providerService.CheckLimit(ctx, request.WalletCode, amount); responseCode != constants.TransferSuccess {
+		return w.handleTopupResponse(responseCode, request)
+	}
+
+	// provider rejects topup to unregistered or frozen account at inquiry, before merchant balance is debited
+	customerName, responseCode := w.inquire(ctx, request.WalletCode, customerNumber, request.PartnerReferenceNo, request.Amount.Value, externalId)
+	if responseCode != constants.TransferSuccess {
+		return w.handleTopupResponse(responseCode, request)
+	}
+
+	log.Info("Top up wallet through transfer pipeline")
+	response := w.transferService.TransferRequest(ctx, dto.TransferRequest{
+		PartnerReferenceNo:       request.PartnerReferenceNo,
+		CustomerNumber:           customerNumber,
+		BeneficiaryAccountNumber: customerNumber,
+		BeneficiaryBankCode:      request.WalletCode,
+		Amount:                   request.Amount,
+		AdditionalInfo: dto.TransferRequestInfo{
+			TransactionDate:   request.AdditionalInfo.TransactionDate,
+			CustomerReference: request.AdditionalInfo.CustomerReference,
+			Channel:           constants.ChannelWallet,
+			Remarks:           request.AdditionalInfo.Remarks,
+		},
+	}, merchantCode, externalId)
+
+	response.AdditionalInfo["customer_name"] = customerName
+	return response
+}
+
+func (w *walletService) inquire(ctx context.Context, walletCode, customerNumber, partnerReferenceNo, amount, externalId string) (string, string) {
+	log := loghelper.FromContext(ctx)
+	start := time.Now()
+
+	provider, ok := w.providerService.GetWalletProvider(ctx, walletCode)
+	if !ok {
+		return "", constants.ErrDataNotFound
+	}
+	if provider.InquiryUrl == "" {
+		log.Errorf("Wallet provider %s has no inquiry url configured", provider.ProviderName)
+		return "", constants.ErrExternalServerError
+	}
+
+	log.Infof("Inquire wallet account to %s", provider.ProviderName)
+	var response dto.WalletInquiryPartnerResponse
+	err := postInquiry(ctx, w.client, provider.InquiryUrl, externalId, dto.WalletInquiryPartnerRequest{
+		PartnerReferenceNo: partnerReferenceNo,
+		CustomerNumber:     customerNumber,
+		Amount:             dto.TransferAmountData{Value: amount, Currency: "IDR"},
+		TransactionDate:    timehelper.FormatTimeToISO7(time.Now()),
+		AdditionalInfo:     map[string]string{"walletCode": walletCode},
+	}, &response)
+
+	result, responseCode := inquiryResult(err, response.ResponseCode, constants.ErrDataNotFound)
+	metrichelper.WalletInquiryDuration.WithLabelValues(walletCode, result).Observe(time.Since(start).Seconds())
+
+	if responseCode != constants.TransferSuccess {
+		log.WithError(err).Warnf("Wallet inquiry %s, provider response %s %s", result, response.ResponseCode, response.ResponseMessage)
+		return "", responseCode
+	}
+	return response.CustomerName, responseCode
+}
+
+func (w *walletService) handleInquiryResponse(responseCode string, request dto.WalletInquiryRequest, customerName string) dto.WalletInquiryResponse {
+	return dto.WalletInquiryResponse{
+		ResponseCode:       responseCode,
+		ResponseMessage:    constants.ResponseMap[responseCode],
+		PartnerReferenceNo: request.PartnerReferenceNo,
+		WalletCode:         request.WalletCode,
+		CustomerNumber:     request.CustomerNumber,
+		CustomerName:       customerName,
+		AdditionalInfo:     map[string]string{},
+	}
+}
+
+func (w *walletService) handleTopupResponse(responseCode string, request dto.WalletTopupRequest) dto.TransferResponse {
+	return dto.TransferResponse{
+		ResponseCode:       responseCode,
+		ResponseMessage:    constants.ResponseMap[responseCode],
+		PartnerReferenceNo: request.PartnerReferenceNo,
+		TransactionDate:    timehelper.FormatTimeToISO7(time.Now()),
+		AdditionalInfo:     map[string]string{},
+	}
+}
+
+// normalizePhone convert 08xx, 628xx and +628xx into 628xx wallet providers expect
+func normalizePhone(phone string) (string, bool) {
+	phone = strings.TrimPrefix(strings.TrimSpace(phone), "+")
+	if strings.HasPrefix(phone, "0") {
+		phone = "62" + phone[1:]
+	}
+
+	if !strings.HasPrefix(phone, "628") || len(phone) < walletMinPhoneLength || len(phone) > walletMaxPhoneLength {
+		return "", false
+	}
+	for _, digit := range phone {
+		if digit < '0' || digit > '9' {
+			return "", false
+		}
+	}
+	return phone, true
+}
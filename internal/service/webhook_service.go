@@ -0,0 +1,382 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/dto"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/timehelper"
+	"briefcash-transfer/internal/repository"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	webhookClaimLimit    = 50
+	webhookClaimLease    = 2 * time.Minute
+	webhookTimeout       = 10 * time.Second
+	webhookMaxBackoff    = time.Hour
+	webhookResponseLimit = 1024
+	webhookListLimit     = 100
+	webhookMinSecret     = 16
+	webhookWorkerActor   = "webhook-worker"
+)
+
+type WebhookService interface {
+	RegisterEndpoint(ctx context.Context, merchantCode string, request dto.WebhookEndpointRequest) dto.WebhookResponse
+	ListDeliveries(ctx context.Context, merchantCode, status string) dto.WebhookResponse
+	GetDelivery(ctx context.Context, id int64) dto.WebhookResponse
+	Resend(ctx context.Context, id int64, actor string) dto.WebhookResponse
+	Start(ctx context.Context, interval time.Duration)
+	DeliverDue(ctx context.Context) error
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	db          *gorm.DB
+	client      *http.Client
+	maxAttempts int
+	backoffBase time.Duration
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, db *gorm.DB, maxAttempts int, backoffBase time.Duration) WebhookService {
+	return &webhookService{webhookRepo, db, &http.Client{Timeout: webhookTimeout}, maxAttempts, backoffBase}
+}
+
+func (w *webhookService) RegisterEndpoint(ctx context.Context, merchantCode string, request dto.WebhookEndpointRequest) dto.WebhookResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "webhook_service",
+		"operation": "register_endpoint",
+		"merchant":  merchantCode,
+	})
+
+	callbackUrl, err := url.Parse(request.CallbackUrl)
+	if err != nil || (callbackUrl.Scheme != "https" && callbackUrl.Scheme != "http") || callbackUrl.Host == "" {
+		log.Warn("Invalid webhook callback url")
+		return w.handleWebhookResponse(constants.ErrBadRequest)
+	}
+
+	if merchantCode == "" || len(request.Secret) < webhookMinSecret {
+		log.Warnf("Merchant code and signing secret of at least %d characters are mandatory", webhookMinSecret)
+		return w.handleWebhookResponse(constants.ErrBadRequest)
+	}
+
+	isActive := true
+	if request.IsActive != nil {
+		isActive = *request.IsActive
+	}
+
+	now := time.Now()
+	log.Info("Persist merchant webhook endpoint")
+	if err := w.webhookRepo.SaveEndpoint(ctx, &entity.MerchantWebhook{
+		MerchantCode: merchantCode,
+		CallbackUrl:  request.CallbackUrl,
+		Secret:       request.Secret,
+		IsActive:     isActive,
+		CreatedAt:    now,
+		LastUpdated:  now,
+	}); err != nil {
+		log.WithError(err).Error("Failed to persist merchant webhook endpoint")
+		return w.handleWebhookResponse(constants.ErrInternalServerError)
+	}
+
+	return w.handleWebhookResponse(constants.TransferSuccess)
+}
+
+func (w *webhookService) ListDeliveries(ctx context.Context, merchantCode, status string) dto.WebhookResponse {
+	log := loghelper.FromContext(ctx)
+	log.Info("Fetch webhook deliveries from database")
+	deliveries, err := w.webhookRepo.FindDeliveries(ctx, merchantCode, status, webhookListLimit)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch webhook deliveries")
+		return w.handleWebhookResponse(constants.ErrInternalServerError)
+	}
+
+	response := w.handleWebhookResponse(constants.TransferSuccess)
+	for i := range deliveries {
+		response.Deliveries = append(response.Deliveries, w.deliveryInfo(&deliveries[i], nil))
+	}
+	return response
+}
+
+func (w *webhookService) GetDelivery(ctx context.Context, id int64) dto.WebhookResponse {
+	log := loghelper.FromContext(ctx).WithField("delivery_id", id)
+
+	delivery, err := w.webhookRepo.FindDelivery(ctx, id)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch webhook delivery")
+		return w.handleWebhookResponse(constants.ErrInternalServerError)
+	}
+
+	if delivery == nil {
+		return w.handleWebhookResponse(constants.ErrDataNotFound)
+	}
+
+	logs, err := w.webhookRepo.FindLogs(ctx, id)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch webhook delivery log")
+		return w.handleWebhookResponse(constants.ErrInternalServerError)
+	}
+
+	response := w.handleWebhookResponse(constants.TransferSuccess)
+	response.Deliveries = append(response.Deliveries, w.deliveryInfo(delivery, logs))
+	return response
+}
+
+// Resend deliver immediately regardless of current status, retry counter starts over
+func (w *webhookService) Resend(ctx context.Context, id int64, actor string) dto.WebhookResponse {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":     "webhook_service",
+		"operation":   "resend_webhook",
+		"delivery_id": id,
+		"actor":       actor,
+	})
+	ctx = loghelper.NewContext(ctx, log)
+
+	var delivery *entity.WebhookDelivery
+	err := w.db.Transaction(func(tx *gorm.DB) error {
+		webhookTx := w.webhookRepo.WithTransaction(tx)
+
+		var err error
+		delivery, err = webhookTx.FindDeliveryForUpdate(ctx, id)
+		if err != nil || delivery == nil {
+			return err
+		}
+
+		// lease the row so worker does not pick it up concurrently
+		delivery.Status = constants.WebhookPending
+		delivery.Attempts = 0
+		delivery.DeadLetteredAt = nil
+		delivery.NextAttemptAt = time.Now().Add(webhookClaimLease)
+		return webhookTx.UpdateDelivery(ctx, delivery)
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to claim webhook delivery")
+		return w.handleWebhookResponse(constants.ErrInternalServerError)
+	}
+
+	if delivery == nil {
+		return w.handleWebhookResponse(constants.ErrDataNotFound)
+	}
+
+	log.Info("Re-send webhook to merchant")
+	w.deliver(ctx, delivery, actor)
+
+	return w.GetDelivery(ctx, id)
+}
+
+// Start poll pending deliveries until context is cancelled
+func (w *webhookService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// leased deliveries are posted and recorded before Start returns
+			if err := w.DeliverDue(context.WithoutCancel(ctx)); err != nil {
+				loghelper.FromContext(ctx).WithError(err).Error("Failed to deliver pending webhook")
+			}
+		}
+	}
+}
+
+// DeliverDue claim due deliveries with a lease and post them outside database transaction
+func (w *webhookService) DeliverDue(ctx context.Context) error {
+	log := loghelper.FromContext(ctx).WithFields(logrus.Fields{
+		"service":   "webhook_service",
+		"operation": "deliver_webhook",
+	})
+
+	var claimed []entity.WebhookDelivery
+	err := w.db.Transaction(func(tx *gorm.DB) error {
+		webhookTx := w.webhookRepo.WithTransaction(tx)
+
+		due, err := webhookTx.FindDueForUpdate(ctx, time.Now(), webhookClaimLimit)
+		if err != nil {
+			return err
+		}
+
+		for i := range due {
+			due[i].NextAttemptAt = time.Now().Add(webhookClaimLease)
+			if err := webhookTx.UpdateDelivery(ctx, &due[i]); err != nil {
+				return err
+			}
+		}
+
+		claimed = due
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(claimed) > 0 {
+		log.Infof("Delivering %d pending webhook", len(claimed))
+	}
+
+	var waitGroup sync.WaitGroup
+	for i := range claimed {
+		delivery := &claimed[i]
+		waitGroup.Go(func() {
+			w.deliver(loghelper.NewContext(ctx, log.WithField("delivery_id", delivery.ID)), delivery, webhookWorkerActor)
+		})
+	}
+	waitGroup.Wait()
+	return nil
+}
+
+func (w *webhookService) deliver(ctx context.Context, delivery *entity.WebhookDelivery, actor string) {
+	log := loghelper.FromContext(ctx)
+	delivery.Attempts++
+	attempt := &entity.WebhookDeliveryLog{
+		DeliveryId: delivery.ID,
+		Attempt:    delivery.Attempts,
+		Actor:      actor,
+		CreatedAt:  time.Now(),
+	}
+
+	endpoint, err := w.webhookRepo.FindEndpoint(ctx, delivery.MerchantCode)
+	switch {
+	case err != nil:
+		attempt.Error = err.Error()
+	case endpoint == nil || !endpoint.IsActive:
+		attempt.Error = "merchant webhook endpoint is not active"
+	default:
+		attempt.CallbackUrl = endpoint.CallbackUrl
+		attempt.HttpStatus, attempt.ResponseBody, err = w.post(ctx, endpoint, delivery)
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+	}
+	attempt.DurationMs = time.Since(attempt.CreatedAt).Milliseconds()
+
+	now := time.Now()
+	delivery.LastHttpStatus = attempt.HttpStatus
+	delivery.LastError = attempt.Error
+	switch {
+	case attempt.Error == "":
+		delivery.Status = constants.WebhookDelivered
+		delivery.DeliveredAt = &now
+		log.Infof("Webhook delivered on attempt %d", delivery.Attempts)
+	case delivery.Attempts >= w.maxAttempts:
+		delivery.Status = constants.WebhookDeadLetter
+		delivery.DeadLetteredAt = &now
+		log.Errorf("Webhook moved to dead letter after %d attempts: %s", delivery.Attempts, attempt.Error)
+	default:
+		delivery.NextAttemptAt = now.Add(w.backoff(delivery.Attempts))
+		log.Warnf("Webhook attempt %d failed, retry at %s: %s", delivery.Attempts, timehelper.FormatTimeToISO7(delivery.NextAttemptAt), attempt.Error)
+	}
+
+	if err := w.webhookRepo.SaveLog(ctx, attempt); err != nil {
+		log.WithError(err).Error("Failed to save webhook delivery log")
+	}
+
+	if err := w.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		log.WithError(err).Error("Failed to update webhook delivery")
+	}
+}
+
+// post send signed payload, any non 2xx response is treated as failure
+func (w *webhookService) post(ctx context.Context, endpoint *entity.MerchantWebhook, delivery *entity.WebhookDelivery) (int, string, error) {
+	timestamp := timehelper.FormatTimeToISO7(time.Now())
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.CallbackUrl, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to build webhook request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-TIMESTAMP", timestamp)
+	request.Header.Set("X-SIGNATURE", SignWebhook(endpoint.Secret, timestamp, []byte(delivery.Payload)))
+	request.Header.Set("X-EVENT-ID", strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set("X-EVENT-TYPE", delivery.EventType)
+	request.Header.Set("X-PARTNER-ID", delivery.MerchantCode)
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to call merchant webhook: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, webhookResponseLimit))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, string(body), fmt.Errorf("merchant webhook responded with http status %d", response.StatusCode)
+	}
+	return response.StatusCode, string(body), nil
+}
+
+// backoff double wait time on every failed attempt, capped to one hour
+func (w *webhookService) backoff(attempts int) time.Duration {
+	wait := w.backoffBase
+	for i := 1; i < attempts && wait < webhookMaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, webhookMaxBackoff)
+}
+
+func (w *webhookService) deliveryInfo(delivery *entity.WebhookDelivery, logs []entity.WebhookDeliveryLog) dto.WebhookDeliveryInfo {
+	info := dto.WebhookDeliveryInfo{
+		DeliveryId:         delivery.ID,
+		MerchantCode:       delivery.MerchantCode,
+		Event:              delivery.EventType,
+		PartnerReferenceNo: delivery.PartnerReferenceNo,
+		Status:             delivery.Status,
+		Attempts:           delivery.Attempts,
+		LastHttpStatus:     delivery.LastHttpStatus,
+		LastError:          delivery.LastError,
+		CreatedAt:          timehelper.FormatTimeToISO7(delivery.CreatedAt),
+	}
+	if delivery.Status == constants.WebhookPending {
+		info.NextAttemptAt = timehelper.FormatTimeToISO7(delivery.NextAttemptAt)
+	}
+	if delivery.DeliveredAt != nil {
+		info.DeliveredAt = timehelper.FormatTimeToISO7(*delivery.DeliveredAt)
+	}
+
+	for _, log := range logs {
+		info.Logs = append(info.Logs, dto.WebhookAttemptInfo{
+			Attempt:      log.Attempt,
+			CallbackUrl:  log.CallbackUrl,
+			HttpStatus:   log.HttpStatus,
+			ResponseBody: log.ResponseBody,
+			Error:        log.Error,
+			DurationMs:   log.DurationMs,
+			Actor:        log.Actor,
+			AttemptedAt:  timehelper.FormatTimeToISO7(log.CreatedAt),
+		})
+	}
+	return info
+}
+
+func (w *webhookService) handleWebhookResponse(responseCode string) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ResponseCode:    responseCode,
+		ResponseMessage: constants.ResponseMap[responseCode],
+		TransactionDate: timehelper.FormatTimeToISO7(time.Now()),
+		Deliveries:      []dto.WebhookDeliveryInfo{},
+	}
+}
+
+// SignWebhook compute hex encoded HMAC-SHA256 of timestamp and body joined by dot, merchant verifies with the same secret
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
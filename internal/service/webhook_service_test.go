@@ -0,0 +1,250 @@
+package service
+
+import (
+	"briefcash-transfer/internal/constants"
+	"briefcash-transfer/internal/entity"
+	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/repository"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testWebhookSecret = "merchant-signing-secret"
+
+// stubWebhookRepository keep endpoint, logs and updates in memory, methods not used by deliver are left unimplemented
+type stubWebhookRepository struct {
+	repository.WebhookRepository
+	mu       sync.Mutex
+	endpoint *entity.MerchantWebhook
+	logs     []entity.WebhookDeliveryLog
+	updated  []entity.WebhookDelivery
+}
+
+func (s *stubWebhookRepository) FindEndpoint(ctx context.Context, merchantCode string) (*entity.MerchantWebhook, error) {
+	return s.endpoint, nil
+}
+
+func (s *stubWebhookRepository) SaveLog(ctx context.Context, log *entity.WebhookDeliveryLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs = append(s.logs, *log)
+	return nil
+}
+
+func (s *stubWebhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updated = append(s.updated, *delivery)
+	return nil
+}
+
+// receivedWebhook is a request captured by merchant server
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func newMerchantServer(t *testing.T, statuses ...int) (*httptest.Server, *[]receivedWebhook) {
+	t.Helper()
+	var mu sync.Mutex
+	var received []receivedWebhook
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, receivedWebhook{header: r.Header.Clone(), body: body})
+		status := statuses[min(len(received), len(statuses))-1]
+		mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"status":"received"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func newTestWebhookService(repo *stubWebhookRepository, maxAttempts int) *webhookService {
+	return &webhookService{webhookRepo: repo, client: &http.Client{Timeout: time.Second}, maxAttempts: maxAttempts, backoffBase: time.Minute}
+}
+
+func testContext() context.Context {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return loghelper.NewContext(context.Background(), logrus.NewEntry(logger))
+}
+
+func testDelivery(attempts int) *entity.WebhookDelivery {
+	return &entity.WebhookDelivery{
+		ID:                 42,
+		MerchantCode:       "MRC001",
+		EventType:          "transfer.done",
+		PartnerReferenceNo: "MRC-20250101-0001",
+		Payload:            `{"event":"transfer.done","partnerReferenceNo":"MRC-20250101-0001"}`,
+		Status:             constants.WebhookPending,
+		Attempts:           attempts,
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	timestamp := "2025-01-01T10:00:00+07:00"
+	body := []byte(`{"event":"transfer.done"}`)
+
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if got := SignWebhook(testWebhookSecret, timestamp, body); got != expected {
+		t.Fatalf("signature mismatch, got %s want %s", got, expected)
+	}
+	if SignWebhook("other-signing-secret", timestamp, body) == expected {
+		t.Fatal("signature must depend on secret")
+	}
+	if SignWebhook(testWebhookSecret, "2025-01-01T10:00:01+07:00", body) == expected {
+		t.Fatal("signature must depend on timestamp")
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	svc := &webhookService{backoffBase: time.Minute}
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{6, 32 * time.Minute},
+		{7, time.Hour},
+		{20, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := svc.backoff(tt.attempts); got != tt.expected {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.expected)
+		}
+	}
+}
+
+func TestDeliverSignedWebhook(t *testing.T) {
+	server, received := newMerchantServer(t, http.StatusOK)
+	repo := &stubWebhookRepository{endpoint: &entity.MerchantWebhook{MerchantCode: "MRC001", CallbackUrl: server.URL, Secret: testWebhookSecret, IsActive: true}}
+	svc := newTestWebhookService(repo, 3)
+
+	delivery := testDelivery(0)
+	svc.deliver(testContext(), delivery, webhookWorkerActor)
+
+	if len(*received) != 1 {
+		t.Fatalf("merchant received %d request, want 1", len(*received))
+	}
+	request := (*received)[0]
+	timestamp := request.header.Get("X-TIMESTAMP")
+	if timestamp == "" {
+		t.Fatal("X-TIMESTAMP header is missing")
+	}
+	if got, want := request.header.Get("X-SIGNATURE"), SignWebhook(testWebhookSecret, timestamp, request.body); got != want {
+		t.Errorf("X-SIGNATURE = %s, want %s", got, want)
+	}
+	if got := request.header.Get("X-EVENT-ID"); got != "42" {
+		t.Errorf("X-EVENT-ID = %s, want 42", got)
+	}
+	if got := request.header.Get("X-PARTNER-ID"); got != "MRC001" {
+		t.Errorf("X-PARTNER-ID = %s, want MRC001", got)
+	}
+	if string(request.body) != delivery.Payload {
+		t.Errorf("body = %s, want %s", request.body, delivery.Payload)
+	}
+
+	if delivery.Status != constants.WebhookDelivered || delivery.DeliveredAt == nil || delivery.Attempts != 1 {
+		t.Errorf("delivery status %s attempts %d, want delivered on first attempt", delivery.Status, delivery.Attempts)
+	}
+	if len(repo.logs) != 1 || repo.logs[0].HttpStatus != http.StatusOK || repo.logs[0].Error != "" || repo.logs[0].Actor != webhookWorkerActor {
+		t.Errorf("unexpected delivery log %+v", repo.logs)
+	}
+	if len(repo.updated) != 1 {
+		t.Errorf("delivery updated %d times, want 1", len(repo.updated))
+	}
+}
+
+func TestDeliverRetryThenDeadLetter(t *testing.T) {
+	server, received := newMerchantServer(t, http.StatusInternalServerError)
+	repo := &stubWebhookRepository{endpoint: &entity.MerchantWebhook{MerchantCode: "MRC001", CallbackUrl: server.URL, Secret: testWebhookSecret, IsActive: true}}
+	svc := newTestWebhookService(repo, 3)
+	delivery := testDelivery(0)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		before := time.Now()
+		svc.deliver(testContext(), delivery, webhookWorkerActor)
+
+		if delivery.Status != constants.WebhookPending || delivery.Attempts != attempt {
+			t.Fatalf("attempt %d: status %s attempts %d, want pending", attempt, delivery.Status, delivery.Attempts)
+		}
+		wait := svc.backoff(attempt)
+		if delivery.NextAttemptAt.Before(before.Add(wait)) || delivery.NextAttemptAt.After(time.Now().Add(wait)) {
+			t.Errorf("attempt %d: next attempt at %s, want about %s from now", attempt, delivery.NextAttemptAt, wait)
+		}
+		if delivery.LastHttpStatus != http.StatusInternalServerError || delivery.LastError == "" {
+			t.Errorf("attempt %d: last status %d error %q", attempt, delivery.LastHttpStatus, delivery.LastError)
+		}
+	}
+
+	svc.deliver(testContext(), delivery, webhookWorkerActor)
+	if delivery.Status != constants.WebhookDeadLetter || delivery.DeadLetteredAt == nil {
+		t.Fatalf("status %s after %d attempts, want dead letter", delivery.Status, delivery.Attempts)
+	}
+	if len(*received) != 3 {
+		t.Errorf("merchant received %d request, want 3", len(*received))
+	}
+
+	if len(repo.logs) != 3 {
+		t.Fatalf("saved %d delivery log, want 3", len(repo.logs))
+	}
+	for i, log := range repo.logs {
+		if log.Attempt != i+1 || log.HttpStatus != http.StatusInternalServerError || log.ResponseBody == "" {
+			t.Errorf("log %d: unexpected attempt %+v", i, log)
+		}
+	}
+	if last := repo.updated[len(repo.updated)-1]; last.Status != constants.WebhookDeadLetter {
+		t.Errorf("dead letter store has status %s, want %s", last.Status, constants.WebhookDeadLetter)
+	}
+}
+
+func TestDeliverRecoverAfterRetry(t *testing.T) {
+	server, received := newMerchantServer(t, http.StatusBadGateway, http.StatusOK)
+	repo := &stubWebhookRepository{endpoint: &entity.MerchantWebhook{MerchantCode: "MRC001", CallbackUrl: server.URL, Secret: testWebhookSecret, IsActive: true}}
+	svc := newTestWebhookService(repo, 3)
+	delivery := testDelivery(0)
+
+	svc.deliver(testContext(), delivery, webhookWorkerActor)
+	svc.deliver(testContext(), delivery, webhookWorkerActor)
+
+	if delivery.Status != constants.WebhookDelivered || delivery.Attempts != 2 {
+		t.Fatalf("status %s attempts %d, want delivered on second attempt", delivery.Status, delivery.Attempts)
+	}
+	if len(*received) != 2 {
+		t.Errorf("merchant received %d request, want 2", len(*received))
+	}
+}
+
+func TestDeliverInactiveEndpoint(t *testing.T) {
+	server, received := newMerchantServer(t, http.StatusOK)
+	repo := &stubWebhookRepository{endpoint: &entity.MerchantWebhook{MerchantCode: "MRC001", CallbackUrl: server.URL, Secret: testWebhookSecret, IsActive: false}}
+	svc := newTestWebhookService(repo, 1)
+	delivery := testDelivery(0)
+
+	svc.deliver(testContext(), delivery, webhookWorkerActor)
+
+	if len(*received) != 0 {
+		t.Errorf("inactive endpoint received %d request", len(*received))
+	}
+	if delivery.Status != constants.WebhookDeadLetter || delivery.LastError == "" {
+		t.Errorf("status %s error %q, want dead letter with error", delivery.Status, delivery.LastError)
+	}
+}
@@ -2,32 +2,58 @@ package main
 
 import (
 	"briefcash-transfer/config"
+	"briefcash-transfer/internal/consumer"
 	"briefcash-transfer/internal/controller"
 	"briefcash-transfer/internal/helper/dbhelper"
 	"briefcash-transfer/internal/helper/kafkahelper"
 	"briefcash-transfer/internal/helper/loghelper"
+	"briefcash-transfer/internal/helper/metrichelper"
 	"briefcash-transfer/internal/helper/redishelper"
+	"briefcash-transfer/internal/helper/tracehelper"
+	"briefcash-transfer/internal/middleware"
 	"briefcash-transfer/internal/repository"
 	repositoryredis "briefcash-transfer/internal/repository/repository-redis"
 	"briefcash-transfer/internal/service"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func main() {
-	loghelper.InitLogger("./resource/app.log", logrus.InfoLevel)
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path of yaml config file, environment variables override it")
+	printConfig := flag.Bool("print-config", false, "print effective configuration with secrets redacted and exit")
+	flag.Parse()
+
+	if *printConfig {
+		os.Exit(printEffectiveConfig(*configPath))
+	}
+
+	if flag.Arg(0) == "dlq" {
+		os.Exit(runDeadLetterCommand(*configPath, flag.Args()[1:]))
+	}
+
+	loghelper.InitLogger(config.LoadLogConfig())
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// workers get their own context so they are stopped only after http server stops accepting requests
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+	var workers sync.WaitGroup
+	var consumers []*kafkahelper.KafkaConsumer
+
 	go func() {
 		signalChannel := make(chan os.Signal, 1)
 		signal.Notify(signalChannel, syscall.SIGINT, syscall.SIGTERM)
@@ -36,31 +62,39 @@ func main() {
 		cancel()
 	}()
 
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfig(*configPath)
 	if err != nil {
-		loghelper.Logger.Fatal("Failed to load credential configuration")
+		loghelper.Logger.WithError(err).Fatal("Failed to load configuration")
 	}
 
-	dbConfig := dbhelper.DBConfig{
-		Hostname: cfg.DBHost, Port: cfg.DBPort, DBname: cfg.DBName,
-		Username: cfg.DBUsername, Password: cfg.DBPassword, SslMode: "disable",
+	shutdownTracer, err := tracehelper.InitTracer(ctx, tracehelper.TracerConfig{
+		Exporter: cfg.TraceExporter, Endpoint: cfg.TraceEndpoint,
+		ServiceName: tracehelper.TracerName, SampleRatio: cfg.TraceSampleRatio,
+	})
+	if err != nil {
+		loghelper.Logger.WithError(err).Fatal("Failed to initialize tracing")
 	}
 
+	dbConfig := newDBConfig(cfg)
 	dbCon, err := dbhelper.NewDBConfig(dbConfig)
 	if err != nil {
 		loghelper.Logger.WithError(err).Fatal("Failed to established connection to databases")
 	}
-	defer dbCon.Close()
 
 	redisClient, err := redishelper.NewRedisHelper(cfg)
 	if err != nil {
 		loghelper.Logger.WithError(err).Fatal("Failed to established connection to redis server")
 	}
-	defer redisClient.Close()
 	redsync := redishelper.NewRedsync(redisClient.Client)
 
-	kafkaAddres := fmt.Sprintf("%s:%s", cfg.KafkaHost, cfg.KafkaPort)
-	kafkaService, err := kafkahelper.NewKafkaProducer([]string{kafkaAddres})
+	sqlDb, err := dbCon.DB.DB()
+	if err != nil {
+		loghelper.Logger.WithError(err).Fatal("Failed to get database connection pool")
+	}
+	metrichelper.RegisterPoolCollector(redisClient.Client, sqlDb)
+
+	kafkaConfig := newKafkaConfig(cfg)
+	kafkaService, err := kafkahelper.NewKafkaProducer(kafkaConfig)
 	if err != nil {
 		loghelper.Logger.WithError(err).Fatal("Failed to establish kafka server")
 	}
@@ -73,13 +107,37 @@ func main() {
 	merchantRepo := repository.NewMerchantBalanceRepository(dbCon.DB)
 	partnerRepo := repository.NewPartnerRepository(dbCon.DB)
 	transferRepo := repository.NewTransferRepository(dbCon.DB)
+	depositRepo := repository.NewDepositRepository(dbCon.DB)
+	journalRepo := repository.NewJournalRepository(dbCon.DB)
+	batchRepo := repository.NewBatchRepository(dbCon.DB)
+	scheduleRepo := repository.NewScheduleRepository(dbCon.DB)
+	webhookRepo := repository.NewWebhookRepository(dbCon.DB)
+	calendarRepo := repository.NewCalendarRepository(dbCon.DB)
+	responseCodeRepo := repository.NewResponseCodeRepository(dbCon.DB)
+	walletProviderRepo := repository.NewWalletProviderRepository(dbCon.DB)
 
 	partnerService := service.NewPartnerService(partnerRepo)
 	if err := partnerService.LoadAllBankPartner(ctx); err != nil {
 		loghelper.Logger.WithError(err).Fatal("Failed to load all bank partner configuration to memory")
 	}
 
-	redisService := service.NewRedisService(feeSettingRepo, merchantRepo, redisRepo, redsync)
+	walletProviderService := service.NewWalletProviderService(walletProviderRepo)
+	if err := walletProviderService.LoadWalletProviders(ctx); err != nil {
+		loghelper.Logger.WithError(err).Fatal("Failed to load wallet provider configuration to memory")
+	}
+
+	calendarService := service.NewCalendarService(calendarRepo)
+	if err := calendarService.LoadCalendar(ctx); err != nil {
+		loghelper.Logger.WithError(err).Fatal("Failed to load holiday calendar and channel cutoff to memory")
+	}
+
+	responseCodeService := service.NewResponseCodeService(responseCodeRepo)
+	if err := responseCodeService.LoadResponseCodes(ctx); err != nil {
+		loghelper.Logger.WithError(err).Fatal("Failed to load partner response code mapping to memory")
+	}
+	workers.Go(func() { responseCodeService.Start(workerCtx, cfg.ResponseCodeRefreshInterval) })
+
+	redisService := service.NewRedisService(feeSettingRepo, merchantRepo, redisRepo, redsync, cfg.LockExpiry, cfg.LockTries)
 
 	if err := redisService.LoadFeeSetting(ctx); err != nil {
 		loghelper.Logger.WithError(err).Fatal("Failed to load fee setting to redis")
@@ -89,16 +147,138 @@ func main() {
 		loghelper.Logger.WithError(err).Fatal("Failed to load merchant balance to redis")
 	}
 
-	transferService := service.NewTransferService(recipientRepo, transferRepo, feeSettingRepo, ledgerRepo, balanceRepo, redisService, partnerService, dbCon.DB, kafkaService)
+	transferService := service.NewTransferService(recipientRepo, transferRepo, feeSettingRepo, ledgerRepo, journalRepo, balanceRepo, redisService, partnerService, walletProviderService, calendarService, dbCon.DB, kafkaService)
+
+	virtualAccountService := service.NewVirtualAccountService(partnerService, transferService, cfg.VAInquiryTimeout)
+	walletService := service.NewWalletService(walletProviderService, transferService, cfg.WalletInquiryTimeout)
+
+	depositService := service.NewDepositService(depositRepo, ledgerRepo, journalRepo, balanceRepo, redisService, dbCon.DB)
+	ledgerService := service.NewLedgerService(journalRepo)
+	scheduleService := service.NewScheduleService(scheduleRepo, transferRepo, transferService, calendarService, kafkaService, dbCon.DB, cfg.KafkaNotificationTopic, cfg.ScheduleClaimTimeout)
+	batchService := service.NewBatchService(batchRepo, transferRepo, transferService, redisService, calendarService, scheduleService, cfg.BatchConcurrency, cfg.BatchMaxRows)
+	if err := batchService.ResumeBatches(ctx); err != nil {
+		loghelper.Logger.WithError(err).Error("Failed to resume unfinished transfer batch")
+	}
+	workers.Go(func() { scheduleService.Start(workerCtx, cfg.SchedulerInterval) })
+	reversalService := service.NewReversalService(transferRepo, ledgerRepo, journalRepo, balanceRepo, redisService, dbCon.DB)
+	transferStatusService := service.NewTransferStatusService(transferRepo, webhookRepo, dbCon.DB)
+	webhookService := service.NewWebhookService(webhookRepo, dbCon.DB, cfg.WebhookMaxAttempts, cfg.WebhookBackoffBase)
+	workers.Go(func() { webhookService.Start(workerCtx, cfg.WebhookInterval) })
+	healthService := service.NewHealthService(dbCon.DB, redisClient.Client, kafkaService, partnerService, redisService, cfg.HealthCheckTimeout)
+
+	transferController := controller.NewTransferController(transferService, scheduleService, calendarService)
+	virtualAccountController := controller.NewVirtualAccountController(virtualAccountService)
+	walletController := controller.NewWalletController(walletService)
+	depositController := controller.NewDepositController(depositService)
+	ledgerController := controller.NewLedgerController(ledgerService)
+	reversalController := controller.NewReversalController(reversalService)
+	batchController := controller.NewBatchController(batchService)
+	scheduleController := controller.NewScheduleController(scheduleService)
+	webhookController := controller.NewWebhookController(webhookService)
+	calendarController := controller.NewCalendarController(calendarService)
+	responseCodeController := controller.NewResponseCodeController(responseCodeService)
+	healthController := controller.NewHealthController(healthService)
+
+	// poison message is skipped with error log when there is no dead letter topic
+	var deadLetter kafkahelper.DeadLetterPublisher
+	if cfg.KafkaDeadLetterTopic != "" {
+		deadLetter = kafkaService
+	}
 
-	transferController := controller.NewTransferController(transferService)
+	if cfg.KafkaDepositTopic != "" {
+		depositConsumer, err := kafkahelper.NewKafkaConsumer(kafkaConfig, cfg.KafkaDepositGroup, deadLetter)
+		if err != nil {
+			loghelper.Logger.WithError(err).Fatal("Failed to establish kafka deposit consumer")
+		}
+		consumers = append(consumers, depositConsumer)
+
+		depositHandler := consumer.NewDepositConsumer(depositService)
+		workers.Go(func() {
+			loghelper.Logger.Infof("Deposit consumer is listening on topic %s", cfg.KafkaDepositTopic)
+			if err := depositConsumer.Consume(workerCtx, []string{cfg.KafkaDepositTopic}, depositHandler.Handle); err != nil {
+				loghelper.Logger.WithError(err).Error("Deposit consumer stopped")
+			}
+		})
+	}
+
+	if cfg.KafkaReversalTopic != "" {
+		reversalConsumer, err := kafkahelper.NewKafkaConsumer(kafkaConfig, cfg.KafkaReversalGroup, deadLetter)
+		if err != nil {
+			loghelper.Logger.WithError(err).Fatal("Failed to establish kafka reversal consumer")
+		}
+		consumers = append(consumers, reversalConsumer)
+
+		reversalHandler := consumer.NewReversalConsumer(reversalService)
+		workers.Go(func() {
+			loghelper.Logger.Infof("Reversal consumer is listening on topic %s", cfg.KafkaReversalTopic)
+			if err := reversalConsumer.Consume(workerCtx, []string{cfg.KafkaReversalTopic}, reversalHandler.Handle); err != nil {
+				loghelper.Logger.WithError(err).Error("Reversal consumer stopped")
+			}
+		})
+	}
+
+	if cfg.KafkaStatusTopic != "" {
+		statusConsumer, err := kafkahelper.NewKafkaConsumer(kafkaConfig, cfg.KafkaStatusGroup, deadLetter)
+		if err != nil {
+			loghelper.Logger.WithError(err).Fatal("Failed to establish kafka transfer status consumer")
+		}
+		consumers = append(consumers, statusConsumer)
+
+		statusHandler := consumer.NewTransferStatusConsumer(transferStatusService)
+		workers.Go(func() {
+			loghelper.Logger.Infof("Transfer status consumer is listening on topic %s", cfg.KafkaStatusTopic)
+			if err := statusConsumer.Consume(workerCtx, []string{cfg.KafkaStatusTopic}, statusHandler.Handle); err != nil {
+				loghelper.Logger.WithError(err).Error("Transfer status consumer stopped")
+			}
+		})
+	}
 
 	router := gin.New()
 	router.Use(gin.Recovery())
-	router.Use(RequestLoggerMiddleware())
+
+	// probes registered before tracing, logging and metrics middleware to keep them out of telemetry
+	router.GET("/health", healthController.Live)
+	router.GET("/health/live", healthController.Live)
+	router.GET("/health/ready", healthController.Ready)
+
+	router.Use(otelgin.Middleware(tracehelper.TracerName))
+	router.Use(middleware.RequestLoggerMiddleware(middleware.RequestLogConfig{
+		LogBody: cfg.RequestLogBody, BodyLimit: cfg.RequestLogBodyLimit,
+	}))
+	router.Use(middleware.MetricsMiddleware())
+
+	router.GET("/metrics", gin.WrapH(metrichelper.Handler()))
 
 	api := router.Group("/api/v1")
 	api.POST("/transfer", transferController.Transfer)
+	api.POST("/transfer/va/inquiry", virtualAccountController.Inquiry)
+	api.POST("/transfer/va", virtualAccountController.Payment)
+	api.POST("/transfer/wallet/inquiry", walletController.Inquiry)
+	api.POST("/transfer/wallet", walletController.Topup)
+	api.POST("/transfer/batch", batchController.Submit)
+	api.GET("/transfer/batch/:batchReference", batchController.Status)
+	api.GET("/transfer/batch/:batchReference/result", batchController.Result)
+	api.POST("/transfer/schedule", scheduleController.Schedule)
+	api.GET("/transfer/schedule", scheduleController.List)
+	api.DELETE("/transfer/schedule/:scheduleReference", scheduleController.Cancel)
+
+	internal := router.Group("/internal/v1")
+	internal.Use(middleware.InternalAuthMiddleware(cfg.InternalApiKey))
+	internal.POST("/deposit", depositController.Deposit)
+	internal.GET("/ledger/trial-balance", ledgerController.TrialBalance)
+	internal.GET("/calendar/holidays", calendarController.ListHolidays)
+	internal.GET("/partner/response-codes", responseCodeController.List)
+	internal.GET("/webhook/deliveries", webhookController.Deliveries)
+	internal.GET("/webhook/deliveries/:id", webhookController.Delivery)
+
+	// admin action is recorded against admin identified by own key, on top of internal key
+	adminKeys, _ := cfg.AdminKeys()
+	admin := internal.Group("", middleware.AdminAuthMiddleware(adminKeys))
+	admin.POST("/admin/reversal", reversalController.Reverse)
+	admin.POST("/calendar/holidays", calendarController.AddHoliday)
+	admin.PUT("/partner/response-codes", responseCodeController.Save)
+	admin.PUT("/webhook/endpoint/:merchantCode", webhookController.RegisterEndpoint)
+	admin.POST("/webhook/deliveries/:id/resend", webhookController.Resend)
 
 	server := &http.Server{
 		Addr:    cfg.AppPort,
@@ -107,39 +287,112 @@ func main() {
 
 	go func() {
 		loghelper.Logger.Info("Transfer service is running...")
-		if err := router.Run(cfg.AppPort); err != nil {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			loghelper.Logger.WithError(err).Fatal("Failed to start Transfer Service")
 		}
 	}()
 
 	<-ctx.Done()
 
+	// phases run in dependency order: nothing may publish after producer is closed or query after storage is closed
 	loghelper.Logger.Info("Shutting down apps properly...")
+	healthService.Drain()
+	if cfg.ShutdownPreStopDelay > 0 {
+		loghelper.Logger.Infof("Readiness reports draining, waiting %s before closing http listener", cfg.ShutdownPreStopDelay)
+		time.Sleep(cfg.ShutdownPreStopDelay)
+	}
+	shutdownPhase("http", cfg.ShutdownHttpTimeout, server.Shutdown)
 
-	shutDownctx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancelShutdown()
+	// workers create transfers, they are stopped before transfers are drained
+	shutdownPhase("worker", cfg.ShutdownWorkerTimeout, func(ctx context.Context) error {
+		cancelWorkers()
+		batchErr := batchService.Drain(ctx)
+		workers.Wait()
+
+		errs := []error{batchErr}
+		for _, kafkaConsumer := range consumers {
+			errs = append(errs, kafkaConsumer.Close())
+		}
+		return errors.Join(errs...)
+	})
+
+	shutdownPhase("transfer", cfg.ShutdownTransferTimeout, transferService.Drain)
+	shutdownPhase("kafka", cfg.ShutdownKafkaTimeout, func(ctx context.Context) error {
+		return kafkaService.Close()
+	})
+	shutdownPhase("trace", cfg.ShutdownTraceTimeout, shutdownTracer)
+	shutdownPhase("redis", cfg.ShutdownStorageTimeout, func(ctx context.Context) error {
+		return redisClient.Close()
+	})
+	shutdownPhase("database", cfg.ShutdownStorageTimeout, func(ctx context.Context) error {
+		return dbCon.Close()
+	})
+
+	loghelper.Logger.Info("Transfer service shutdown completed")
+}
 
-	if err := server.Shutdown(shutDownctx); err != nil {
-		loghelper.Logger.WithError(err).Error("Forced shutdown due to timeout")
-	} else {
-		loghelper.Logger.Info("Transfer service shutdown completed")
+// shutdownPhase run one shutdown step bounded by its own timeout, step that overruns is logged and left behind
+func shutdownPhase(name string, timeout time.Duration, step func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- step(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
 	}
 
+	log := loghelper.Logger.WithFields(logrus.Fields{
+		"phase":    name,
+		"duration": time.Since(start).String(),
+	})
+	if err != nil {
+		log.WithError(err).Error("Shutdown phase did not complete cleanly")
+		return
+	}
+	log.Info("Shutdown phase completed")
 }
 
-func RequestLoggerMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		start := time.Now()
+// printEffectiveConfig write merged configuration to stdout and validation problems to stderr
+func printEffectiveConfig(path string) int {
+	cfg, err := config.LoadConfig(path)
+	if cfg != nil {
+		output, marshalErr := cfg.Redacted()
+		if marshalErr != nil {
+			fmt.Fprintln(os.Stderr, marshalErr)
+			return 1
+		}
+		fmt.Print(string(output))
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
 
-		duration := time.Since(start)
-		status := c.Writer.Status()
+func newDBConfig(cfg *config.Config) dbhelper.DBConfig {
+	return dbhelper.DBConfig{
+		Hostname: cfg.DBHost, Port: cfg.DBPort, DBname: cfg.DBName,
+		Username: cfg.DBUsername, Password: cfg.DBPassword, SslMode: cfg.DBSslMode,
+		MaxOpenConns: cfg.DBMaxOpenConns, MaxIdleConns: cfg.DBMaxIdleConns, ConnMaxLifetime: cfg.DBConnMaxLifetime,
+	}
+}
 
-		loghelper.Logger.WithFields(logrus.Fields{
-			"method":   c.Request.Method,
-			"path":     c.FullPath(),
-			"status":   status,
-			"duration": duration.String(),
-			"clientIp": c.ClientIP(),
-		}).Info("Handled request")
+func newKafkaConfig(cfg *config.Config) kafkahelper.KafkaConfig {
+	return kafkahelper.KafkaConfig{
+		Brokers: cfg.KafkaBrokers(), RetryMax: cfg.KafkaRetryMax, RetryBackoff: cfg.KafkaRetryBackoff,
+		ProducerTimeout: cfg.KafkaProducerTimeout, NetTimeout: cfg.KafkaNetTimeout,
+		ProducerMode: cfg.KafkaProducerMode, AsyncBufferSize: cfg.KafkaAsyncBufferSize, DeadLetterTopic: cfg.KafkaDeadLetterTopic,
+		ConsumerMaxAttempts: cfg.KafkaConsumerMaxAttempts, ConsumerRetryBackoff: cfg.KafkaConsumerRetryBackoff,
+		ClientId: cfg.KafkaClientId, SaslMechanism: cfg.KafkaSaslMechanism, SaslUsername: cfg.KafkaSaslUsername, SaslPassword: cfg.KafkaSaslPassword,
+		TlsEnabled: cfg.KafkaTlsEnabled, TlsCaFile: cfg.KafkaTlsCaFile, TlsCertFile: cfg.KafkaTlsCertFile,
+		TlsKeyFile: cfg.KafkaTlsKeyFile, TlsServerName: cfg.KafkaTlsServerName,
 	}
 }